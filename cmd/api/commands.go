@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/database"
+	"khalif-stories/pkg/logger"
+
+)
+
+type command struct {
+	Name  string
+	Usage string
+	Run   func(app *App, args []string) error
+}
+
+var commands = []command{
+	{Name: "serve", Usage: "serve [--skip-migrate]            Apply migrations, seed and start the HTTP server (default)", Run: serve},
+	{Name: "migrate", Usage: "migrate <up|down [n]|status>      Manage schema migrations", Run: func(app *App, args []string) error { return runMigrate(app.DB, args) }},
+	{Name: "seed", Usage: "seed                              Seed initial categories into an empty database", Run: seedCommand},
+	{Name: "reset", Usage: "reset --confirm                   Drop the public schema, re-run migrations and seed", Run: resetCommand},
+	{Name: "reindex", Usage: "reindex                           Rebuild denormalized counters and search data", Run: reindexCommand},
+	{Name: "recommend", Usage: "recommend                         Recompute recommendations for every active user", Run: recommendCommand},
+	{Name: "blobs", Usage: "blobs gc [--dry-run] [--min-age]  Delete stored files no longer referenced by the database", Run: blobsCommand},
+	{Name: "cache", Usage: "cache flush [prefix...]           Remove cached API responses from Redis", Run: cacheCommand},
+	{Name: "package", Usage: "package <export uuid|import> zip  Export or import a story package ZIP", Run: packageCommand},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: server <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintln(w, "  "+c.Usage)
+	}
+}
+
+func seedCommand(app *App, args []string) error {
+	return database.SeedCategories(app.DB)
+}
+
+func resetCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	confirm := fs.Bool("confirm", false, "Confirm dropping every table in the public schema")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*confirm {
+		return fmt.Errorf("reset drops all data, re-run with --confirm")
+	}
+
+	database.ResetSchema(app.DB)
+	logger.Info("Database reset successfully")
+
+	database.RunMigrations(app.DB)
+	if err := database.SeedCategories(app.DB); err != nil {
+		return err
+	}
+
+	return flushCache(context.Background(), app.Cache, nil)
+}
+
+func reindexCommand(app *App, args []string) error {
+	if err := database.RecountSlides(app.DB); err != nil {
+		return err
+	}
+	logger.Info("Slide counters rebuilt")
+
+	if _, err := database.SyncSearchConfig(app.DB, app.Config.SearchLanguage); err != nil {
+		return err
+	}
+	if err := database.RebuildSearchIndex(app.DB); err != nil {
+		return err
+	}
+	logger.Info("Search index rebuilt", zap.String("language", app.Config.SearchLanguage))
+
+	return flushCache(context.Background(), app.Cache, []string{domain.CacheKeyStoryPrefix})
+}
+
+func recommendCommand(app *App, args []string) error {
+	users, err := app.Recommender.GenerateAll(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Recommendations generated", zap.Int("users", users))
+	return nil
+}
+
+func blobsCommand(app *App, args []string) error {
+	if len(args) == 0 || args[0] != "gc" {
+		return fmt.Errorf("usage: blobs gc [--dry-run] [--min-age 1h]")
+	}
+
+	fs := flag.NewFlagSet("blobs gc", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Only list unreferenced files")
+	minAge := fs.Duration("min-age", time.Hour, "Skip files younger than this, uploads may not be saved yet")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	referenced, err := database.ReferencedAssetURLs(app.DB)
+	if err != nil {
+		return err
+	}
+
+	containers := []string{
+		app.Config.AzureContainer,
+		app.Config.AzureContainerStoriesName,
+		app.Config.AzureContainerChapterImages,
+		app.Config.AzureContainerChapterSounds,
+	}
+
+	seen := map[string]bool{}
+	var deleted int
+	for _, container := range containers {
+		if seen[container] {
+			continue
+		}
+		seen[container] = true
+
+		objects, err := app.Storage.ListContainer(ctx, container)
+		if err != nil {
+			return fmt.Errorf("list %s: %w", container, err)
+		}
+
+		for _, obj := range objects {
+			if _, ok := referenced[obj.URL]; ok {
+				continue
+			}
+			if time.Since(obj.ModifiedAt) < *minAge {
+				continue
+			}
+
+			if *dryRun {
+				fmt.Println(obj.URL)
+				continue
+			}
+			if err := app.Storage.DeleteFromContainer(ctx, container, obj.URL); err != nil {
+				logger.Error("Failed to delete blob", zap.String("url", obj.URL), zap.Error(err))
+				continue
+			}
+			deleted++
+		}
+	}
+
+	logger.Info("Blob garbage collection finished", zap.Int("deleted", deleted), zap.Bool("dry_run", *dryRun))
+	return nil
+}
+
+func cacheCommand(app *App, args []string) error {
+	if len(args) == 0 || args[0] != "flush" {
+		return fmt.Errorf("usage: cache flush [prefix...]")
+	}
+	return flushCache(context.Background(), app.Cache, args[1:])
+}
+
+func packageCommand(app *App, args []string) error {
+	ctx := context.Background()
+
+	switch {
+	case len(args) == 3 && args[0] == "export":
+		out, err := os.Create(args[2])
+		if err != nil {
+			return err
+		}
+		if err := app.Packages.Export(ctx, args[1], out); err != nil {
+			out.Close()
+			os.Remove(args[2])
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		logger.Info("Story package exported", zap.String("story", args[1]), zap.String("file", args[2]))
+		return nil
+
+	case len(args) == 2 && args[0] == "import":
+		in, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		info, err := in.Stat()
+		if err != nil {
+			return err
+		}
+		res, err := app.Packages.Import(ctx, in, info.Size())
+		if err != nil {
+			return err
+		}
+		logger.Info("Story package imported", zap.String("story", res.Story.UUID), zap.Bool("created", res.Created))
+		return nil
+	}
+	return fmt.Errorf("usage: package export <story-uuid> <file.zip> | package import <file.zip>")
+}
+
+// flushCache menghapus key cache aplikasi. Tanpa prefix, semua prefix cache yang
+// dikenal ikut dihapus, key rate limiter tidak disentuh.
+func flushCache(ctx context.Context, cache domain.RedisRepository, prefixes []string) error {
+	if len(prefixes) == 0 {
+		prefixes = []string{domain.CacheKeyCategoryAll, domain.CacheKeyStoryPrefix}
+	}
+
+	for _, prefix := range prefixes {
+		if err := cache.DeletePrefix(ctx, prefix); err != nil {
+			return err
+		}
+		logger.Info("Cache flushed", zap.String("prefix", strings.TrimSpace(prefix)))
+	}
+	return nil
+}
\ No newline at end of file
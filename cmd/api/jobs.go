@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"khalif-stories/pkg/logger"
+
+)
+
+// startRecommendationJob menghitung ulang rekomendasi semua user secara berkala
+// sampai ctx dibatalkan.
+func startRecommendationJob(ctx context.Context, app *App) {
+	interval := time.Duration(app.Config.RecommendationIntervalMin) * time.Minute
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runRecommendationJob(ctx, app)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runRecommendationJob(ctx context.Context, app *App) {
+	start := time.Now()
+	users, err := app.Recommender.GenerateAll(ctx)
+	if err != nil {
+		// kegagalan per user digabung dengan errors.Join, dicatat satu per satu
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, e := range joined.Unwrap() {
+				logger.Error("Recommendation failed for user", zap.Error(e))
+			}
+		} else {
+			logger.Error("Recommendation job failed", zap.Error(err))
+		}
+	}
+	logger.Info("Recommendation job finished", zap.Int("users", users), zap.Duration("took", time.Since(start)))
+}
+
+// startScheduleJob menjalankan jadwal terbit/tarik story. Semua replika menjalankan
+// ticker ini, tetapi hanya pemegang advisory lock yang benar-benar mengubah data.
+func startScheduleJob(ctx context.Context, app *App) {
+	interval := time.Duration(app.Config.SchedulerIntervalSec) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			applied, err := app.Stories.RunScheduler(ctx, time.Now())
+			if err != nil {
+				logger.Error("Story schedule job failed", zap.Error(err))
+			} else if applied > 0 {
+				logger.Info("Story schedule applied", zap.Int64("stories", applied))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// startImportJob memproses antrean impor massal. Job diambil dengan SKIP LOCKED sehingga
+// aman dijalankan di semua replika.
+func startImportJob(ctx context.Context, app *App) {
+	interval := time.Duration(app.Config.ImportIntervalSec) * time.Second
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			processed, err := app.Imports.RunPending(ctx)
+			if err != nil {
+				logger.Error("Import job failed", zap.Error(err))
+			} else if processed > 0 {
+				logger.Info("Import jobs processed", zap.Int("jobs", processed))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// startJobWorkers menjalankan JobWorkers worker antrean job. Worker tanpa job menunggu
+// JobPollIntervalSec sebelum mencoba lagi, sementara satu ticker mengembalikan job yang
+// worker-nya hilang.
+func startJobWorkers(ctx context.Context, app *App) {
+	poll := time.Duration(app.Config.JobPollIntervalSec) * time.Second
+
+	for i := 0; i < app.Config.JobWorkers; i++ {
+		go func() {
+			for ctx.Err() == nil {
+				found, err := app.Jobs.RunNext(ctx)
+				if err != nil {
+					logger.Error("Background job failed", zap.Error(err))
+				}
+				if found {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(poll):
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			requeued, err := app.Jobs.RequeueStale(ctx)
+			if err != nil {
+				logger.Error("Stale job requeue failed", zap.Error(err))
+			} else if requeued > 0 {
+				logger.Info("Stale jobs requeued", zap.Int64("jobs", requeued))
+			}
+		}
+	}()
+}
\ No newline at end of file
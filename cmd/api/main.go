@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -33,67 +35,135 @@ import (
 // @in header
 // @name Authorization
 type App struct {
-	DB                *gorm.DB
-	RDB               *redis.Client
-	CategoryHandler   *handler.CategoryHandler
-	StoryHandler      *handler.StoryHandler
-	ChapterHandler    *handler.ChapterHandler
-	PreferenceHandler *handler.PreferenceHandler // Ditambahkan
+	Config                *config.Config
+	DB                    *gorm.DB
+	RDB                   *redis.Client
+	Cache                 domain.RedisRepository
+	Storage               domain.StorageRepository
+	Recommender           domain.RecommendationUseCase
+	Stories               domain.StoryUseCase
+	Packages              domain.PackageUseCase
+	Imports               domain.ImportUseCase
+	Jobs                  domain.JobUseCase
+	CategoryHandler       *handler.CategoryHandler
+	StoryHandler          *handler.StoryHandler
+	ChapterHandler        *handler.ChapterHandler
+	PreferenceHandler     *handler.PreferenceHandler
+	HistoryHandler        *handler.HistoryHandler
+	RecommendationHandler *handler.RecommendationHandler
+	SearchHandler         *handler.SearchHandler
+	RevisionHandler       *handler.RevisionHandler
+	CollectionHandler     *handler.CollectionHandler
+	FavouriteHandler      *handler.FavouriteHandler
+	ReviewHandler         *handler.ReviewHandler
+	BundleHandler         *handler.BundleHandler
+	PackageHandler        *handler.PackageHandler
+	ImportHandler         *handler.ImportHandler
+	JobHandler            *handler.JobHandler
 }
 
-// Update NewApp untuk menerima PreferenceHandler
-func NewApp(db *gorm.DB, rdb *redis.Client, ch *handler.CategoryHandler, sh *handler.StoryHandler, chapH *handler.ChapterHandler, ph *handler.PreferenceHandler) *App {
+func NewApp(cfg *config.Config, db *gorm.DB, rdb *redis.Client, cache domain.RedisRepository, storage domain.StorageRepository, recommender domain.RecommendationUseCase, stories domain.StoryUseCase, ch *handler.CategoryHandler, sh *handler.StoryHandler, chapH *handler.ChapterHandler, ph *handler.PreferenceHandler, hh *handler.HistoryHandler, rh *handler.RecommendationHandler, srh *handler.SearchHandler, revh *handler.RevisionHandler, colh *handler.CollectionHandler, fh *handler.FavouriteHandler, revwh *handler.ReviewHandler, bh *handler.BundleHandler, packages domain.PackageUseCase, pkgh *handler.PackageHandler, imports domain.ImportUseCase, imph *handler.ImportHandler, jobs domain.JobUseCase, jh *handler.JobHandler) *App {
 	return &App{
-		DB:                db,
-		RDB:               rdb,
-		CategoryHandler:   ch,
-		StoryHandler:      sh,
-		ChapterHandler:    chapH,
-		PreferenceHandler: ph, // Ditambahkan
+		Config:                cfg,
+		DB:                    db,
+		RDB:                   rdb,
+		Cache:                 cache,
+		Storage:               storage,
+		Recommender:           recommender,
+		Stories:               stories,
+		Packages:              packages,
+		Imports:               imports,
+		Jobs:                  jobs,
+		CategoryHandler:       ch,
+		StoryHandler:          sh,
+		ChapterHandler:        chapH,
+		PreferenceHandler:     ph,
+		HistoryHandler:        hh,
+		RecommendationHandler: rh,
+		SearchHandler:         srh,
+		RevisionHandler:       revh,
+		CollectionHandler:     colh,
+		FavouriteHandler:      fh,
+		ReviewHandler:         revwh,
+		BundleHandler:         bh,
+		PackageHandler:        pkgh,
+		ImportHandler:         imph,
+		JobHandler:            jh,
 	}
 }
 
 func main() {
 	logger.Init()
 
-	refreshFlag := flag.Bool("refresh", false, "Reset Database")
-	flag.Parse()
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		printUsage(os.Stderr)
+		os.Exit(2)
+	}
 
-	cfg := config.LoadConfig()
 	app, err := InitializeApp()
 	if err != nil {
 		logger.Fatal("Failed to initialize app", zap.Error(err))
 	}
 
-	if *refreshFlag {
-		database.ResetSchema(app.DB)
-		logger.Info("Database reset successfully")
+	if err := cmd.Run(app, args); err != nil {
+		logger.Fatal("Command failed", zap.String("command", cmd.Name), zap.Error(err))
+	}
+}
+
+func serve(app *App, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	skipMigrate := fs.Bool("skip-migrate", false, "Do not apply pending migrations on boot")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
 
-	// Menambahkan tabel preference ke AutoMigrate
-	app.DB.AutoMigrate(
-		&domain.Category{},
-		&domain.Story{},
-		&domain.Chapter{},
-		&domain.Slide{},
-		&domain.ListeningHistory{},
-		&domain.Recommendation{},
-		&domain.UserChoiceStory{},  // Baru
-		&domain.UserChoiceDakwah{}, // Baru
-		&domain.UserChoiceHadist{}, // Baru
-	)
+	if *skipMigrate {
+		migrator, err := database.NewMigrator(app.DB)
+		if err != nil {
+			return err
+		}
+		if err := migrator.Verify(context.Background()); err != nil {
+			return err
+		}
+	} else {
+		database.RunMigrations(app.DB)
+	}
 
-	database.RunMigrations(app.DB)
+	changed, err := database.SyncSearchConfig(app.DB, app.Config.SearchLanguage)
+	if err != nil {
+		logger.Error("Search language not applied, keeping current dictionary", zap.Error(err))
+	} else if changed {
+		if err := database.RebuildSearchIndex(app.DB); err != nil {
+			return err
+		}
+		logger.Info("Search index rebuilt", zap.String("language", app.Config.SearchLanguage))
+	}
 
-	database.SeedCategories(app.DB)
+	if err := database.SeedCategories(app.DB); err != nil {
+		return err
+	}
+
+	startRecommendationJob(context.Background(), app)
+	startScheduleJob(context.Background(), app)
+	startImportJob(context.Background(), app)
+	startJobWorkers(context.Background(), app)
 
 	r := gin.New()
 	r.Use(gin.Recovery())
 
-	SetupRoutes(r, app, cfg)
+	SetupRoutes(r, app, app.Config)
 
-	logger.Info("Server starting", zap.String("port", cfg.Port))
-	if err := r.Run(":" + cfg.Port); err != nil {
-		logger.Fatal("Server start failed", zap.Error(err))
-	}
+	logger.Info("Server starting", zap.String("port", app.Config.Port))
+	return r.Run(":" + app.Config.Port)
 }
\ No newline at end of file
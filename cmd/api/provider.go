@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,6 +12,7 @@ import (
 	"gorm.io/gorm/logger"
 
 	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
 	"khalif-stories/pkg/database"
 	"khalif-stories/pkg/utils"
 
@@ -46,10 +49,60 @@ func ProvideRedis(cfg *config.Config) *redis.Client {
 	})
 }
 
-func ProvideAzureUploader(cfg *config.Config) *utils.AzureUploader {
-	uploader, err := utils.NewAzureUploader(cfg.AzureConnStr, cfg.AzureContainer)
+func ProvideStorage(cfg *config.Config) domain.StorageRepository {
+	var (
+		storage domain.StorageRepository
+		err     error
+	)
+
+	switch cfg.StorageDriver {
+	case "local":
+		storage, err = utils.NewLocalUploader(cfg.LocalStoragePath, cfg.LocalStorageURL, cfg.AzureContainer)
+	case "s3":
+		storage, err = utils.NewS3Uploader(cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3Region, cfg.S3UseSSL, cfg.S3PublicURL, cfg.AzureContainer)
+	case "azure":
+		storage, err = utils.NewAzureUploader(cfg.AzureConnStr, cfg.AzureContainer)
+	default:
+		log.Fatalf("unknown STORAGE_DRIVER %q", cfg.StorageDriver)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	return uploader
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	containers := []string{
+		cfg.AzureContainer,
+		cfg.AzureContainerStoriesName,
+		cfg.AzureContainerChapterImages,
+		cfg.AzureContainerChapterSounds,
+	}
+	for _, name := range containers {
+		if err := storage.EnsureContainer(ctx, name); err != nil {
+			log.Printf("Warning: failed to ensure storage container %q: %v", name, err)
+		}
+	}
+
+	return storage
+}
+
+// ProvideContentFilter membuat filter kata untuk ulasan dari REVIEW_BLOCKED_WORDS yang
+// dipisahkan koma. Daftar kosong berarti tidak ada kata yang ditandai.
+func ProvideContentFilter(cfg *config.Config) domain.ContentFilter {
+	return utils.NewKeywordFilter(strings.Split(cfg.ReviewBlockedWords, ","))
+}
+
+// ProvideManifestSigner memuat kunci tanda tangan bundle offline dari BUNDLE_SIGNING_KEY.
+// Tanpa kunci, server memakai kunci sementara sehingga manifest lama tidak lagi
+// terverifikasi setelah restart.
+func ProvideManifestSigner(cfg *config.Config) domain.ManifestSigner {
+	if cfg.BundleSigningKey == "" {
+		log.Println("Warning: BUNDLE_SIGNING_KEY is empty, using an ephemeral bundle signing key")
+	}
+	signer, err := utils.NewEd25519Signer(cfg.BundleSigningKey)
+	if err != nil {
+		log.Fatal("invalid BUNDLE_SIGNING_KEY: ", err)
+	}
+	return signer
 }
\ No newline at end of file
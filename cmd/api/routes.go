@@ -9,6 +9,7 @@ import (
 
 	_ "khalif-stories/docs"
 	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
 	"khalif-stories/pkg/middleware"
 
 )
@@ -16,26 +17,74 @@ import (
 func SetupRoutes(r *gin.Engine, app *App, cfg *config.Config) {
 	r.Use(middleware.Logger())
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	if cfg.StorageDriver == "local" {
+		r.Static("/uploads", cfg.LocalStoragePath)
+	}
 	
 	limiter := middleware.RateLimitConfig{Limit: 300, Window: time.Minute}
 	r.Use(middleware.RateLimit(app.RDB, limiter))
 	
 	auth := middleware.AuthMiddleware(cfg.JWTSecret)
 	admin := middleware.OnlyAdmin()
+	editor := middleware.RequireRole(domain.RoleAdmin, domain.RoleEditor)
+	optionalAuth := middleware.OptionalAuth(cfg.JWTSecret)
 
 	r.GET("/api/categories", app.CategoryHandler.GetAll)
+	r.GET("/api/categories/tree", app.CategoryHandler.Tree)
 	r.GET("/api/categories/:id", app.CategoryHandler.GetOne)
 	r.GET("/api/search/categories", app.CategoryHandler.Search)
-	r.GET("/api/stories", app.StoryHandler.GetAll)
-	r.GET("/api/stories/:uuid", app.StoryHandler.GetOne)
+	r.GET("/api/collections", app.CollectionHandler.GetAll)
+	r.GET("/api/collections/:id", app.CollectionHandler.GetOne)
+	r.GET("/api/stories", optionalAuth, app.StoryHandler.GetAll)
+	r.GET("/api/stories/:uuid", optionalAuth, app.StoryHandler.GetOne)
 	r.GET("/api/search/stories", app.StoryHandler.Search)
+	r.GET("/api/search", app.SearchHandler.Search)
+	r.GET("/api/search/suggest", app.SearchHandler.Suggest)
 	r.GET("/api/chapters/:uuid", app.ChapterHandler.GetOne)
+	r.GET("/api/stories/:uuid/chapters", app.ChapterHandler.ListByStory)
+	r.GET("/api/stories/:uuid/reviews", app.ReviewHandler.ListForStory)
+	r.GET("/api/stories/:uuid/bundle", app.BundleHandler.Story)
+	r.GET("/api/chapters/:uuid/bundle", app.BundleHandler.Chapter)
+	r.GET("/api/bundles/public-key", app.BundleHandler.PublicKey)
 
 	protected := r.Group("/api")
 	protected.Use(auth)
 	{
-		protected.GET("/stories/recommendations", app.StoryHandler.GetRecommendations)
+		protected.GET("/stories/recommendations", app.RecommendationHandler.GetRecommendations)
+		protected.GET("/preferences", app.PreferenceHandler.Get)
 		protected.POST("/preferences", app.PreferenceHandler.Save)
+		protected.PATCH("/preferences", app.PreferenceHandler.Update)
+		protected.POST("/history", app.HistoryHandler.RecordProgress)
+		protected.GET("/history/continue", app.HistoryHandler.ContinueListening)
+		protected.GET("/stories/:uuid/progress", app.HistoryHandler.GetStoryProgress)
+		protected.GET("/favourites", app.FavouriteHandler.List)
+		protected.PUT("/favourites/stories/:uuid", app.FavouriteHandler.AddStory)
+		protected.DELETE("/favourites/stories/:uuid", app.FavouriteHandler.RemoveStory)
+		protected.PUT("/favourites/chapters/:uuid", app.FavouriteHandler.AddChapter)
+		protected.DELETE("/favourites/chapters/:uuid", app.FavouriteHandler.RemoveChapter)
+		protected.GET("/favourites/folders", app.FavouriteHandler.ListFolders)
+		protected.POST("/favourites/folders", app.FavouriteHandler.CreateFolder)
+		protected.PATCH("/favourites/folders/:id", app.FavouriteHandler.RenameFolder)
+		protected.DELETE("/favourites/folders/:id", app.FavouriteHandler.DeleteFolder)
+		protected.PUT("/stories/:uuid/review", app.ReviewHandler.Submit)
+		protected.GET("/stories/:uuid/review", app.ReviewHandler.GetMine)
+		protected.DELETE("/stories/:uuid/review", app.ReviewHandler.Delete)
+	}
+
+	// Editor boleh melihat semua story dan menjalankan transisi status yang diizinkan
+	// domain.StoryTransitions, sisanya tetap khusus admin.
+	editorial := r.Group("/api/admin")
+	editorial.Use(auth, editor)
+	{
+		editorial.GET("/stories", app.StoryHandler.AdminGetAll)
+		editorial.GET("/stories/:uuid", app.StoryHandler.AdminGetOne)
+		editorial.POST("/stories/:uuid/status", app.StoryHandler.Transition)
+		editorial.GET("/stories/:uuid/chapters", app.ChapterHandler.AdminListByStory)
+		editorial.GET("/chapters/:uuid", app.ChapterHandler.AdminGetOne)
+		editorial.GET("/collections/:id", app.CollectionHandler.AdminGetOne)
+		editorial.GET("/reviews", app.ReviewHandler.List)
+		editorial.POST("/reviews/:id/moderation", app.ReviewHandler.Moderate)
 	}
 
 	adm := r.Group("/api/admin")
@@ -44,12 +93,39 @@ func SetupRoutes(r *gin.Engine, app *App, cfg *config.Config) {
 		adm.POST("/categories", app.CategoryHandler.Create)
 		adm.PUT("/categories/:id", app.CategoryHandler.Update)
 		adm.DELETE("/categories/:id", app.CategoryHandler.Delete)
+		adm.POST("/collections", app.CollectionHandler.Create)
+		adm.PUT("/collections/:id", app.CollectionHandler.Update)
+		adm.DELETE("/collections/:id", app.CollectionHandler.Delete)
+		adm.PUT("/collections/:id/stories", app.CollectionHandler.SetStories)
 		adm.POST("/stories", app.StoryHandler.Create)
 		adm.PUT("/stories/:uuid", app.StoryHandler.Update)
 		adm.DELETE("/stories/:uuid", app.StoryHandler.Delete)
 		adm.POST("/stories/:uuid/slides", app.StoryHandler.AddSlide)
+		adm.PATCH("/stories/:uuid/slides/:id", app.StoryHandler.UpdateSlide)
+		adm.DELETE("/stories/:uuid/slides/:id", app.StoryHandler.DeleteSlide)
+		adm.PUT("/stories/:uuid/slides/order", app.StoryHandler.ReorderSlides)
+		adm.PUT("/stories/:uuid/schedule", app.StoryHandler.Schedule)
+		adm.GET("/schedule", app.StoryHandler.ListScheduled)
+		adm.GET("/stories/popular", app.FavouriteHandler.Popular)
+		adm.GET("/stories/:uuid/package", app.PackageHandler.Export)
+		// 1 MB tambahan untuk overhead multipart, ukuran ZIP-nya diperiksa lagi di PackageUC.Import
+		adm.POST("/packages", middleware.MaxBodySize(int64(cfg.PackageMaxSizeMB+1)<<20), app.PackageHandler.Import)
+		adm.POST("/imports", middleware.MaxBodySize(int64(cfg.ImportMaxSizeMB+1)<<20), app.ImportHandler.Submit)
+		adm.GET("/imports/:id", app.ImportHandler.Get)
+		adm.GET("/jobs/dead", app.JobHandler.ListDead)
+		adm.POST("/jobs/dead/:id/retry", app.JobHandler.RetryDead)
+		adm.GET("/jobs/:id", app.JobHandler.Get)
+		adm.GET("/stories/:uuid/revisions", app.RevisionHandler.ListByStory)
+		adm.GET("/revisions/:id", app.RevisionHandler.Get)
+		adm.POST("/revisions/:id/rollback", app.RevisionHandler.Rollback)
+		adm.PUT("/stories/:uuid/chapters/order", app.ChapterHandler.Reorder)
 		adm.POST("/chapters", app.ChapterHandler.Create)
+		adm.PATCH("/chapters/:uuid", app.ChapterHandler.Update)
 		adm.DELETE("/chapters/:uuid", app.ChapterHandler.Delete)
+		adm.POST("/chapters/:uuid/status", app.ChapterHandler.SetStatus)
 		adm.POST("/chapters/:uuid/slides", app.ChapterHandler.AddSlide)
+		adm.PATCH("/chapters/:uuid/slides/:id", app.ChapterHandler.UpdateSlide)
+		adm.DELETE("/chapters/:uuid/slides/:id", app.ChapterHandler.DeleteSlide)
+		adm.PUT("/chapters/:uuid/slides/order", app.ChapterHandler.ReorderSlides)
 	}
 }
\ No newline at end of file
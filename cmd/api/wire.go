@@ -19,33 +19,89 @@ func InitializeApp() (*App, error) {
 		config.LoadConfig,
 		ProvideDB,
 		ProvideRedis,
-		ProvideAzureUploader,
+		ProvideStorage,
+		ProvideContentFilter,
+		ProvideManifestSigner,
 
 		repository.NewCategoryRepository,
 		repository.NewStoryRepository,
 		repository.NewChapterRepository,
 		repository.NewCacheRepository,
 		repository.NewPreferenceRepository,
+		repository.NewHistoryRepository,
+		repository.NewRecommendationRepository,
+		repository.NewRevisionRepository,
+		repository.NewCollectionRepository,
+		repository.NewFavouriteRepository,
+		repository.NewReviewRepository,
+		repository.NewImportJobRepository,
+		repository.NewJobRepository,
+		repository.NewTxManager,
 
 		wire.Bind(new(domain.CategoryRepository), new(*repository.CategoryRepo)),
 		wire.Bind(new(domain.StoryRepository), new(*repository.StoryRepo)),
 		wire.Bind(new(domain.ChapterRepository), new(*repository.ChapterRepo)),
 		wire.Bind(new(domain.RedisRepository), new(*repository.RedisRepo)),
 		wire.Bind(new(domain.PreferenceRepository), new(*repository.PreferenceRepo)),
+		wire.Bind(new(domain.HistoryRepository), new(*repository.HistoryRepo)),
+		wire.Bind(new(domain.RecommendationRepository), new(*repository.RecommendationRepo)),
+		wire.Bind(new(domain.RevisionRepository), new(*repository.RevisionRepo)),
+		wire.Bind(new(domain.CollectionRepository), new(*repository.CollectionRepo)),
+		wire.Bind(new(domain.FavouriteRepository), new(*repository.FavouriteRepo)),
+		wire.Bind(new(domain.ReviewRepository), new(*repository.ReviewRepo)),
+		wire.Bind(new(domain.ImportJobRepository), new(*repository.ImportJobRepo)),
+		wire.Bind(new(domain.JobRepository), new(*repository.JobRepo)),
+		wire.Bind(new(domain.Transactor), new(*repository.TxManager)),
 
 		usecase.NewCategoryUseCase,
 		usecase.NewStoryUseCase,
 		usecase.NewChapterUseCase,
 		usecase.NewPreferenceUseCase,
+		usecase.NewHistoryUseCase,
+		usecase.NewRecommendationUseCase,
+		usecase.NewSearchUseCase,
+		usecase.NewRevisionUseCase,
+		usecase.NewCollectionUseCase,
+		usecase.NewFavouriteUseCase,
+		usecase.NewReviewUseCase,
+		usecase.NewBundleUseCase,
+		usecase.NewPackageUseCase,
+		usecase.NewImportUseCase,
+		usecase.NewSlideMediaUseCase,
+		usecase.NewStoryPaletteUseCase,
+		usecase.NewImagePaletteUseCase,
+		usecase.NewJobUseCase,
 
 		wire.Bind(new(domain.CategoryUseCase), new(*usecase.CategoryUC)),
 		wire.Bind(new(domain.ChapterUseCase), new(*usecase.ChapterUC)),
 		wire.Bind(new(domain.PreferenceUseCase), new(*usecase.PreferenceUC)),
+		wire.Bind(new(domain.HistoryUseCase), new(*usecase.HistoryUC)),
+		wire.Bind(new(domain.RecommendationUseCase), new(*usecase.RecommendationUC)),
+		wire.Bind(new(domain.SearchUseCase), new(*usecase.SearchUC)),
+		wire.Bind(new(domain.RevisionUseCase), new(*usecase.RevisionUC)),
+		wire.Bind(new(domain.CollectionUseCase), new(*usecase.CollectionUC)),
+		wire.Bind(new(domain.FavouriteUseCase), new(*usecase.FavouriteUC)),
+		wire.Bind(new(domain.ReviewUseCase), new(*usecase.ReviewUC)),
+		wire.Bind(new(domain.BundleUseCase), new(*usecase.BundleUC)),
+		wire.Bind(new(domain.PackageUseCase), new(*usecase.PackageUC)),
+		wire.Bind(new(domain.ImportUseCase), new(*usecase.ImportUC)),
+		wire.Bind(new(domain.JobUseCase), new(*usecase.JobUC)),
 
 		handler.NewCategoryHandler,
 		handler.NewStoryHandler,
 		handler.NewChapterHandler,
 		handler.NewPreferenceHandler,
+		handler.NewHistoryHandler,
+		handler.NewRecommendationHandler,
+		handler.NewSearchHandler,
+		handler.NewRevisionHandler,
+		handler.NewCollectionHandler,
+		handler.NewFavouriteHandler,
+		handler.NewReviewHandler,
+		handler.NewBundleHandler,
+		handler.NewPackageHandler,
+		handler.NewImportHandler,
+		handler.NewJobHandler,
 
 		NewApp,
 	)
@@ -23,20 +23,56 @@ func InitializeApp() (*App, error) {
 	configConfig := config.LoadConfig()
 	db := ProvideDB(configConfig)
 	client := ProvideRedis(configConfig)
-	categoryRepo := repository.NewCategoryRepository(db)
 	redisRepo := repository.NewCacheRepository(client)
-	azureUploader := ProvideAzureUploader(configConfig)
-	categoryUC := usecase.NewCategoryUseCase(categoryRepo, redisRepo, azureUploader)
-	categoryHandler := handler.NewCategoryHandler(categoryUC)
+	storageRepository := ProvideStorage(configConfig)
+	recommendationRepo := repository.NewRecommendationRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	txManager := repository.NewTxManager(db)
+	recommendationUC := usecase.NewRecommendationUseCase(configConfig, recommendationRepo, jobRepo, txManager)
 	storyRepo := repository.NewStoryRepository(db)
-	storyUseCase := usecase.NewStoryUseCase(configConfig, storyRepo, categoryRepo, redisRepo, azureUploader)
-	storyHandler := handler.NewStoryHandler(storyUseCase)
+	categoryRepo := repository.NewCategoryRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	collectionRepo := repository.NewCollectionRepository(db)
+	storyUseCase := usecase.NewStoryUseCase(configConfig, storyRepo, categoryRepo, redisRepo, storageRepository, revisionRepo, collectionRepo, jobRepo, txManager)
+	categoryUC := usecase.NewCategoryUseCase(configConfig, categoryRepo, redisRepo, storageRepository, jobRepo, txManager)
+	categoryHandler := handler.NewCategoryHandler(categoryUC)
+	favouriteRepo := repository.NewFavouriteRepository(db)
 	chapterRepo := repository.NewChapterRepository(db)
-	chapterUC := usecase.NewChapterUseCase(configConfig, chapterRepo, storyRepo, azureUploader)
+	favouriteUC := usecase.NewFavouriteUseCase(favouriteRepo, storyRepo, chapterRepo)
+	storyHandler := handler.NewStoryHandler(storyUseCase, favouriteUC)
+	chapterUC := usecase.NewChapterUseCase(configConfig, chapterRepo, storyRepo, storageRepository, revisionRepo, jobRepo, txManager)
 	chapterHandler := handler.NewChapterHandler(chapterUC)
 	preferenceRepo := repository.NewPreferenceRepository(db)
-	preferenceUC := usecase.NewPreferenceUseCase(preferenceRepo, categoryRepo)
+	preferenceUC := usecase.NewPreferenceUseCase(preferenceRepo, categoryRepo, recommendationUC, configConfig, jobRepo)
 	preferenceHandler := handler.NewPreferenceHandler(preferenceUC)
-	app := NewApp(db, client, categoryHandler, storyHandler, chapterHandler, preferenceHandler)
+	historyRepo := repository.NewHistoryRepository(db)
+	historyUC := usecase.NewHistoryUseCase(historyRepo, storyRepo, chapterRepo)
+	historyHandler := handler.NewHistoryHandler(historyUC)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationUC)
+	searchUC := usecase.NewSearchUseCase(storyRepo, categoryRepo, chapterRepo, redisRepo)
+	searchHandler := handler.NewSearchHandler(searchUC)
+	revisionUC := usecase.NewRevisionUseCase(revisionRepo, storyRepo, chapterRepo, redisRepo, txManager)
+	revisionHandler := handler.NewRevisionHandler(revisionUC)
+	collectionUC := usecase.NewCollectionUseCase(configConfig, collectionRepo, redisRepo, storageRepository, jobRepo, txManager)
+	collectionHandler := handler.NewCollectionHandler(collectionUC)
+	favouriteHandler := handler.NewFavouriteHandler(favouriteUC)
+	reviewRepo := repository.NewReviewRepository(db)
+	contentFilter := ProvideContentFilter(configConfig)
+	reviewUC := usecase.NewReviewUseCase(configConfig, reviewRepo, storyRepo, redisRepo, contentFilter)
+	reviewHandler := handler.NewReviewHandler(reviewUC)
+	manifestSigner := ProvideManifestSigner(configConfig)
+	bundleUC := usecase.NewBundleUseCase(configConfig, storyRepo, chapterRepo, storageRepository, redisRepo, manifestSigner)
+	bundleHandler := handler.NewBundleHandler(bundleUC)
+	packageUC := usecase.NewPackageUseCase(configConfig, storyRepo, chapterRepo, categoryRepo, storageRepository, revisionRepo, redisRepo, txManager)
+	packageHandler := handler.NewPackageHandler(packageUC)
+	importJobRepo := repository.NewImportJobRepository(db)
+	importUC := usecase.NewImportUseCase(configConfig, importJobRepo, storyUseCase, chapterUC, storyRepo, chapterRepo, categoryRepo)
+	importHandler := handler.NewImportHandler(importUC)
+	slideMediaUC := usecase.NewSlideMediaUseCase(configConfig, storyRepo, storageRepository, revisionRepo, redisRepo, txManager)
+	storyPaletteUC := usecase.NewStoryPaletteUseCase(configConfig, storyRepo, storageRepository, revisionRepo, redisRepo, txManager)
+	imagePaletteUC := usecase.NewImagePaletteUseCase(configConfig, categoryRepo, collectionRepo, storageRepository, redisRepo)
+	jobUC := usecase.NewJobUseCase(configConfig, jobRepo, slideMediaUC, storyPaletteUC, imagePaletteUC, recommendationUC)
+	jobHandler := handler.NewJobHandler(jobUC)
+	app := NewApp(configConfig, db, client, redisRepo, storageRepository, recommendationUC, storyUseCase, categoryHandler, storyHandler, chapterHandler, preferenceHandler, historyHandler, recommendationHandler, searchHandler, revisionHandler, collectionHandler, favouriteHandler, reviewHandler, bundleHandler, packageUC, packageHandler, importUC, importHandler, jobUC, jobHandler)
 	return app, nil
 }
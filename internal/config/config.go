@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/spf13/viper"
 
@@ -13,14 +14,42 @@ type Config struct {
 	RedisAddr                   string `mapstructure:"REDIS_ADDR"`
 	Port                        string `mapstructure:"PORT"`
 	JWTSecret                   string `mapstructure:"JWT_SECRET"`
+	StorageDriver               string `mapstructure:"STORAGE_DRIVER"`
 	AzureConnStr                string `mapstructure:"AZURE_STORAGE_CONNECTION_STRING"`
 	AzureContainer              string `mapstructure:"AZURE_CONTAINER_NAME"`
 	AzureContainerStoriesName   string `mapstructure:"AZURE_CONTAINER_STORIES_NAME"`
 	AzureContainerChapterImages string `mapstructure:"AZURE_CONTAINER_CHAPTER_IMAGES"`
 	AzureContainerChapterSounds string `mapstructure:"AZURE_CONTAINER_CHAPTER_SOUNDS"`
+	LocalStoragePath            string `mapstructure:"LOCAL_STORAGE_PATH"`
+	LocalStorageURL             string `mapstructure:"LOCAL_STORAGE_URL"`
+	S3Endpoint                  string `mapstructure:"S3_ENDPOINT"`
+	S3AccessKey                 string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey                 string `mapstructure:"S3_SECRET_KEY"`
+	S3Region                    string `mapstructure:"S3_REGION"`
+	S3UseSSL                    bool   `mapstructure:"S3_USE_SSL"`
+	S3PublicURL                 string `mapstructure:"S3_PUBLIC_URL"`
 	SlideLimit                  int    `mapstructure:"SLIDE_LIMIT"`
+	ChapterSlideLimit           int    `mapstructure:"CHAPTER_SLIDE_LIMIT"`
+	RecommendationIntervalMin   int    `mapstructure:"RECOMMENDATION_INTERVAL_MINUTES"`
+	SchedulerIntervalSec        int    `mapstructure:"SCHEDULER_INTERVAL_SECONDS"`
+	SearchLanguage              string `mapstructure:"SEARCH_LANGUAGE"`
 	StoriesThumbPath            string `mapstructure:"STORIES_THUMB_PATH"`
 	StoriesSlidePath            string `mapstructure:"STORIES_SLIDE_PATH"`
+	ReviewBlockedWords          string `mapstructure:"REVIEW_BLOCKED_WORDS"`
+	ReviewAutoApprove           bool   `mapstructure:"REVIEW_AUTO_APPROVE"`
+	BundleSigningKey            string `mapstructure:"BUNDLE_SIGNING_KEY"`
+	PackageMaxSizeMB            int    `mapstructure:"PACKAGE_MAX_SIZE_MB"`
+	PackageMaxFileMB            int    `mapstructure:"PACKAGE_MAX_FILE_MB"`
+	ImportMaxRows               int    `mapstructure:"IMPORT_MAX_ROWS"`
+	ImportMaxSizeMB             int    `mapstructure:"IMPORT_MAX_SIZE_MB"`
+	ImportMaxFileMB             int    `mapstructure:"IMPORT_MAX_FILE_MB"`
+	ImportIntervalSec           int    `mapstructure:"IMPORT_INTERVAL_SECONDS"`
+	ImportJobTimeoutMin         int    `mapstructure:"IMPORT_JOB_TIMEOUT_MINUTES"`
+	JobWorkers                  int    `mapstructure:"JOB_WORKERS"`
+	JobPollIntervalSec          int    `mapstructure:"JOB_POLL_INTERVAL_SECONDS"`
+	JobMaxAttempts              int    `mapstructure:"JOB_MAX_ATTEMPTS"`
+	JobRetryBaseSec             int    `mapstructure:"JOB_RETRY_BASE_SECONDS"`
+	JobLockTimeoutMin           int    `mapstructure:"JOB_LOCK_TIMEOUT_MINUTES"`
 }
 
 func LoadConfig() *Config {
@@ -54,6 +83,12 @@ func LoadConfig() *Config {
 	if config.JWTSecret == "" {
 		config.JWTSecret = os.Getenv("JWT_SECRET")
 	}
+	if config.StorageDriver == "" {
+		config.StorageDriver = os.Getenv("STORAGE_DRIVER")
+	}
+	if config.StorageDriver == "" {
+		config.StorageDriver = "azure"
+	}
 	if config.AzureConnStr == "" {
 		config.AzureConnStr = os.Getenv("AZURE_STORAGE_CONNECTION_STRING")
 	}
@@ -69,12 +104,165 @@ func LoadConfig() *Config {
 	if config.AzureContainerChapterSounds == "" {
 		config.AzureContainerChapterSounds = os.Getenv("AZURE_CONTAINER_CHAPTER_SOUNDS")
 	}
+	if config.AzureContainer == "" {
+		config.AzureContainer = "media"
+	}
+	if config.AzureContainerStoriesName == "" {
+		config.AzureContainerStoriesName = "stories"
+	}
+	if config.AzureContainerChapterImages == "" {
+		config.AzureContainerChapterImages = "chapter-images"
+	}
+	if config.AzureContainerChapterSounds == "" {
+		config.AzureContainerChapterSounds = "chapter-sounds"
+	}
+	if config.LocalStoragePath == "" {
+		config.LocalStoragePath = os.Getenv("LOCAL_STORAGE_PATH")
+	}
+	if config.LocalStoragePath == "" {
+		config.LocalStoragePath = "uploads"
+	}
+	if config.LocalStorageURL == "" {
+		config.LocalStorageURL = os.Getenv("LOCAL_STORAGE_URL")
+	}
+	if config.LocalStorageURL == "" {
+		config.LocalStorageURL = "/uploads"
+	}
+	if config.S3Endpoint == "" {
+		config.S3Endpoint = os.Getenv("S3_ENDPOINT")
+	}
+	if config.S3AccessKey == "" {
+		config.S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	}
+	if config.S3SecretKey == "" {
+		config.S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	}
+	if config.S3Region == "" {
+		config.S3Region = os.Getenv("S3_REGION")
+	}
+	if config.S3PublicURL == "" {
+		config.S3PublicURL = os.Getenv("S3_PUBLIC_URL")
+	}
+	if !config.S3UseSSL {
+		config.S3UseSSL = os.Getenv("S3_USE_SSL") == "true"
+	}
+	if config.SlideLimit == 0 {
+		config.SlideLimit, _ = strconv.Atoi(os.Getenv("SLIDE_LIMIT"))
+	}
+	if config.SlideLimit <= 0 {
+		config.SlideLimit = 20
+	}
+	if config.ChapterSlideLimit == 0 {
+		config.ChapterSlideLimit, _ = strconv.Atoi(os.Getenv("CHAPTER_SLIDE_LIMIT"))
+	}
+	if config.ChapterSlideLimit <= 0 {
+		config.ChapterSlideLimit = 20
+	}
+	if config.RecommendationIntervalMin == 0 {
+		config.RecommendationIntervalMin, _ = strconv.Atoi(os.Getenv("RECOMMENDATION_INTERVAL_MINUTES"))
+	}
+	if config.RecommendationIntervalMin <= 0 {
+		config.RecommendationIntervalMin = 360
+	}
+	if config.SchedulerIntervalSec == 0 {
+		config.SchedulerIntervalSec, _ = strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL_SECONDS"))
+	}
+	if config.SchedulerIntervalSec <= 0 {
+		config.SchedulerIntervalSec = 60
+	}
+	if config.SearchLanguage == "" {
+		config.SearchLanguage = os.Getenv("SEARCH_LANGUAGE")
+	}
+	if config.SearchLanguage == "" {
+		config.SearchLanguage = "indonesian"
+	}
 	if config.StoriesThumbPath == "" {
 		config.StoriesThumbPath = "stories/thumbnails/"
 	}
 	if config.StoriesSlidePath == "" {
 		config.StoriesSlidePath = "stories/slides/"
 	}
+	if config.ReviewBlockedWords == "" {
+		config.ReviewBlockedWords = os.Getenv("REVIEW_BLOCKED_WORDS")
+	}
+	if !config.ReviewAutoApprove {
+		config.ReviewAutoApprove = os.Getenv("REVIEW_AUTO_APPROVE") == "true"
+	}
+	if config.BundleSigningKey == "" {
+		config.BundleSigningKey = os.Getenv("BUNDLE_SIGNING_KEY")
+	}
+	if config.PackageMaxSizeMB == 0 {
+		config.PackageMaxSizeMB, _ = strconv.Atoi(os.Getenv("PACKAGE_MAX_SIZE_MB"))
+	}
+	if config.PackageMaxSizeMB <= 0 {
+		config.PackageMaxSizeMB = 512
+	}
+	if config.PackageMaxFileMB == 0 {
+		config.PackageMaxFileMB, _ = strconv.Atoi(os.Getenv("PACKAGE_MAX_FILE_MB"))
+	}
+	if config.PackageMaxFileMB <= 0 {
+		config.PackageMaxFileMB = 100
+	}
+	if config.ImportMaxRows == 0 {
+		config.ImportMaxRows, _ = strconv.Atoi(os.Getenv("IMPORT_MAX_ROWS"))
+	}
+	if config.ImportMaxRows <= 0 {
+		config.ImportMaxRows = 5000
+	}
+	if config.ImportMaxSizeMB == 0 {
+		config.ImportMaxSizeMB, _ = strconv.Atoi(os.Getenv("IMPORT_MAX_SIZE_MB"))
+	}
+	if config.ImportMaxSizeMB <= 0 {
+		config.ImportMaxSizeMB = 64
+	}
+	if config.ImportMaxFileMB == 0 {
+		config.ImportMaxFileMB, _ = strconv.Atoi(os.Getenv("IMPORT_MAX_FILE_MB"))
+	}
+	if config.ImportMaxFileMB <= 0 {
+		config.ImportMaxFileMB = 32
+	}
+	if config.ImportIntervalSec == 0 {
+		config.ImportIntervalSec, _ = strconv.Atoi(os.Getenv("IMPORT_INTERVAL_SECONDS"))
+	}
+	if config.ImportIntervalSec <= 0 {
+		config.ImportIntervalSec = 10
+	}
+	if config.ImportJobTimeoutMin == 0 {
+		config.ImportJobTimeoutMin, _ = strconv.Atoi(os.Getenv("IMPORT_JOB_TIMEOUT_MINUTES"))
+	}
+	if config.ImportJobTimeoutMin <= 0 {
+		config.ImportJobTimeoutMin = 60
+	}
+	if config.JobWorkers == 0 {
+		config.JobWorkers, _ = strconv.Atoi(os.Getenv("JOB_WORKERS"))
+	}
+	if config.JobWorkers <= 0 {
+		config.JobWorkers = 4
+	}
+	if config.JobPollIntervalSec == 0 {
+		config.JobPollIntervalSec, _ = strconv.Atoi(os.Getenv("JOB_POLL_INTERVAL_SECONDS"))
+	}
+	if config.JobPollIntervalSec <= 0 {
+		config.JobPollIntervalSec = 2
+	}
+	if config.JobMaxAttempts == 0 {
+		config.JobMaxAttempts, _ = strconv.Atoi(os.Getenv("JOB_MAX_ATTEMPTS"))
+	}
+	if config.JobMaxAttempts <= 0 {
+		config.JobMaxAttempts = 5
+	}
+	if config.JobRetryBaseSec == 0 {
+		config.JobRetryBaseSec, _ = strconv.Atoi(os.Getenv("JOB_RETRY_BASE_SECONDS"))
+	}
+	if config.JobRetryBaseSec <= 0 {
+		config.JobRetryBaseSec = 15
+	}
+	if config.JobLockTimeoutMin == 0 {
+		config.JobLockTimeoutMin, _ = strconv.Atoi(os.Getenv("JOB_LOCK_TIMEOUT_MINUTES"))
+	}
+	if config.JobLockTimeoutMin <= 0 {
+		config.JobLockTimeoutMin = 15
+	}
 
 	if config.DBUrl == "" {
 		log.Fatal("FATAL: DATABASE_URL is empty. Please check your docker-compose.yml")
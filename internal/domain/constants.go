@@ -1,12 +1,103 @@
 package domain
 
 const (
-	RoleAdmin = "Admin"
-	RoleUser  = "User"
+	RoleAdmin  = "Admin"
+	RoleEditor = "Editor"
+	RoleUser   = "User"
 
-	StatusDraft     = "Draft"
-	StatusPublished = "Published"
+	StatusPendingUpload = "Pending_Upload"
+	StatusDraft         = "Draft"
+	StatusInReview      = "InReview"
+	StatusPublished     = "Published"
+	StatusArchived      = "Archived"
 
-	CacheKeyCategoryAll = "categories:all"
-	CacheKeyStoryPrefix = "stories:"
+	ScheduleActionPublish   = "publish"
+	ScheduleActionUnpublish = "unpublish"
+
+	RevisionEntityStory   = "story"
+	RevisionEntityChapter = "chapter"
+	RevisionEntitySlide   = "slide"
+
+	RevisionActionBaseline = "baseline"
+	RevisionActionUpdate   = "update"
+	RevisionActionRollback = "rollback"
+	RevisionActionImport   = "import"
+
+	MaxPreferenceChoices = 5
+
+	CategoryTypeStory  = "story"
+	CategoryTypeDakwah = "dakwah"
+	CategoryTypeHadist = "hadist"
+
+	CollectionTypeSeries     = "series"
+	CollectionTypeCollection = "collection"
+
+	FavouriteTypeStory   = "story"
+	FavouriteTypeChapter = "chapter"
+
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+	ReviewStatusHidden   = "hidden"
+
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+
+	ImportRowStory   = "story"
+	ImportRowChapter = "chapter"
+	ImportRowSlide   = "slide"
+
+	ImportResultOK      = "ok"
+	ImportResultFailed  = "failed"
+	ImportResultSkipped = "skipped"
+
+	SlideStatusProcessing = "processing"
+	SlideStatusReady      = "ready"
+	SlideStatusFailed     = "failed"
+
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusDead      = "dead"
+
+	JobTypeSlideMedia      = "slide_media"
+	JobTypeStoryPalette    = "story_palette"
+	JobTypeImagePalette    = "image_palette"
+	JobTypeRecommendations = "recommendations"
+
+	PaletteEntityCategory   = "category"
+	PaletteEntityCollection = "collection"
+
+	CacheKeyCategoryAll   = "categories:all"
+	CacheKeyCollectionAll = "collections:all"
+	CacheKeyStoryPrefix   = "stories:"
+	CacheKeySuggestPrefix = "search:suggest:"
+	CacheKeyBundlePrefix  = "bundles:"
+
+	BundleKindStory   = "story"
+	BundleKindChapter = "chapter"
+
+	// BundleSchemaVersion dinaikkan jika struktur manifest berubah tidak kompatibel.
+	BundleSchemaVersion = 1
+	// PackageSchemaVersion berlaku untuk manifest paket story portabel.
+	PackageSchemaVersion = 1
+
+	FilterCategory = "category"
+	FilterStatus   = "status"
+	FilterHasAudio = "has_audio"
+	FilterType     = "type"
+	FilterParent   = "parent"
+	FilterFolder   = "folder"
+	FilterStory    = "story"
+	FilterFlagged  = "flagged"
+
+	SearchTypeStory    = "story"
+	SearchTypeCategory = "category"
+	SearchTypeChapter  = "chapter"
+	SearchTypeSlide    = "slide"
 )
\ No newline at end of file
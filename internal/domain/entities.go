@@ -2,22 +2,63 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"mime/multipart"
 	"time"
 
 )
 
+// Category milik tepat satu section (story, dakwah atau hadist). Sub-kategori hanya
+// satu tingkat: parent harus kategori utama dengan type yang sama.
 type Category struct {
+	ID            uint       `gorm:"primaryKey" json:"-"`
+	UUID          string     `gorm:"type:uuid;uniqueIndex" json:"id"`
+	Name          string     `gorm:"index" json:"name"`
+	Type          string     `gorm:"index;default:story" json:"type"`
+	ParentID      *uint      `gorm:"index" json:"-"`
+	Parent        *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+	Children      []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	ImageURL      string     `json:"image_url"`
+	DominantColor string     `json:"dominant_color"`
+	Stories       []Story    `gorm:"foreignKey:CategoryID" json:"stories,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Collection mengelompokkan story lintas kategori dalam urutan yang ditentukan editor.
+// Collection bertipe series dibaca berurutan, sehingga detail story menampilkan
+// navigasi ke story sebelum dan sesudahnya.
+type Collection struct {
 	ID            uint      `gorm:"primaryKey" json:"-"`
 	UUID          string    `gorm:"type:uuid;uniqueIndex" json:"id"`
-	Name          string    `gorm:"index" json:"name"`
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	Type          string    `gorm:"default:series" json:"type"`
 	ImageURL      string    `json:"image_url"`
 	DominantColor string    `json:"dominant_color"`
-	Stories       []Story   `gorm:"foreignKey:CategoryID" json:"stories,omitempty"`
+	Stories       []Story   `gorm:"-" json:"stories,omitempty"`
 	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+type StoryRef struct {
+	UUID         string `json:"id"`
+	Title        string `json:"title"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// SeriesNavigation adalah posisi sebuah story di dalam satu series, dihitung hanya
+// dari story yang sudah Published.
+type SeriesNavigation struct {
+	CollectionUUID string    `json:"collection_id"`
+	Title          string    `json:"title"`
+	Position       int       `json:"position"`
+	Total          int       `json:"total"`
+	Previous       *StoryRef `json:"previous,omitempty"`
+	Next           *StoryRef `json:"next,omitempty"`
+}
+
 type Story struct {
 	ID            uint      `gorm:"primaryKey" json:"-"`
 	UUID          string    `gorm:"type:uuid;uniqueIndex" json:"id"`
@@ -31,19 +72,36 @@ type Story struct {
 	Slides        []Slide   `gorm:"foreignKey:StoryID" json:"slides,omitempty"`
 	Chapters      []Chapter `gorm:"foreignKey:StoryID" json:"chapters,omitempty"`
 	SlideCount    int       `gorm:"default:0" json:"slide_count"`
-	Status        string    `gorm:"index;default:'Draft'" json:"status"`
-	CreatedAt     time.Time `gorm:"index;autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Status        string     `gorm:"index;default:'Draft'" json:"status"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt   *time.Time `json:"unpublish_at,omitempty"`
+	Series        []SeriesNavigation `gorm:"-" json:"series,omitempty"`
+	Favourited    *bool      `gorm:"-" json:"favourited,omitempty"`
+	RatingAverage float64    `gorm:"->" json:"rating_average"`
+	RatingCount   int        `gorm:"->" json:"rating_count"`
+	CreatedAt     time.Time  `gorm:"index;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// Chapter punya status terbit sendiri (Draft/Published). Chapter hanya terlihat publik
+// jika chapter dan story-nya sama-sama Published. Position unik per story, dimulai dari 1.
 type Chapter struct {
-	ID         uint      `gorm:"primaryKey" json:"-"`
-	UUID       string    `gorm:"type:uuid;uniqueIndex" json:"id"`
-	StoryID    uint      `gorm:"index" json:"story_id"`
-	Slides     []Slide   `gorm:"foreignKey:ChapterID" json:"slides,omitempty"`
-	SlideCount int       `gorm:"default:0" json:"slide_count"`
-	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              uint       `gorm:"primaryKey" json:"-"`
+	UUID            string     `gorm:"type:uuid;uniqueIndex" json:"id"`
+	StoryID         uint       `gorm:"index" json:"story_id"`
+	Title           string     `json:"title"`
+	Position        int        `json:"position"`
+	Synopsis        string     `json:"synopsis"`
+	CoverURL        string     `json:"cover_url"`
+	DurationSeconds int        `json:"duration_seconds"`
+	Status          string     `gorm:"default:Draft" json:"status"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	Slides          []Slide    `gorm:"foreignKey:ChapterID" json:"slides,omitempty"`
+	SlideCount      int        `gorm:"default:0" json:"slide_count"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 type Slide struct {
@@ -54,6 +112,8 @@ type Slide struct {
 	SoundURL  string    `json:"sound_url"`
 	Content   string    `json:"content"`
 	Sequence  int       `gorm:"index" json:"sequence"`
+	Status    string    `gorm:"default:ready" json:"status"`
+	JobID     string    `json:"job_id,omitempty"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -77,37 +137,194 @@ type CategoryRepository interface {
 	Create(ctx context.Context, category *Category) error
 	GetByName(ctx context.Context, name string) (*Category, error)
 	GetByUUID(ctx context.Context, uuid string) (*Category, error)
-	GetAll(ctx context.Context) ([]Category, error)
+	GetByUUIDs(ctx context.Context, uuids []string) ([]Category, error)
+	GetAll(ctx context.Context, q ListQuery) ([]Category, *PageInfo, error)
+	ListByType(ctx context.Context, categoryType string) ([]Category, error)
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	CountReferences(ctx context.Context, id uint) (int64, error)
 	Search(ctx context.Context, query string) ([]Category, error)
+	Suggest(ctx context.Context, query string, limit int) ([]SearchSuggestion, error)
 	Update(ctx context.Context, category *Category) error
 	Delete(ctx context.Context, uuid string) error
 	UpdateColor(ctx context.Context, id uint, color string) error
+	UpdateDominantColor(ctx context.Context, category *Category) error
+}
+
+type CollectionRepository interface {
+	Create(ctx context.Context, c *Collection) error
+	Update(ctx context.Context, c *Collection) error
+	UpdateDominantColor(ctx context.Context, c *Collection) error
+	Delete(ctx context.Context, uuid string) error
+	GetByUUID(ctx context.Context, uuid string) (*Collection, error)
+	GetAll(ctx context.Context, q ListQuery) ([]Collection, *PageInfo, error)
+	ListStories(ctx context.Context, collectionID uint, publishedOnly bool) ([]Story, error)
+	SetStories(ctx context.Context, collectionID uint, storyUUIDs []string) error
+	SeriesForStory(ctx context.Context, storyID uint) ([]SeriesNavigation, error)
 }
 
 type StoryRepository interface {
 	Create(ctx context.Context, s *Story) error
-	GetAll(ctx context.Context, page, limit int, sort string) ([]Story, error)
-	Search(ctx context.Context, query string) ([]Story, error)
+	GetAll(ctx context.Context, q ListQuery) ([]Story, *PageInfo, error)
+	Search(ctx context.Context, params SearchParams) ([]StorySearchHit, *PageInfo, error)
+	Suggest(ctx context.Context, query string, limit int) ([]SearchSuggestion, error)
+	SearchSlides(ctx context.Context, params SearchParams) ([]SearchHit, error)
+	SearchFacets(ctx context.Context, params SearchParams) (*SearchFacets, error)
 	GetByID(ctx context.Context, id uint) (*Story, error)
 	GetByUUID(ctx context.Context, uuid string) (*Story, error)
 	Update(ctx context.Context, s *Story) error
-	GetRecommendations(ctx context.Context, userID string) ([]Recommendation, error)
+	UpdateLifecycle(ctx context.Context, s *Story, from string) error
 	UpdateColor(ctx context.Context, id uint, color string) error
 	Delete(ctx context.Context, uuid string) error
 	CheckDuplicate(ctx context.Context, title, description string) (bool, error)
-	CreateSlide(ctx context.Context, s *Slide) error
+	CreateSlide(ctx context.Context, s *Slide, limit int) error
 	CountSlides(ctx context.Context, storyID uint) (int64, error)
+	GetSlideByID(ctx context.Context, id uint) (*Slide, error)
+	UpdateSlide(ctx context.Context, s *Slide) error
+	UpdateSlideMedia(ctx context.Context, s *Slide) error
+	SetSlideJob(ctx context.Context, s *Slide) error
+	UpdateDominantColor(ctx context.Context, s *Story) error
+	DeleteSlide(ctx context.Context, s *Slide) error
+	ReorderSlides(ctx context.Context, storyID uint, slideIDs []uint) error
+	ListScheduled(ctx context.Context, limit int) ([]ScheduledChange, error)
+	ApplyDueSchedules(ctx context.Context, now time.Time) (int64, error)
+	Import(ctx context.Context, s *Story) (bool, error)
+}
+
+// ScheduledChange adalah satu perubahan status terjadwal. At yang sudah lewat berarti
+// jadwal tertahan, misal story belum lolos review saat publish_at tiba.
+type ScheduledChange struct {
+	StoryUUID string    `json:"story_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Action    string    `json:"action"`
+	At        time.Time `json:"at"`
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListQuery adalah parameter listing yang sudah divalidasi terhadap whitelist endpoint,
+// lihat utils.ParseListQuery. Cursor adalah nilai opaque dari PageInfo sebelumnya.
+type ListQuery struct {
+	Limit     int
+	Sort      []SortField
+	Filters   map[string]string
+	Cursor    string
+	WithTotal bool
+}
+
+// PageInfo adalah meta paginasi cursor yang dikirim bersama hasil listing.
+type PageInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// SearchFilter membatasi hasil pencarian. Rentang tanggal dicocokkan dengan created_at
+// story induk, atau created_at kategori untuk hasil bertipe category.
+type SearchFilter struct {
+	CategoryUUID string
+	HasAudio     *bool
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+}
+
+// SearchParams memakai Cursor (keyset atas skor dan id) jika diisi, selain itu Offset.
+type SearchParams struct {
+	Query  string
+	Filter SearchFilter
+	Cursor string
+	Offset int
+	Limit  int
+}
+
+// StorySearchHit adalah satu hasil pencarian full-text beserta skor relevansi dan
+// cuplikan teks yang sudah di-highlight dengan tag <mark>.
+type StorySearchHit struct {
+	Story    Story   `json:"story"`
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline"`
+	Snippet  string  `json:"snippet"`
+}
+
+// SearchSuggestion adalah satu saran autocomplete, berupa judul story atau nama kategori.
+type SearchSuggestion struct {
+	Type  string  `json:"type"`
+	ID    string  `json:"id"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+// SearchHit adalah satu hasil pencarian gabungan. Type menentukan field mana yang terisi:
+// story dan category membawa objeknya, chapter dan slide membawa UUID story induknya.
+type SearchHit struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet,omitempty"`
+	Score     float64   `json:"score"`
+	StoryID   string    `json:"story_id,omitempty"`
+	ChapterID string    `json:"chapter_id,omitempty"`
+	Sequence  int       `json:"sequence,omitempty"`
+	Story     *Story    `json:"story,omitempty" gorm:"-"`
+	Category  *Category `json:"category,omitempty" gorm:"-"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+type SearchFacets struct {
+	Categories []FacetCount `json:"categories"`
+}
+
+type SearchResult struct {
+	Hits       []SearchHit  `json:"hits"`
+	Facets     SearchFacets `json:"facets"`
+	NextCursor string       `json:"-"`
+	HasMore    bool         `json:"-"`
+}
+
+type SearchUseCase interface {
+	Search(ctx context.Context, query string, filter SearchFilter, cursor string, limit int) (*SearchResult, error)
+	Suggest(ctx context.Context, query string, limit int) ([]SearchSuggestion, error)
 }
 
 type PreferenceRepository interface {
-	SaveStoryChoices(ctx context.Context, choices []UserChoiceStory) error
-	SaveDakwahChoices(ctx context.Context, choices []UserChoiceDakwah) error
-	SaveHadistChoices(ctx context.Context, choices []UserChoiceHadist) error
-	ClearChoices(ctx context.Context, userID string) error
+	GetChoices(ctx context.Context, userID string) (*UserPreferences, error)
+	ReplaceChoices(ctx context.Context, userID string, choices PreferenceChoices) error
+	UpdateChoices(ctx context.Context, userID string, add, remove PreferenceChoices, limit int) error
+}
+
+// PreferenceSelection adalah UUID kategori per section yang dikirim user.
+type PreferenceSelection struct {
+	Story  []string
+	Dakwah []string
+	Hadist []string
+}
+
+// PreferenceChoices adalah ID kategori per section setelah PreferenceSelection divalidasi.
+type PreferenceChoices struct {
+	Story  []uint
+	Dakwah []uint
+	Hadist []uint
+}
+
+type UserPreferences struct {
+	StoryCategories  []Category `json:"story_categories"`
+	DakwahCategories []Category `json:"dakwah_categories"`
+	HadistCategories []Category `json:"hadist_categories"`
 }
 
 type PreferenceUseCase interface {
+	GetPreferences(ctx context.Context, userID string) (*UserPreferences, error)
 	SavePreferences(ctx context.Context, userID string, storyCatIDs, dakwahCatIDs, hadistCatIDs []string) error
+	UpdatePreferences(ctx context.Context, userID string, add, remove PreferenceSelection) (*UserPreferences, error)
 }
 
 type RedisRepository interface {
@@ -118,52 +335,516 @@ type RedisRepository interface {
 }
 
 type StorageRepository interface {
-	Upload(file multipart.File, header *multipart.FileHeader) (string, error)
-	Delete(fileURL string) error
+	Upload(ctx context.Context, file io.Reader, filename string) (string, error)
+	Delete(ctx context.Context, fileURL string) error
+	EnsureContainer(ctx context.Context, containerName string) error
+	UploadToContainer(ctx context.Context, file io.Reader, containerName, filename string) (string, error)
+	DeleteFromContainer(ctx context.Context, containerName, fileURL string) error
+	ListContainer(ctx context.Context, containerName string) ([]StoredObject, error)
+	OpenFromContainer(ctx context.Context, containerName, fileURL string) (io.ReadCloser, error)
+}
+
+type StoredObject struct {
+	URL        string
+	ModifiedAt time.Time
 }
 
 type CategoryUseCase interface {
-	Create(ctx context.Context, name string, file multipart.File, header *multipart.FileHeader) (*Category, error)
-	GetAll(ctx context.Context) ([]Category, error)
+	Create(ctx context.Context, name, categoryType, parentUUID string, file multipart.File, header *multipart.FileHeader) (*Category, error)
+	GetAll(ctx context.Context, q ListQuery) ([]Category, *PageInfo, error)
+	Tree(ctx context.Context, categoryType string) ([]Category, error)
 	Get(ctx context.Context, uuid string) (*Category, error)
 	Search(ctx context.Context, query string) ([]Category, error)
-	Update(ctx context.Context, uuid string, name string, file multipart.File, header *multipart.FileHeader) (*Category, error)
+	Update(ctx context.Context, uuid string, name, categoryType string, parentUUID *string, file multipart.File, header *multipart.FileHeader) (*Category, error)
+	Delete(ctx context.Context, uuid string) error
+}
+
+type CollectionUseCase interface {
+	Create(ctx context.Context, title, description, collectionType string, file multipart.File, header *multipart.FileHeader) (*Collection, error)
+	Update(ctx context.Context, uuid, title, description, collectionType string, file multipart.File, header *multipart.FileHeader) (*Collection, error)
 	Delete(ctx context.Context, uuid string) error
+	GetAll(ctx context.Context, q ListQuery) ([]Collection, *PageInfo, error)
+	Get(ctx context.Context, uuid string) (*Collection, error)
+	AdminGet(ctx context.Context, uuid string) (*Collection, error)
+	SetStories(ctx context.Context, uuid string, storyUUIDs []string) (*Collection, error)
 }
 
 type StoryUseCase interface {
 	Create(ctx context.Context, title, desc string, categoryUUID string, userID string, file multipart.File, header *multipart.FileHeader) (*Story, error)
-	Update(ctx context.Context, storyUUID string, title, desc, categoryUUID, status string, file multipart.File, header *multipart.FileHeader) (*Story, error)
-	GetAll(ctx context.Context, page, limit int, sort string) ([]Story, error)
+	Update(ctx context.Context, storyUUID string, title, desc, categoryUUID string, file multipart.File, header *multipart.FileHeader) (*Story, error)
+	Transition(ctx context.Context, storyUUID, status, role string) (*Story, error)
+	Schedule(ctx context.Context, storyUUID string, publishAt, unpublishAt *time.Time) (*Story, error)
+	ListScheduled(ctx context.Context, limit int) ([]ScheduledChange, error)
+	RunScheduler(ctx context.Context, now time.Time) (int64, error)
+	GetAll(ctx context.Context, q ListQuery) ([]Story, *PageInfo, error)
 	GetByUUID(ctx context.Context, uuid string) (*Story, error)
-	Search(ctx context.Context, query string) ([]Story, error)
-	GetRecommendations(ctx context.Context, userID string) ([]Recommendation, error)
+	GetPublished(ctx context.Context, uuid string) (*Story, error)
+	Search(ctx context.Context, params SearchParams) ([]StorySearchHit, *PageInfo, error)
 	Delete(ctx context.Context, uuid string) error
 	AddSlide(ctx context.Context, storyUUID string, content string, sequence int, file multipart.File, header *multipart.FileHeader) (*Slide, error)
+	UpdateSlide(ctx context.Context, storyUUID string, slideID uint, content *string, file multipart.File, header *multipart.FileHeader) (*Slide, error)
+	DeleteSlide(ctx context.Context, storyUUID string, slideID uint) error
+	ReorderSlides(ctx context.Context, storyUUID string, slideIDs []uint) ([]Slide, error)
 }
 
 type ChapterRepository interface {
 	Create(ctx context.Context, c *Chapter) error
+	Update(ctx context.Context, c *Chapter) error
+	ReorderChapters(ctx context.Context, storyID uint, chapterUUIDs []string) error
 	GetByUUID(ctx context.Context, uuid string) (*Chapter, error)
 	GetAllByStoryID(ctx context.Context, storyID uint) ([]Chapter, error)
+	ListByStoryID(ctx context.Context, storyID uint, q ListQuery) ([]Chapter, *PageInfo, error)
+	Search(ctx context.Context, params SearchParams) ([]SearchHit, error)
 	Delete(ctx context.Context, uuid string) error
-	CreateSlide(ctx context.Context, s *Slide) error
+	CreateSlide(ctx context.Context, s *Slide, limit int) error
+	UpdateSlide(ctx context.Context, s *Slide) error
+	DeleteSlide(ctx context.Context, s *Slide) error
+	ReorderSlides(ctx context.Context, chapterID uint, slideIDs []uint) error
 	CountSlides(ctx context.Context, chapterID uint) (int64, error)
 }
 
 type ChapterUseCase interface {
-	Create(ctx context.Context, storyUUID string) (*Chapter, error)
+	Create(ctx context.Context, storyUUID, title, synopsis string, durationSeconds int, cover multipart.File, coverHeader *multipart.FileHeader) (*Chapter, error)
+	Update(ctx context.Context, uuid, title, synopsis string, durationSeconds *int, cover multipart.File, coverHeader *multipart.FileHeader) (*Chapter, error)
+	SetStatus(ctx context.Context, uuid, status string) (*Chapter, error)
+	Reorder(ctx context.Context, storyUUID string, chapterUUIDs []string) ([]Chapter, error)
 	GetByUUID(ctx context.Context, uuid string) (*Chapter, error)
+	AdminGetByUUID(ctx context.Context, uuid string) (*Chapter, error)
+	ListByStory(ctx context.Context, storyUUID string, q ListQuery) ([]Chapter, *PageInfo, error)
+	AdminListByStory(ctx context.Context, storyUUID string, q ListQuery) ([]Chapter, *PageInfo, error)
 	Delete(ctx context.Context, uuid string) error
 	AddSlide(ctx context.Context, chapterUUID string, content string, sequence int, imageFile multipart.File, imageHeader *multipart.FileHeader, soundFile multipart.File, soundHeader *multipart.FileHeader) (*Slide, error)
+	UpdateSlide(ctx context.Context, chapterUUID string, slideID uint, content *string, imageFile multipart.File, imageHeader *multipart.FileHeader, soundFile multipart.File, soundHeader *multipart.FileHeader) (*Slide, error)
+	DeleteSlide(ctx context.Context, chapterUUID string, slideID uint) error
+	ReorderSlides(ctx context.Context, chapterUUID string, slideIDs []uint) ([]Slide, error)
 }
 
 type ListeningHistory struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	UserID    string    `gorm:"index" json:"user_id"`
-	StoryID   uint      `gorm:"index" json:"story_id"`
-	Duration  int       `json:"duration"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        string    `gorm:"index" json:"user_id"`
+	StoryID       uint      `gorm:"index" json:"story_id"`
+	Story         *Story    `gorm:"foreignKey:StoryID" json:"story,omitempty"`
+	ChapterID     *uint     `gorm:"index" json:"chapter_id,omitempty"`
+	Chapter       *Chapter  `gorm:"foreignKey:ChapterID" json:"chapter,omitempty"`
+	SlideSequence int       `json:"slide_sequence"`
+	Position      int       `json:"position"`
+	Duration      int       `json:"duration"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ListeningProgress adalah posisi terakhir user pada sebuah story, dipakai untuk
+// fitur "lanjutkan mendengarkan".
+type ListeningProgress struct {
+	Story          Story     `json:"story"`
+	ChapterUUID    string    `json:"chapter_id,omitempty"`
+	SlideSequence  int       `json:"slide_sequence"`
+	Position       int       `json:"position"`
+	SlidesListened int       `json:"slides_listened"`
+	TotalSlides    int       `json:"total_slides"`
+	Percentage     float64   `json:"percentage"`
+	LastListenedAt time.Time `json:"last_listened_at"`
+}
+
+type HistoryRepository interface {
+	Create(ctx context.Context, h *ListeningHistory) error
+	GetLatestPerStory(ctx context.Context, userID string, limit int) ([]ListeningHistory, error)
+	GetLatestForStory(ctx context.Context, userID string, storyID uint) (*ListeningHistory, error)
+}
+
+type HistoryUseCase interface {
+	RecordProgress(ctx context.Context, userID, storyUUID, chapterUUID string, slideSequence, position, duration int) (*ListeningHistory, error)
+	GetContinueListening(ctx context.Context, userID string, limit int) ([]ListeningProgress, error)
+	GetStoryProgress(ctx context.Context, userID, storyUUID string) (*ListeningProgress, error)
+}
+
+// FavouriteFolder adalah folder pribadi untuk mengelompokkan favorit. Menghapus folder
+// tidak menghapus isinya, favoritnya hanya keluar dari folder.
+type FavouriteFolder struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	UUID           string    `gorm:"type:uuid;uniqueIndex" json:"id"`
+	UserID         string    `gorm:"index" json:"-"`
+	Name           string    `json:"name"`
+	FavouriteCount int64     `gorm:"->" json:"favourite_count"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Favourite menyimpan satu story atau satu chapter. StoryID selalu terisi; jika
+// ChapterID kosong berarti yang disimpan adalah story-nya.
+type Favourite struct {
+	ID        uint             `gorm:"primaryKey" json:"-"`
+	UUID      string           `gorm:"type:uuid;uniqueIndex" json:"id"`
+	UserID    string           `gorm:"index" json:"-"`
+	StoryID   uint             `gorm:"index" json:"-"`
+	Story     *Story           `gorm:"foreignKey:StoryID" json:"story,omitempty"`
+	ChapterID *uint            `json:"-"`
+	Chapter   *Chapter         `gorm:"foreignKey:ChapterID" json:"chapter,omitempty"`
+	FolderID  *uint            `gorm:"index" json:"-"`
+	Folder    *FavouriteFolder `gorm:"foreignKey:FolderID" json:"folder,omitempty"`
+	CreatedAt time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// StoryPopularity adalah baris tampilan popularitas admin. Recent dan Listeners dihitung
+// dalam jendela waktu yang diminta, Favourites sepanjang waktu.
+type StoryPopularity struct {
+	Story            Story `json:"story"`
+	Favourites       int64 `json:"favourites"`
+	RecentFavourites int64 `json:"recent_favourites"`
+	Listeners        int64 `json:"listeners"`
+}
+
+type FavouriteRepository interface {
+	Save(ctx context.Context, f *Favourite) error
+	Remove(ctx context.Context, userID string, storyID uint, chapterID *uint) error
+	List(ctx context.Context, userID string, q ListQuery) ([]Favourite, *PageInfo, error)
+	FavouritedStoryIDs(ctx context.Context, userID string, storyIDs []uint) (map[uint]bool, error)
+	CreateFolder(ctx context.Context, f *FavouriteFolder) error
+	UpdateFolder(ctx context.Context, f *FavouriteFolder) error
+	DeleteFolder(ctx context.Context, id uint) error
+	GetFolder(ctx context.Context, userID, uuid string) (*FavouriteFolder, error)
+	ListFolders(ctx context.Context, userID string) ([]FavouriteFolder, error)
+	MostFavourited(ctx context.Context, since time.Time, limit int) ([]StoryPopularity, error)
+}
+
+type FavouriteUseCase interface {
+	AddStory(ctx context.Context, userID, storyUUID, folderUUID string) (*Favourite, error)
+	AddChapter(ctx context.Context, userID, chapterUUID, folderUUID string) (*Favourite, error)
+	RemoveStory(ctx context.Context, userID, storyUUID string) error
+	RemoveChapter(ctx context.Context, userID, chapterUUID string) error
+	List(ctx context.Context, userID string, q ListQuery) ([]Favourite, *PageInfo, error)
+	MarkFavourited(ctx context.Context, userID string, stories []Story) error
+	CreateFolder(ctx context.Context, userID, name string) (*FavouriteFolder, error)
+	RenameFolder(ctx context.Context, userID, folderUUID, name string) (*FavouriteFolder, error)
+	DeleteFolder(ctx context.Context, userID, folderUUID string) error
+	ListFolders(ctx context.Context, userID string) ([]FavouriteFolder, error)
+	Popular(ctx context.Context, since time.Time, limit int) ([]StoryPopularity, error)
+}
+
+// Review adalah rating dan ulasan satu user untuk satu story. Hanya ulasan berstatus
+// approved yang tampil publik dan dihitung ke RatingAverage/RatingCount story, yang
+// diperbarui trigger trg_refresh_story_rating.
+type Review struct {
+	ID               uint       `gorm:"primaryKey" json:"-"`
+	UUID             string     `gorm:"type:uuid;uniqueIndex" json:"id"`
+	StoryID          uint       `gorm:"index" json:"-"`
+	Story            *Story     `gorm:"foreignKey:StoryID" json:"story,omitempty"`
+	UserID           string     `gorm:"index" json:"user_id"`
+	Rating           int        `json:"rating"`
+	Body             string     `json:"body"`
+	Status           string     `gorm:"default:pending" json:"status"`
+	FlaggedTerms     string     `json:"flagged_terms,omitempty"`
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+	ModeratedBy      string     `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ContentFilter memeriksa teks dari user dan mengembalikan kata terlarang yang ditemukan.
+type ContentFilter interface {
+	Match(text string) []string
+}
+
+type ReviewRepository interface {
+	Upsert(ctx context.Context, r *Review) error
+	Update(ctx context.Context, r *Review) error
+	Delete(ctx context.Context, id uint) error
+	GetByUUID(ctx context.Context, uuid string) (*Review, error)
+	GetByUser(ctx context.Context, storyID uint, userID string) (*Review, error)
+	ListByStory(ctx context.Context, storyID uint, q ListQuery) ([]Review, *PageInfo, error)
+	List(ctx context.Context, q ListQuery) ([]Review, *PageInfo, error)
+}
+
+type ReviewUseCase interface {
+	Submit(ctx context.Context, userID, storyUUID string, rating int, body string) (*Review, error)
+	GetMine(ctx context.Context, userID, storyUUID string) (*Review, error)
+	Delete(ctx context.Context, userID, storyUUID string) error
+	ListForStory(ctx context.Context, storyUUID string, q ListQuery) ([]Review, *PageInfo, error)
+	List(ctx context.Context, q ListQuery) ([]Review, *PageInfo, error)
+	Moderate(ctx context.Context, reviewUUID, status, reason string) (*Review, error)
+}
+
+// BundleFile adalah satu file media di paket offline. Path relatif terhadap root ZIP.
+type BundleFile struct {
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	Container   string `json:"-"`
+}
+
+type BundleSlide struct {
+	Sequence int    `json:"sequence"`
+	Content  string `json:"content"`
+	Image    string `json:"image,omitempty"`
+	Sound    string `json:"sound,omitempty"`
+}
+
+type BundleChapter struct {
+	ID              string        `json:"id"`
+	Title           string        `json:"title"`
+	Synopsis        string        `json:"synopsis"`
+	Position        int           `json:"position"`
+	DurationSeconds int           `json:"duration_seconds"`
+	Cover           string        `json:"cover,omitempty"`
+	Slides          []BundleSlide `json:"slides"`
+}
+
+// BundleManifest adalah isi manifest.json paket offline. Version berubah setiap kali
+// isi story/chapter atau file medianya berubah, sehingga app bisa mendeteksi salinan
+// offline yang sudah basi tanpa mengunduh ulang.
+type BundleManifest struct {
+	SchemaVersion int             `json:"schema_version"`
+	Version       string          `json:"version"`
+	Kind          string          `json:"kind"`
+	StoryID       string          `json:"story_id"`
+	Title         string          `json:"title"`
+	Description   string          `json:"description"`
+	Thumbnail     string          `json:"thumbnail,omitempty"`
+	Slides        []BundleSlide   `json:"slides,omitempty"`
+	Chapters      []BundleChapter `json:"chapters,omitempty"`
+	Files         []BundleFile    `json:"files"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+}
+
+// SignedBundle membawa manifest persis seperti yang ditandatangani. Signature dihitung
+// atas byte Manifest apa adanya.
+type SignedBundle struct {
+	Manifest  json.RawMessage `json:"manifest" swaggertype:"object"`
+	Signature string          `json:"signature"`
+	Algorithm string          `json:"algorithm"`
+	Version   string          `json:"version"`
+	Files     []BundleFile    `json:"-"`
+}
+
+// ManifestSigner menandatangani manifest bundle. App memverifikasi dengan PublicKey.
+type ManifestSigner interface {
+	Algorithm() string
+	Sign(payload []byte) string
+	PublicKey() string
+}
+
+type BundleUseCase interface {
+	StoryBundle(ctx context.Context, storyUUID string) (*SignedBundle, error)
+	ChapterBundle(ctx context.Context, chapterUUID string) (*SignedBundle, error)
+	WriteZip(ctx context.Context, bundle *SignedBundle, w io.Writer) error
+	PublicKey() (algorithm, key string)
+}
+
+// StoryPackage adalah manifest.json paket story portabel untuk memindahkan story
+// antar environment. Path media relatif terhadap root ZIP.
+type StoryPackage struct {
+	SchemaVersion int          `json:"schema_version"`
+	ExportedAt    time.Time    `json:"exported_at"`
+	Story         PackageStory `json:"story"`
+}
+
+type PackageCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type PackageSlide struct {
+	Sequence int    `json:"sequence"`
+	Content  string `json:"content"`
+	Image    string `json:"image,omitempty"`
+	Sound    string `json:"sound,omitempty"`
+}
+
+type PackageChapter struct {
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	Synopsis        string         `json:"synopsis"`
+	Position        int            `json:"position"`
+	DurationSeconds int            `json:"duration_seconds"`
+	Cover           string         `json:"cover,omitempty"`
+	Slides          []PackageSlide `json:"slides"`
+}
+
+type PackageStory struct {
+	ID            string           `json:"id"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	DominantColor string           `json:"dominant_color"`
+	Category      PackageCategory  `json:"category"`
+	Thumbnail     string           `json:"thumbnail,omitempty"`
+	Slides        []PackageSlide   `json:"slides"`
+	Chapters      []PackageChapter `json:"chapters"`
+}
+
+type PackageImportResult struct {
+	Story   *Story `json:"story"`
+	Created bool   `json:"created"`
+}
+
+type PackageUseCase interface {
+	Export(ctx context.Context, storyUUID string, w io.Writer) error
+	Import(ctx context.Context, archive io.ReaderAt, size int64) (*PackageImportResult, error)
+}
+
+// ImportRow adalah satu baris file impor massal. Story dan Chapter berisi Ref baris lain
+// di file yang sama atau UUID yang sudah ada. Image dan Sound adalah path di arsip ZIP.
+type ImportRow struct {
+	Type            string `json:"type"`
+	Ref             string `json:"ref,omitempty"`
+	Story           string `json:"story,omitempty"`
+	Chapter         string `json:"chapter,omitempty"`
+	Title           string `json:"title,omitempty"`
+	Description     string `json:"description,omitempty"`
+	Category        string `json:"category,omitempty"`
+	Synopsis        string `json:"synopsis,omitempty"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+	Sequence        int    `json:"sequence,omitempty"`
+	Content         string `json:"content,omitempty"`
+	Image           string `json:"image,omitempty"`
+	Sound           string `json:"sound,omitempty"`
+}
+
+// ImportRowResult adalah hasil satu baris. Row dihitung dari 1 tanpa header CSV, ID
+// berisi UUID story/chapter atau ID slide yang dibuat.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Type   string `json:"type"`
+	Ref    string `json:"ref,omitempty"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportJob adalah impor massal yang diproses worker di latar belakang. Payload dan
+// Archive dikosongkan setelah job selesai, Report tetap disimpan.
+type ImportJob struct {
+	ID            uint         `gorm:"primaryKey" json:"-"`
+	UUID          string       `gorm:"type:uuid;uniqueIndex" json:"id"`
+	UserID        string       `json:"user_id"`
+	Format        string       `json:"format"`
+	DryRun        bool         `json:"dry_run"`
+	Status        string       `gorm:"default:pending" json:"status"`
+	Payload       []byte       `json:"-"`
+	Archive       []byte       `json:"-"`
+	TotalRows     int          `json:"total_rows"`
+	SucceededRows int          `json:"succeeded_rows"`
+	FailedRows    int          `json:"failed_rows"`
+	Report        ImportReport `gorm:"type:jsonb" json:"report"`
+	Error         string       `json:"error,omitempty"`
+	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	StartedAt     *time.Time   `json:"started_at,omitempty"`
+	HeartbeatAt   *time.Time   `json:"-"`
+	FinishedAt    *time.Time   `json:"finished_at,omitempty"`
+}
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, j *ImportJob) error
+	GetByUUID(ctx context.Context, uuid string) (*ImportJob, error)
+	ClaimNext(ctx context.Context) (*ImportJob, error)
+	Heartbeat(ctx context.Context, id uint) error
+	Finish(ctx context.Context, j *ImportJob) error
+	FailStale(ctx context.Context, heartbeatBefore time.Time) (int64, error)
+}
+
+type ImportUseCase interface {
+	Submit(ctx context.Context, format string, data, archive []byte, dryRun bool) (*ImportJob, error)
+	Get(ctx context.Context, uuid string) (*ImportJob, error)
+	RunPending(ctx context.Context) (int, error)
+}
+
+// Job adalah satu tugas di antrean latar belakang. Job yang gagal dijadwalkan ulang
+// dengan backoff sampai MaxAttempts, lalu dicatat di dead_jobs dengan status dead.
+type Job struct {
+	ID          uint       `gorm:"primaryKey" json:"-"`
+	UUID        string     `gorm:"type:uuid;uniqueIndex" json:"id"`
+	Type        string     `json:"type"`
+	Payload     JobPayload `gorm:"type:jsonb" json:"payload" swaggertype:"object"`
+	Status      string     `gorm:"default:pending" json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	RunAt       time.Time  `json:"run_at"`
+	LastError   string     `json:"last_error,omitempty"`
+	LockedAt    *time.Time `json:"-"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// DeadJob adalah salinan job yang kehabisan percobaan, disimpan sampai dijalankan ulang.
+type DeadJob struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	JobID     uint       `json:"-"`
+	Job       *Job       `gorm:"foreignKey:JobID" json:"job,omitempty"`
+	Type      string     `json:"type"`
+	Payload   JobPayload `gorm:"type:jsonb" json:"payload" swaggertype:"object"`
+	Attempts  int        `json:"attempts"`
+	LastError string     `json:"last_error"`
+	FailedAt  time.Time  `gorm:"autoCreateTime" json:"failed_at"`
+}
+
+// StagedMedia adalah file mentah yang sudah diunggah ke folder staging container dan
+// menunggu diproses worker menjadi Target di container yang sama.
+type StagedMedia struct {
+	Container string `json:"container"`
+	URL       string `json:"url"`
+	Filename  string `json:"filename"`
+	Target    string `json:"target"`
+}
+
+// SlideMediaJob adalah payload job JobTypeSlideMedia.
+type SlideMediaJob struct {
+	JobID   string       `json:"job_id,omitempty"`
+	SlideID uint         `json:"slide_id"`
+	StoryID uint         `json:"story_id"`
+	UserID  string       `json:"user_id,omitempty"`
+	Image   *StagedMedia `json:"image,omitempty"`
+	Sound   *StagedMedia `json:"sound,omitempty"`
+}
+
+// StoryPaletteJob adalah payload job JobTypeStoryPalette.
+type StoryPaletteJob struct {
+	StoryUUID    string `json:"story_uuid"`
+	UserID       string `json:"user_id,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// ImagePaletteJob adalah payload job JobTypeImagePalette untuk gambar kategori dan
+// collection. Entity berisi salah satu PaletteEntity*.
+type ImagePaletteJob struct {
+	Entity   string `json:"entity"`
+	UUID     string `json:"uuid"`
+	ImageURL string `json:"image_url"`
+}
+
+// RecommendationJob menghitung ulang rekomendasi satu user yang gagal dihitung langsung.
+type RecommendationJob struct {
+	UserID string `json:"user_id"`
+}
+
+type JobRepository interface {
+	Enqueue(ctx context.Context, j *Job) error
+	GetByUUID(ctx context.Context, uuid string) (*Job, error)
+	Claim(ctx context.Context) (*Job, error)
+	Complete(ctx context.Context, j *Job) error
+	Retry(ctx context.Context, j *Job) error
+	Bury(ctx context.Context, j *Job) error
+	Heartbeat(ctx context.Context, j *Job) error
+	RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, []Job, error)
+	ListDead(ctx context.Context, limit int) ([]DeadJob, error)
+	Revive(ctx context.Context, deadID uint) (*Job, error)
+}
+
+// JobHandler memproses satu tipe job. Handle bisa dipanggil berulang untuk payload yang
+// sama sehingga harus idempoten; Dead dipanggil sekali saat job dipindah ke dead_jobs.
+type JobHandler interface {
+	Handle(ctx context.Context, payload JobPayload) error
+	Dead(ctx context.Context, payload JobPayload, cause error) error
+}
+
+type JobUseCase interface {
+	RunNext(ctx context.Context) (bool, error)
+	RequeueStale(ctx context.Context) (int64, error)
+	Get(ctx context.Context, uuid string) (*Job, error)
+	ListDead(ctx context.Context, limit int) ([]DeadJob, error)
+	Retry(ctx context.Context, deadID uint) (*Job, error)
 }
 
 type Recommendation struct {
@@ -173,4 +854,67 @@ type Recommendation struct {
 	Story     Story     `gorm:"foreignKey:StoryID" json:"story"`
 	Score     float64   `json:"score"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// UserSignals adalah data perilaku user yang dipakai untuk menghitung skor rekomendasi.
+type UserSignals struct {
+	PreferredCategories map[uint]bool
+	CategoryListening   map[uint]int
+	ListenedStories     map[uint]bool
+}
+
+func (s UserSignals) IsEmpty() bool {
+	return len(s.PreferredCategories) == 0 && len(s.CategoryListening) == 0 && len(s.ListenedStories) == 0
+}
+
+// Revision adalah catatan immutable satu perubahan Story, Chapter atau Slide. Snapshot
+// berisi keadaan entity setelah perubahan, Changes berisi selisihnya dari revisi sebelumnya.
+// Revisi "baseline" menyimpan keadaan sebelum perubahan pertama yang tercatat.
+type Revision struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	StoryID    uint           `gorm:"index" json:"-"`
+	EntityType string         `json:"entity_type"`
+	EntityKey  string         `json:"entity_id"`
+	Version    int            `json:"version"`
+	Action     string         `json:"action"`
+	UserID     string         `json:"user_id"`
+	Snapshot   RevisionFields `gorm:"type:jsonb" json:"snapshot"`
+	Changes    RevisionDiff   `gorm:"type:jsonb" json:"changes"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Transactor menjalankan fn dalam satu transaksi database. Repository yang dipanggil
+// dengan ctx milik fn ikut transaksi tersebut.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type RevisionRepository interface {
+	Record(ctx context.Context, rev *Revision, baseline RevisionFields) error
+	ListByStory(ctx context.Context, storyID uint, entityType string, q ListQuery) ([]Revision, *PageInfo, error)
+	GetByID(ctx context.Context, id uint) (*Revision, error)
+}
+
+type RevisionUseCase interface {
+	ListByStory(ctx context.Context, storyUUID, entityType string, q ListQuery) ([]Revision, *PageInfo, error)
+	Get(ctx context.Context, id uint) (*Revision, error)
+	Rollback(ctx context.Context, id uint) (*Revision, error)
+}
+
+type RecommendationRepository interface {
+	GetForUser(ctx context.Context, userID string, limit int) ([]Recommendation, error)
+	ReplaceForUser(ctx context.Context, userID string, recs []Recommendation) error
+	MarkRequested(ctx context.Context, userID string) (bool, error)
+	GetActiveUserIDs(ctx context.Context) ([]string, error)
+	GetUserSignals(ctx context.Context, userID string) (*UserSignals, error)
+	GetCandidateStories(ctx context.Context) ([]Story, error)
+	GetCategoryPopularity(ctx context.Context, since time.Time) (map[uint]int, error)
+	GetStoryFavourites(ctx context.Context) (map[uint]int, error)
+	WithLeaderLock(ctx context.Context, fn func() error) error
+}
+
+type RecommendationUseCase interface {
+	GetForUser(ctx context.Context, userID string) ([]Recommendation, error)
+	GenerateForUser(ctx context.Context, userID string) error
+	GenerateAll(ctx context.Context) (int, error)
 }
\ No newline at end of file
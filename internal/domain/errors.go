@@ -1,10 +1,82 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+)
 
 var (
 	ErrInternalServerError = errors.New("internal server error")
 	ErrNotFound            = errors.New("your requested item is not found")
 	ErrConflict            = errors.New("your item already exists")
 	ErrBadParamInput       = errors.New("given param is not valid")
-)
\ No newline at end of file
+	ErrForbidden           = errors.New("you are not allowed to perform this action")
+	ErrInvalidTransition   = errors.New("status transition is not allowed")
+	ErrSlideLimitReached   = errors.New("slide limit reached")
+	ErrSlideSequenceTaken  = errors.New("slide sequence already used")
+	ErrTooManyChoices      = errors.New("too many categories chosen")
+
+	// ErrCategoryInUse berarti type kategori tidak bisa diganti karena masih dipakai story
+	// atau pilihan user. errors.Is(err, ErrConflict) bernilai true.
+	ErrCategoryInUse = fmt.Errorf("category is still used by stories or user choices: %w", ErrConflict)
+)
+
+// InvalidCategoriesError berisi UUID kategori per section yang tidak dikenal atau bukan
+// milik section tersebut. errors.Is(err, ErrBadParamInput) bernilai true.
+type InvalidCategoriesError struct {
+	Story  []string `json:"story_categories,omitempty"`
+	Dakwah []string `json:"dakwah_categories,omitempty"`
+	Hadist []string `json:"hadist_categories,omitempty"`
+}
+
+func (e *InvalidCategoriesError) Error() string {
+	all := append(append(append([]string{}, e.Story...), e.Dakwah...), e.Hadist...)
+	return "unknown categories: " + strings.Join(all, ", ")
+}
+
+func (e *InvalidCategoriesError) Unwrap() error {
+	return ErrBadParamInput
+}
+
+func (e *InvalidCategoriesError) empty() bool {
+	return len(e.Story) == 0 && len(e.Dakwah) == 0 && len(e.Hadist) == 0
+}
+
+// OrNil mengembalikan nil jika tidak ada UUID yang salah, agar bisa langsung dipakai
+// sebagai nilai error.
+func (e *InvalidCategoriesError) OrNil() error {
+	if e.empty() {
+		return nil
+	}
+	return e
+}
+
+// InvalidPackageError menjelaskan kenapa paket story ditolak. errors.Is(err,
+// ErrBadParamInput) bernilai true.
+type InvalidPackageError struct {
+	Reason string
+}
+
+func (e *InvalidPackageError) Error() string {
+	return "invalid package: " + e.Reason
+}
+
+func (e *InvalidPackageError) Unwrap() error {
+	return ErrBadParamInput
+}
+
+// InvalidImportError menjelaskan kenapa file impor massal ditolak sebelum job dibuat.
+// errors.Is(err, ErrBadParamInput) bernilai true.
+type InvalidImportError struct {
+	Reason string
+}
+
+func (e *InvalidImportError) Error() string {
+	return "invalid import: " + e.Reason
+}
+
+func (e *InvalidImportError) Unwrap() error {
+	return ErrBadParamInput
+}
\ No newline at end of file
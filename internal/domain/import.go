@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// ImportReport adalah hasil per baris sebuah ImportJob, disimpan sebagai jsonb.
+type ImportReport []ImportRowResult
+
+func (r ImportReport) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(r)
+	return string(data), err
+}
+
+func (r *ImportReport) Scan(src interface{}) error {
+	return scanJSON(src, r)
+}
\ No newline at end of file
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// JobPayload adalah JSON mentah payload job, disimpan sebagai jsonb dan ditulis apa
+// adanya di respons API.
+type JobPayload []byte
+
+func (p JobPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "{}", nil
+	}
+	return string(p), nil
+}
+
+func (p *JobPayload) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(JobPayload(nil), v...)
+	case string:
+		*p = JobPayload(v)
+	default:
+		return fmt.Errorf("unsupported jsonb value %T", src)
+	}
+	return nil
+}
+
+func (p JobPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("{}"), nil
+	}
+	return p, nil
+}
+
+func (p *JobPayload) UnmarshalJSON(data []byte) error {
+	*p = append(JobPayload(nil), data...)
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RevisionFields adalah snapshot field sebuah entity, disimpan sebagai jsonb.
+type RevisionFields map[string]interface{}
+
+func (f RevisionFields) Value() (driver.Value, error) {
+	if f == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(f)
+	return string(data), err
+}
+
+func (f *RevisionFields) Scan(src interface{}) error {
+	return scanJSON(src, f)
+}
+
+// FieldChange adalah nilai lama dan baru satu field dalam sebuah revisi.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// RevisionDiff adalah perubahan per field terhadap revisi sebelumnya.
+type RevisionDiff map[string]FieldChange
+
+func (d RevisionDiff) Value() (driver.Value, error) {
+	if d == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(d)
+	return string(data), err
+}
+
+func (d *RevisionDiff) Scan(src interface{}) error {
+	return scanJSON(src, d)
+}
+
+func scanJSON(src interface{}, dst interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	default:
+		return fmt.Errorf("unsupported jsonb value %T", src)
+	}
+}
+
+// DiffFields membandingkan dua snapshot, field yang hilang di salah satu sisi dianggap nil.
+func DiffFields(before, after RevisionFields) RevisionDiff {
+	diff := RevisionDiff{}
+	for key, newValue := range after {
+		if oldValue := before[key]; !reflect.DeepEqual(oldValue, newValue) {
+			diff[key] = FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	for key, oldValue := range before {
+		if _, ok := after[key]; !ok && oldValue != nil {
+			diff[key] = FieldChange{Old: oldValue}
+		}
+	}
+	return diff
+}
+
+type actorKey struct{}
+
+// WithActor menyimpan user id pelaku request di context, diisi oleh middleware auth
+// dan dibaca saat mencatat revisi.
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(actorKey{}).(string)
+	return userID
+}
\ No newline at end of file
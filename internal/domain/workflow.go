@@ -0,0 +1,61 @@
+package domain
+
+// StoryTransition adalah perpindahan status story yang diizinkan beserta role yang boleh
+// menjalankannya. Roles kosong berarti hanya sistem yang boleh (misal setelah upload selesai).
+type StoryTransition struct {
+	From  string
+	To    string
+	Roles []string
+}
+
+// StoryTransitions adalah alur editorial:
+//
+//	Pending_Upload -> Draft -> InReview -> Published -> Archived
+//
+// Editor mengajukan dan menarik review, hanya Admin yang menerbitkan, mengarsipkan
+// dan mengembalikan story arsip ke Draft.
+var StoryTransitions = []StoryTransition{
+	{From: StatusPendingUpload, To: StatusDraft},
+	{From: StatusDraft, To: StatusInReview, Roles: []string{RoleEditor, RoleAdmin}},
+	{From: StatusInReview, To: StatusDraft, Roles: []string{RoleEditor, RoleAdmin}},
+	{From: StatusInReview, To: StatusPublished, Roles: []string{RoleAdmin}},
+	{From: StatusPublished, To: StatusArchived, Roles: []string{RoleAdmin}},
+	{From: StatusArchived, To: StatusDraft, Roles: []string{RoleAdmin}},
+}
+
+// CheckStoryTransition mengembalikan ErrInvalidTransition jika perpindahan tidak ada di
+// StoryTransitions, atau ErrForbidden jika role tidak termasuk yang diizinkan.
+func CheckStoryTransition(from, to, role string) error {
+	for _, t := range StoryTransitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		for _, r := range t.Roles {
+			if r == role {
+				return nil
+			}
+		}
+		return ErrForbidden
+	}
+	return ErrInvalidTransition
+}
+
+// ReviewTransitions adalah alur moderasi ulasan. Ulasan yang sudah tampil hanya bisa
+// disembunyikan, ulasan yang ditolak atau disembunyikan bisa disetujui kembali.
+var ReviewTransitions = map[string][]string{
+	ReviewStatusPending:  {ReviewStatusApproved, ReviewStatusRejected},
+	ReviewStatusApproved: {ReviewStatusHidden},
+	ReviewStatusRejected: {ReviewStatusApproved},
+	ReviewStatusHidden:   {ReviewStatusApproved},
+}
+
+// CheckReviewTransition mengembalikan ErrInvalidTransition jika perpindahan tidak ada di
+// ReviewTransitions.
+func CheckReviewTransition(from, to string) error {
+	for _, allowed := range ReviewTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return ErrInvalidTransition
+}
\ No newline at end of file
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type BundleHandler struct {
+	uc domain.BundleUseCase
+}
+
+func NewBundleHandler(uc domain.BundleUseCase) *BundleHandler {
+	return &BundleHandler{uc: uc}
+}
+
+type BundlePublicKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
+
+func bundleErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// respond mengirim manifest atau ZIP. ETag berisi versi manifest sehingga app bisa
+// memeriksa salinan offline dengan If-None-Match tanpa mengunduh ulang.
+func (h *BundleHandler) respond(c *gin.Context, bundle *domain.SignedBundle, name string) {
+	etag := `"` + bundle.Version + `"`
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	switch c.DefaultQuery("format", "manifest") {
+	case "manifest":
+		utils.SuccessResponse(c, http.StatusOK, bundle)
+	case "zip":
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="`+name+"-"+bundle.Version[:12]+`.zip"`)
+		c.Header("X-Bundle-Signature", bundle.Signature)
+		c.Status(http.StatusOK)
+		// header sudah terkirim, error di tengah stream hanya bisa memutus respons
+		if err := h.uc.WriteZip(c.Request.Context(), bundle, c.Writer); err != nil {
+			_ = c.Error(err)
+			c.Abort()
+		}
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "format must be manifest or zip")
+	}
+}
+
+// GetStoryBundle godoc
+// @Summary      Offline bundle of a story
+// @Description  Signed download manifest of a published story: slide JSON of the story and its published chapters, plus every image and m4a audio file with size and SHA-256. The manifest version changes whenever content or media change; send it back in If-None-Match to check an offline copy. With format=zip the manifest (manifest.json), its signature (manifest.sig) and all media are streamed as one ZIP.
+// @Tags         bundles
+// @Produce      json
+// @Produce      application/zip
+// @Param        uuid           path      string  true   "Story UUID"
+// @Param        format         query     string  false  "manifest (default) or zip"
+// @Param        If-None-Match  header    string  false  "Version from a previous ETag"
+// @Success      200  {object}  domain.SignedBundle
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/bundle [get]
+func (h *BundleHandler) Story(c *gin.Context) {
+	bundle, err := h.uc.StoryBundle(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		bundleErrorResponse(c, err)
+		return
+	}
+	h.respond(c, bundle, "story-"+c.Param("uuid"))
+}
+
+// GetChapterBundle godoc
+// @Summary      Offline bundle of a chapter
+// @Description  Signed download manifest of one published chapter with its slides, cover, images and m4a audio. Works like the story bundle.
+// @Tags         bundles
+// @Produce      json
+// @Produce      application/zip
+// @Param        uuid           path      string  true   "Chapter UUID"
+// @Param        format         query     string  false  "manifest (default) or zip"
+// @Param        If-None-Match  header    string  false  "Version from a previous ETag"
+// @Success      200  {object}  domain.SignedBundle
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /chapters/{uuid}/bundle [get]
+func (h *BundleHandler) Chapter(c *gin.Context) {
+	bundle, err := h.uc.ChapterBundle(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		bundleErrorResponse(c, err)
+		return
+	}
+	h.respond(c, bundle, "chapter-"+c.Param("uuid"))
+}
+
+// GetBundlePublicKey godoc
+// @Summary      Bundle signing key
+// @Description  Public key to verify bundle signatures. The signature covers the exact bytes of the manifest.
+// @Tags         bundles
+// @Produce      json
+// @Success      200  {object}  BundlePublicKeyResponse
+// @Router       /bundles/public-key [get]
+func (h *BundleHandler) PublicKey(c *gin.Context) {
+	algorithm, key := h.uc.PublicKey()
+	utils.SuccessResponse(c, http.StatusOK, BundlePublicKeyResponse{Algorithm: algorithm, PublicKey: key})
+}
\ No newline at end of file
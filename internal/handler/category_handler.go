@@ -22,23 +22,28 @@ func NewCategoryHandler(u domain.CategoryUseCase) *CategoryHandler {
 // --- DTOs ---
 
 type CreateCategoryRequest struct {
-	Name string `form:"name" binding:"required"`
+	Name     string `form:"name" binding:"required"`
+	Type     string `form:"type"`
+	ParentID string `form:"parent_id"`
 }
 
 type UpdateCategoryRequest struct {
 	Name string `form:"name"`
+	Type string `form:"type"`
 }
 
 // --- HANDLERS ---
 
 // CreateCategory godoc
 // @Summary      Create a new category
-// @Description  Create a new category with an image
+// @Description  Create a new category with an image. A sub-category needs a top-level parent of the same type.
 // @Tags         categories
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        name   formData  string  true  "Category Name"
-// @Param        image  formData  file    false "Category Image"
+// @Param        name       formData  string  true  "Category Name"
+// @Param        type       formData  string  false "story (default), dakwah or hadist"
+// @Param        parent_id  formData  string  false "Parent category UUID"
+// @Param        image      formData  file    false "Category Image"
 // @Success      201  {object}  domain.Category
 // @Failure      400  {object}  utils.APIResponse
 // @Failure      409  {object}  utils.APIResponse
@@ -54,12 +59,16 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 
 	file, header, _ := c.Request.FormFile("image")
 	
-	res, err := h.useCase.Create(c.Request.Context(), req.Name, file, header)
+	res, err := h.useCase.Create(c.Request.Context(), req.Name, req.Type, req.ParentID, file, header)
 	if err != nil {
 		if errors.Is(err, domain.ErrConflict) {
 			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid type or parent category")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -68,15 +77,19 @@ func (h *CategoryHandler) Create(c *gin.Context) {
 
 // UpdateCategory godoc
 // @Summary      Update a category
-// @Description  Update category details
+// @Description  Update category details. Send an empty parent_id to turn a sub-category into a top-level category. The type can only change while the category has no parent, no sub-categories and is not used by stories or user choices.
 // @Tags         categories
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        id     path      string  true  "Category UUID"
-// @Param        name   formData  string  false "Category Name"
-// @Param        image  formData  file    false "Category Image"
+// @Param        id         path      string  true  "Category UUID"
+// @Param        name       formData  string  false "Category Name"
+// @Param        type       formData  string  false "story, dakwah or hadist"
+// @Param        parent_id  formData  string  false "Parent category UUID"
+// @Param        image      formData  file    false "Category Image"
 // @Success      200  {object}  domain.Category
+// @Failure      400  {object}  utils.APIResponse
 // @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /admin/categories/{id} [put]
 // @Security     BearerAuth
@@ -87,12 +100,25 @@ func (h *CategoryHandler) Update(c *gin.Context) {
 	uuid := c.Param("id")
 	file, header, _ := c.Request.FormFile("image")
 
-	res, err := h.useCase.Update(c.Request.Context(), uuid, req.Name, file, header)
+	var parentID *string
+	if value, exists := c.GetPostForm("parent_id"); exists {
+		parentID = &value
+	}
+
+	res, err := h.useCase.Update(c.Request.Context(), uuid, req.Name, req.Type, parentID, file, header)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
 			return
 		}
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid type or parent category")
+			return
+		}
+		if errors.Is(err, domain.ErrCategoryInUse) {
+			utils.ErrorResponse(c, http.StatusConflict, "category type cannot change while stories or user choices use it")
+			return
+		}
 		if errors.Is(err, domain.ErrConflict) {
 			utils.ErrorResponse(c, http.StatusConflict, err.Error())
 			return
@@ -126,17 +152,71 @@ func (h *CategoryHandler) Delete(c *gin.Context) {
 	utils.SuccessMessage(c, http.StatusOK, "deleted")
 }
 
+// categoryListSpec adalah whitelist sort dan filter untuk GET /api/categories.
+var categoryListSpec = utils.ListSpec{
+	SortFields: []string{"created_at", "name"},
+	Filters: map[string]utils.FilterRule{
+		domain.FilterType: {Kind: utils.FilterEnum, Values: []string{
+			domain.CategoryTypeStory, domain.CategoryTypeDakwah, domain.CategoryTypeHadist,
+		}},
+		domain.FilterParent: {Kind: utils.FilterUUID},
+	},
+	DefaultSort:  []domain.SortField{{Field: "created_at"}},
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
 // GetAllCategories godoc
 // @Summary      Get all categories
-// @Description  Retrieve all categories
+// @Description  Retrieve categories with cursor pagination
 // @Tags         categories
 // @Produce      json
+// @Param        limit           query     int     false "Limit (max 100)"
+// @Param        sort            query     string  false "created_at or name, prefix with - for descending"
+// @Param        cursor          query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total      query     bool    false "Include total count in meta"
+// @Param        filter[type]    query     string  false "story, dakwah or hadist"
+// @Param        filter[parent]  query     string  false "Parent category UUID, lists its sub-categories"
 // @Success      200  {array}   domain.Category
+// @Failure      400  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /categories [get]
 func (h *CategoryHandler) GetAll(c *gin.Context) {
-	res, err := h.useCase.GetAll(c.Request.Context())
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), categoryListSpec)
 	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, page, err := h.useCase.GetAll(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, res, page)
+}
+
+// CategoryTree godoc
+// @Summary      Get the category tree
+// @Description  Top-level categories with their sub-categories, sorted by name
+// @Tags         categories
+// @Produce      json
+// @Param        type  query     string  false "story, dakwah or hadist; all sections when empty"
+// @Success      200  {array}   domain.Category
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /categories/tree [get]
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	res, err := h.useCase.Tree(c.Request.Context(), c.Query("type"))
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid type")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
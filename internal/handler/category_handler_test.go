@@ -28,7 +28,7 @@ func TestCategoryHandler_GetAll(t *testing.T) {
 			{Name: "Comedy", UUID: "uuid-2"},
 		}
 
-		mockUC.On("GetAll", mock.Anything).Return(expectedCategories, nil)
+		mockUC.On("GetAll", mock.Anything, mock.Anything).Return(expectedCategories, &domain.PageInfo{NextCursor: "next", HasMore: true}, nil)
 
 		r := gin.Default()
 		r.GET("/categories", h.GetAll)
@@ -52,7 +52,7 @@ func TestCategoryHandler_GetAll(t *testing.T) {
 		mockUC := new(mocks.CategoryUseCaseMock)
 		h := handler.NewCategoryHandler(mockUC)
 
-		mockUC.On("GetAll", mock.Anything).Return([]domain.Category{}, nil)
+		mockUC.On("GetAll", mock.Anything, mock.Anything).Return([]domain.Category{}, &domain.PageInfo{}, nil)
 
 		r := gin.Default()
 		r.GET("/categories", h.GetAll)
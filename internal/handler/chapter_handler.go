@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -19,8 +20,24 @@ func NewChapterHandler(uc domain.ChapterUseCase) *ChapterHandler {
 }
 
 type CreateChapterRequest struct {
-	StoryUUID string `form:"story_id" binding:"required"`
-	// Field Title DIHAPUS
+	StoryUUID       string `form:"story_id" binding:"required"`
+	Title           string `form:"title" binding:"required"`
+	Synopsis        string `form:"synopsis"`
+	DurationSeconds int    `form:"duration_seconds"`
+}
+
+type UpdateChapterRequest struct {
+	Title           string `form:"title"`
+	Synopsis        string `form:"synopsis"`
+	DurationSeconds *int   `form:"duration_seconds"`
+}
+
+type ChapterStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type ReorderChaptersRequest struct {
+	ChapterIDs []string `json:"chapter_ids" binding:"required"`
 }
 
 type AddChapterSlideRequest struct {
@@ -30,12 +47,18 @@ type AddChapterSlideRequest struct {
 
 // CreateChapter godoc
 // @Summary      Create a new chapter
-// @Description  Create a chapter for a story (Title inherited from Story)
+// @Description  Create a Draft chapter at the end of the story. The chapter is visible publicly once it and its story are Published.
 // @Tags         chapters
 // @Accept       multipart/form-data
 // @Produce      json
-// @Param        story_id  formData  string  true  "Story UUID"
+// @Param        story_id          formData  string  true  "Story UUID"
+// @Param        title             formData  string  true  "Chapter title"
+// @Param        synopsis          formData  string  false "Synopsis"
+// @Param        duration_seconds  formData  int     false "Estimated duration in seconds"
+// @Param        cover             formData  file    false "Cover image"
 // @Success      201  {object}  domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /admin/chapters [post]
 // @Security     BearerAuth
@@ -46,18 +69,122 @@ func (h *ChapterHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Hanya kirim StoryUUID
-	res, err := h.uc.Create(c.Request.Context(), req.StoryUUID)
+	cover, coverHeader, _ := c.Request.FormFile("cover")
+
+	res, err := h.uc.Create(c.Request.Context(), req.StoryUUID, req.Title, req.Synopsis, req.DurationSeconds, cover, coverHeader)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		chapterErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, http.StatusCreated, res)
 }
 
+// UpdateChapter godoc
+// @Summary      Update chapter metadata
+// @Description  Update title, synopsis, duration and/or cover. Fields that are not sent are kept. The previous version is kept in the revision history.
+// @Tags         chapters
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        uuid              path      string  true  "Chapter UUID"
+// @Param        title             formData  string  false "Chapter title"
+// @Param        synopsis          formData  string  false "Synopsis"
+// @Param        duration_seconds  formData  int     false "Estimated duration in seconds"
+// @Param        cover             formData  file    false "Cover image"
+// @Success      200  {object}  domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid} [patch]
+// @Security     BearerAuth
+func (h *ChapterHandler) Update(c *gin.Context) {
+	var req UpdateChapterRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cover, coverHeader, _ := c.Request.FormFile("cover")
+
+	res, err := h.uc.Update(c.Request.Context(), c.Param("uuid"), req.Title, req.Synopsis, req.DurationSeconds, cover, coverHeader)
+	if err != nil {
+		chapterErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// SetChapterStatus godoc
+// @Summary      Publish or unpublish a chapter
+// @Description  Set the chapter status to Draft or Published. A Published chapter stays hidden while its story is not Published.
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                true  "Chapter UUID"
+// @Param        request  body      ChapterStatusRequest  true  "Draft or Published"
+// @Success      200  {object}  domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid}/status [post]
+// @Security     BearerAuth
+func (h *ChapterHandler) SetStatus(c *gin.Context) {
+	var req ChapterStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.uc.SetStatus(c.Request.Context(), c.Param("uuid"), req.Status)
+	if err != nil {
+		chapterErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// ReorderChapters godoc
+// @Summary      Reorder chapters of a story
+// @Description  Rewrite the position of all chapters of the story in the given order. chapter_ids must contain every chapter UUID of the story exactly once.
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                  true  "Story UUID"
+// @Param        request  body      ReorderChaptersRequest  true  "Chapter UUIDs in the new order"
+// @Success      200  {array}   domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/chapters/order [put]
+// @Security     BearerAuth
+func (h *ChapterHandler) Reorder(c *gin.Context) {
+	var req ReorderChaptersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	chapters, err := h.uc.Reorder(c.Request.Context(), c.Param("uuid"), req.ChapterIDs)
+	if err != nil {
+		chapterErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, chapters)
+}
+
+func chapterErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "chapter not found")
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
 // GetChapter godoc
 // @Summary      Get chapter detail
-// @Description  Get chapter by UUID with all slides
+// @Description  Get a published chapter by UUID with all slides
 // @Tags         chapters
 // @Produce      json
 // @Param        uuid   path      string  true  "Chapter UUID"
@@ -67,15 +194,120 @@ func (h *ChapterHandler) Create(c *gin.Context) {
 func (h *ChapterHandler) GetOne(c *gin.Context) {
 	res, err := h.uc.GetByUUID(c.Request.Context(), c.Param("uuid"))
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		chapterErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, http.StatusOK, res)
 }
 
+// AdminGetChapter godoc
+// @Summary      Get chapter detail (admin)
+// @Description  Get a chapter in any status by UUID with all slides
+// @Tags         chapters
+// @Produce      json
+// @Param        uuid   path      string  true  "Chapter UUID"
+// @Success      200  {object}  domain.Chapter
+// @Failure      404  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid} [get]
+// @Security     BearerAuth
+func (h *ChapterHandler) AdminGetOne(c *gin.Context) {
+	res, err := h.uc.AdminGetByUUID(c.Request.Context(), c.Param("uuid"))
+	if err != nil {
+		chapterErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// chapterListSpec adalah whitelist sort untuk GET /api/stories/:uuid/chapters.
+var chapterListSpec = utils.ListSpec{
+	SortFields:   []string{"position", "created_at"},
+	DefaultSort:  []domain.SortField{{Field: "position"}},
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+var adminChapterListSpec = utils.ListSpec{
+	SortFields: chapterListSpec.SortFields,
+	Filters: map[string]utils.FilterRule{
+		domain.FilterStatus: {Kind: utils.FilterEnum, Values: []string{domain.StatusDraft, domain.StatusPublished}},
+	},
+	DefaultSort:  chapterListSpec.DefaultSort,
+	DefaultLimit: chapterListSpec.DefaultLimit,
+	MaxLimit:     chapterListSpec.MaxLimit,
+}
+
+// ListChapters godoc
+// @Summary      List chapters of a story
+// @Description  Get the published chapters of a published story in reading order, with cursor pagination
+// @Tags         chapters
+// @Produce      json
+// @Param        uuid        path      string  true  "Story UUID"
+// @Param        limit       query     int     false "Limit (max 100)"
+// @Param        cursor      query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total  query     bool    false "Include total count in meta"
+// @Param        sort        query     string  false "position (default) or created_at, prefix with - for descending"
+// @Success      200  {array}   domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/chapters [get]
+func (h *ChapterHandler) ListByStory(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), chapterListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.Filters[domain.FilterStatus] = domain.StatusPublished
+
+	chapters, page, err := h.uc.ListByStory(c.Request.Context(), c.Param("uuid"), q)
+	h.respondList(c, chapters, page, err)
+}
+
+// AdminListChapters godoc
+// @Summary      List chapters of a story (admin)
+// @Description  Get chapters of a story in any status in reading order, with cursor pagination
+// @Tags         chapters
+// @Produce      json
+// @Param        uuid            path      string  true  "Story UUID"
+// @Param        limit           query     int     false "Limit (max 100)"
+// @Param        cursor          query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total      query     bool    false "Include total count in meta"
+// @Param        sort            query     string  false "position (default) or created_at, prefix with - for descending"
+// @Param        filter[status]  query     string  false "Draft or Published"
+// @Success      200  {array}   domain.Chapter
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/chapters [get]
+// @Security     BearerAuth
+func (h *ChapterHandler) AdminListByStory(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), adminChapterListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	chapters, page, err := h.uc.AdminListByStory(c.Request.Context(), c.Param("uuid"), q)
+	h.respondList(c, chapters, page, err)
+}
+
+func (h *ChapterHandler) respondList(c *gin.Context, chapters []domain.Chapter, page *domain.PageInfo, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "story not found")
+		case errors.Is(err, domain.ErrBadParamInput):
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid cursor")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, chapters, page)
+}
+
 // AddSlideToChapter godoc
 // @Summary      Add slide to chapter
-// @Description  Add slide (content, image, sound) to a chapter. Max 20 slides.
+// @Description  Add slide (content, image, sound) to a chapter. The number of slides is limited by CHAPTER_SLIDE_LIMIT.
 // @Tags         chapters
 // @Accept       multipart/form-data
 // @Produce      json
@@ -85,6 +317,9 @@ func (h *ChapterHandler) GetOne(c *gin.Context) {
 // @Param        image    formData  file    false "Slide Image"
 // @Param        sound    formData  file    false "Slide Audio"
 // @Success      201  {object}  domain.Slide
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse  "Sequence already used"
+// @Failure      422  {object}  utils.APIResponse  "Slide limit reached"
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /admin/chapters/{uuid}/slides [post]
 // @Security     BearerAuth
@@ -102,7 +337,7 @@ func (h *ChapterHandler) AddSlide(c *gin.Context) {
 
 	res, err := h.uc.AddSlide(c.Request.Context(), chapterUUID, req.Content, req.Sequence, imageFile, imageHeader, soundFile, soundHeader)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		slideErrorResponse(c, err)
 		return
 	}
 	utils.SuccessResponse(c, http.StatusCreated, res)
@@ -110,17 +345,110 @@ func (h *ChapterHandler) AddSlide(c *gin.Context) {
 
 // DeleteChapter godoc
 // @Summary      Delete chapter
-// @Description  Delete chapter and all its slides/assets
+// @Description  Delete chapter and all its slides, unused media is removed by blobs gc
 // @Tags         chapters
 // @Produce      json
 // @Param        uuid   path      string  true  "Chapter UUID"
 // @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
 // @Router       /admin/chapters/{uuid} [delete]
 // @Security     BearerAuth
 func (h *ChapterHandler) Delete(c *gin.Context) {
 	if err := h.uc.Delete(c.Request.Context(), c.Param("uuid")); err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		chapterErrorResponse(c, err)
 		return
 	}
 	utils.SuccessMessage(c, http.StatusOK, "chapter deleted")
+}
+
+// UpdateChapterSlide godoc
+// @Summary      Update a chapter slide
+// @Description  Replace the text, image and/or sound of a slide. Fields that are not sent are kept. The previous version is kept in the revision history.
+// @Tags         chapters
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        uuid     path      string  true  "Chapter UUID"
+// @Param        id       path      int     true  "Slide ID"
+// @Param        content  formData  string  false "Content Text"
+// @Param        image    formData  file    false "Slide Image"
+// @Param        sound    formData  file    false "Slide Sound"
+// @Success      200  {object}  domain.Slide
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid}/slides/{id} [patch]
+// @Security     BearerAuth
+func (h *ChapterHandler) UpdateSlide(c *gin.Context) {
+	slideID, ok := parseSlideID(c)
+	if !ok {
+		return
+	}
+
+	var content *string
+	if value, exists := c.GetPostForm("content"); exists {
+		content = &value
+	}
+	imageFile, imageHeader, _ := c.Request.FormFile("image")
+	soundFile, soundHeader, _ := c.Request.FormFile("sound")
+
+	slide, err := h.uc.UpdateSlide(c.Request.Context(), c.Param("uuid"), slideID, content, imageFile, imageHeader, soundFile, soundHeader)
+	if err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, slide)
+}
+
+// DeleteChapterSlide godoc
+// @Summary      Delete a chapter slide
+// @Description  Delete a slide with its image and sound
+// @Tags         chapters
+// @Produce      json
+// @Param        uuid  path      string  true  "Chapter UUID"
+// @Param        id    path      int     true  "Slide ID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid}/slides/{id} [delete]
+// @Security     BearerAuth
+func (h *ChapterHandler) DeleteSlide(c *gin.Context) {
+	slideID, ok := parseSlideID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteSlide(c.Request.Context(), c.Param("uuid"), slideID); err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "slide deleted")
+}
+
+// ReorderChapterSlides godoc
+// @Summary      Reorder chapter slides
+// @Description  Rewrite the sequence of all slides of the chapter in the given order. slide_ids must contain every slide of the chapter exactly once.
+// @Tags         chapters
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                true  "Chapter UUID"
+// @Param        request  body      ReorderSlidesRequest  true  "Slide IDs in the new order"
+// @Success      200  {array}   domain.Slide
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/chapters/{uuid}/slides/order [put]
+// @Security     BearerAuth
+func (h *ChapterHandler) ReorderSlides(c *gin.Context) {
+	var req ReorderSlidesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slides, err := h.uc.ReorderSlides(c.Request.Context(), c.Param("uuid"), req.SlideIDs)
+	if err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, slides)
 }
\ No newline at end of file
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type CollectionHandler struct {
+	useCase domain.CollectionUseCase
+}
+
+func NewCollectionHandler(u domain.CollectionUseCase) *CollectionHandler {
+	return &CollectionHandler{useCase: u}
+}
+
+type CreateCollectionRequest struct {
+	Title       string `form:"title" binding:"required"`
+	Description string `form:"description"`
+	Type        string `form:"type"`
+}
+
+type UpdateCollectionRequest struct {
+	Title       string `form:"title"`
+	Description string `form:"description"`
+	Type        string `form:"type"`
+}
+
+type CollectionStoriesRequest struct {
+	StoryIDs []string `json:"story_ids" binding:"required"`
+}
+
+// CreateCollection godoc
+// @Summary      Create a collection or series
+// @Description  Create a curated collection or an ordered series of stories. The dominant colour is taken from the cover image.
+// @Tags         collections
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        title        formData  string  true  "Title"
+// @Param        description  formData  string  false "Description"
+// @Param        type         formData  string  false "series (default) or collection"
+// @Param        image        formData  file    false "Cover image"
+// @Success      201  {object}  domain.Collection
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/collections [post]
+// @Security     BearerAuth
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var req CreateCollectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	file, header, _ := c.Request.FormFile("image")
+
+	res, err := h.useCase.Create(c.Request.Context(), req.Title, req.Description, req.Type, file, header)
+	if err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, res)
+}
+
+// UpdateCollection godoc
+// @Summary      Update a collection
+// @Description  Update collection details. Fields that are not sent are kept.
+// @Tags         collections
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id           path      string  true  "Collection UUID"
+// @Param        title        formData  string  false "Title"
+// @Param        description  formData  string  false "Description"
+// @Param        type         formData  string  false "series or collection"
+// @Param        image        formData  file    false "Cover image"
+// @Success      200  {object}  domain.Collection
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/collections/{id} [put]
+// @Security     BearerAuth
+func (h *CollectionHandler) Update(c *gin.Context) {
+	var req UpdateCollectionRequest
+	_ = c.ShouldBind(&req)
+
+	file, header, _ := c.Request.FormFile("image")
+
+	res, err := h.useCase.Update(c.Request.Context(), c.Param("id"), req.Title, req.Description, req.Type, file, header)
+	if err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// DeleteCollection godoc
+// @Summary      Delete a collection
+// @Description  Delete a collection. The stories in it are not deleted.
+// @Tags         collections
+// @Produce      json
+// @Param        id   path      string  true  "Collection UUID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/collections/{id} [delete]
+// @Security     BearerAuth
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	if err := h.useCase.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "deleted")
+}
+
+// SetCollectionStories godoc
+// @Summary      Set the stories of a collection
+// @Description  Replace the stories of the collection with story_ids, in the given order. A story can appear only once per collection.
+// @Tags         collections
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Collection UUID"
+// @Param        request  body      CollectionStoriesRequest  true  "Story UUIDs in order"
+// @Success      200  {object}  domain.Collection
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/collections/{id}/stories [put]
+// @Security     BearerAuth
+func (h *CollectionHandler) SetStories(c *gin.Context) {
+	var req CollectionStoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.useCase.SetStories(c.Request.Context(), c.Param("id"), req.StoryIDs)
+	if err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// collectionListSpec adalah whitelist sort dan filter untuk GET /api/collections.
+var collectionListSpec = utils.ListSpec{
+	SortFields: []string{"created_at", "title"},
+	Filters: map[string]utils.FilterRule{
+		domain.FilterType: {Kind: utils.FilterEnum, Values: []string{domain.CollectionTypeSeries, domain.CollectionTypeCollection}},
+	},
+	DefaultSort:  []domain.SortField{{Field: "created_at"}},
+	DefaultLimit: 50,
+	MaxLimit:     100,
+}
+
+// GetAllCollections godoc
+// @Summary      Get all collections
+// @Description  Retrieve collections and series with cursor pagination
+// @Tags         collections
+// @Produce      json
+// @Param        limit         query     int     false "Limit (max 100)"
+// @Param        sort          query     string  false "created_at or title, prefix with - for descending"
+// @Param        cursor        query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total    query     bool    false "Include total count in meta"
+// @Param        filter[type]  query     string  false "series or collection"
+// @Success      200  {array}   domain.Collection
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /collections [get]
+func (h *CollectionHandler) GetAll(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), collectionListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, page, err := h.useCase.GetAll(c.Request.Context(), q)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, res, page)
+}
+
+// GetCollection godoc
+// @Summary      Get collection by ID
+// @Description  Retrieve a collection with its published stories in order
+// @Tags         collections
+// @Produce      json
+// @Param        id   path      string  true  "Collection UUID"
+// @Success      200  {object}  domain.Collection
+// @Failure      404  {object}  utils.APIResponse
+// @Router       /collections/{id} [get]
+func (h *CollectionHandler) GetOne(c *gin.Context) {
+	res, err := h.useCase.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// AdminGetCollection godoc
+// @Summary      Get collection by ID (admin)
+// @Description  Retrieve a collection with all its stories in order, in any status
+// @Tags         collections
+// @Produce      json
+// @Param        id   path      string  true  "Collection UUID"
+// @Success      200  {object}  domain.Collection
+// @Failure      404  {object}  utils.APIResponse
+// @Router       /admin/collections/{id} [get]
+// @Security     BearerAuth
+func (h *CollectionHandler) AdminGetOne(c *gin.Context) {
+	res, err := h.useCase.AdminGet(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		collectionErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+func collectionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, "collection not found")
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
\ No newline at end of file
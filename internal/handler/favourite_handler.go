@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type FavouriteHandler struct {
+	uc domain.FavouriteUseCase
+}
+
+func NewFavouriteHandler(uc domain.FavouriteUseCase) *FavouriteHandler {
+	return &FavouriteHandler{uc: uc}
+}
+
+// FavouriteRequest bersifat opsional; tanpa folder_id favorit disimpan di luar folder.
+type FavouriteRequest struct {
+	FolderID string `json:"folder_id"`
+}
+
+type FavouriteFolderRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func favouriteErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		utils.ErrorResponse(c, http.StatusConflict, "folder name already used")
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func bindFavouriteRequest(c *gin.Context) (FavouriteRequest, bool) {
+	var req FavouriteRequest
+	if c.Request.ContentLength == 0 {
+		return req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+// AddStoryFavourite godoc
+// @Summary      Favourite a story
+// @Description  Save a published story to the user's favourites. Calling it again with another folder_id moves the favourite.
+// @Tags         favourites
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string            true   "Story UUID"
+// @Param        request  body      FavouriteRequest  false  "Target folder"
+// @Success      200  {object}  domain.Favourite
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/stories/{uuid} [put]
+// @Security     BearerAuth
+func (h *FavouriteHandler) AddStory(c *gin.Context) {
+	req, ok := bindFavouriteRequest(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.AddStory(c.Request.Context(), c.GetString("user_id"), c.Param("uuid"), req.FolderID)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// RemoveStoryFavourite godoc
+// @Summary      Remove a story from favourites
+// @Description  Remove a story from the user's favourites. Removing a story that is not favourited succeeds.
+// @Tags         favourites
+// @Produce      json
+// @Param        uuid  path      string  true  "Story UUID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/stories/{uuid} [delete]
+// @Security     BearerAuth
+func (h *FavouriteHandler) RemoveStory(c *gin.Context) {
+	if err := h.uc.RemoveStory(c.Request.Context(), c.GetString("user_id"), c.Param("uuid")); err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "removed")
+}
+
+// AddChapterFavourite godoc
+// @Summary      Favourite a chapter
+// @Description  Save a published chapter to the user's favourites. Calling it again with another folder_id moves the favourite.
+// @Tags         favourites
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string            true   "Chapter UUID"
+// @Param        request  body      FavouriteRequest  false  "Target folder"
+// @Success      200  {object}  domain.Favourite
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/chapters/{uuid} [put]
+// @Security     BearerAuth
+func (h *FavouriteHandler) AddChapter(c *gin.Context) {
+	req, ok := bindFavouriteRequest(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.uc.AddChapter(c.Request.Context(), c.GetString("user_id"), c.Param("uuid"), req.FolderID)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// RemoveChapterFavourite godoc
+// @Summary      Remove a chapter from favourites
+// @Description  Remove a chapter from the user's favourites. Removing a chapter that is not favourited succeeds.
+// @Tags         favourites
+// @Produce      json
+// @Param        uuid  path      string  true  "Chapter UUID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/chapters/{uuid} [delete]
+// @Security     BearerAuth
+func (h *FavouriteHandler) RemoveChapter(c *gin.Context) {
+	if err := h.uc.RemoveChapter(c.Request.Context(), c.GetString("user_id"), c.Param("uuid")); err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "removed")
+}
+
+// favouriteListSpec adalah whitelist sort dan filter untuk GET /api/favourites.
+var favouriteListSpec = utils.ListSpec{
+	SortFields: []string{"created_at"},
+	Filters: map[string]utils.FilterRule{
+		domain.FilterType:   {Kind: utils.FilterEnum, Values: []string{domain.FavouriteTypeStory, domain.FavouriteTypeChapter}},
+		domain.FilterFolder: {Kind: utils.FilterUUID},
+	},
+	DefaultSort:  []domain.SortField{{Field: "created_at", Desc: true}},
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// ListFavourites godoc
+// @Summary      List my favourites
+// @Description  Favourited stories and chapters, newest first. Items that are no longer published are hidden until they are published again.
+// @Tags         favourites
+// @Produce      json
+// @Param        limit           query     int     false "Limit (max 100)"
+// @Param        cursor          query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total      query     bool    false "Include total count in meta"
+// @Param        sort            query     string  false "created_at, prefix with - for descending"
+// @Param        filter[type]    query     string  false "story or chapter"
+// @Param        filter[folder]  query     string  false "Folder UUID"
+// @Success      200  {array}   domain.Favourite
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites [get]
+// @Security     BearerAuth
+func (h *FavouriteHandler) List(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), favouriteListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, page, err := h.uc.List(c.Request.Context(), c.GetString("user_id"), q)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, res, page)
+}
+
+// ListFavouriteFolders godoc
+// @Summary      List my favourite folders
+// @Description  Folders of the current user ordered by name, with the number of favourites in each
+// @Tags         favourites
+// @Produce      json
+// @Success      200  {array}   domain.FavouriteFolder
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/folders [get]
+// @Security     BearerAuth
+func (h *FavouriteHandler) ListFolders(c *gin.Context) {
+	res, err := h.uc.ListFolders(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// CreateFavouriteFolder godoc
+// @Summary      Create a favourite folder
+// @Description  Create a folder. Names are unique per user, ignoring case, and at most 50 characters.
+// @Tags         favourites
+// @Accept       json
+// @Produce      json
+// @Param        request  body      FavouriteFolderRequest  true  "Folder name"
+// @Success      201  {object}  domain.FavouriteFolder
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/folders [post]
+// @Security     BearerAuth
+func (h *FavouriteHandler) CreateFolder(c *gin.Context) {
+	var req FavouriteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.uc.CreateFolder(c.Request.Context(), c.GetString("user_id"), req.Name)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, res)
+}
+
+// RenameFavouriteFolder godoc
+// @Summary      Rename a favourite folder
+// @Tags         favourites
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "Folder UUID"
+// @Param        request  body      FavouriteFolderRequest  true  "New name"
+// @Success      200  {object}  domain.FavouriteFolder
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/folders/{id} [patch]
+// @Security     BearerAuth
+func (h *FavouriteHandler) RenameFolder(c *gin.Context) {
+	var req FavouriteFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.uc.RenameFolder(c.Request.Context(), c.GetString("user_id"), c.Param("id"), req.Name)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// DeleteFavouriteFolder godoc
+// @Summary      Delete a favourite folder
+// @Description  Delete a folder. Its favourites are kept and moved out of the folder.
+// @Tags         favourites
+// @Produce      json
+// @Param        id   path      string  true  "Folder UUID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /favourites/folders/{id} [delete]
+// @Security     BearerAuth
+func (h *FavouriteHandler) DeleteFolder(c *gin.Context) {
+	if err := h.uc.DeleteFolder(c.Request.Context(), c.GetString("user_id"), c.Param("id")); err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "deleted")
+}
+
+// PopularStories godoc
+// @Summary      Most favourited stories
+// @Description  Stories ordered by favourites added within the window, then by all-time favourites. Listeners are unique users within the same window.
+// @Tags         favourites
+// @Produce      json
+// @Param        days   query     int  false "Window in days (default 30, max 365)"
+// @Param        limit  query     int  false "Limit (default 20, max 100)"
+// @Success      200  {array}   domain.StoryPopularity
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/popular [get]
+// @Security     BearerAuth
+func (h *FavouriteHandler) Popular(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	res, err := h.uc.Popular(c.Request.Context(), since, limit)
+	if err != nil {
+		favouriteErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
\ No newline at end of file
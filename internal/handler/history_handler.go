@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type HistoryHandler struct {
+	uc domain.HistoryUseCase
+}
+
+func NewHistoryHandler(uc domain.HistoryUseCase) *HistoryHandler {
+	return &HistoryHandler{uc: uc}
+}
+
+type RecordProgressRequest struct {
+	StoryID       string `json:"story_id" binding:"required"`
+	ChapterID     string `json:"chapter_id"`
+	SlideSequence int    `json:"slide_sequence"`
+	Position      int    `json:"position"`
+	Duration      int    `json:"duration"`
+}
+
+// RecordProgress godoc
+// @Summary      Record playback progress
+// @Description  Save the current slide, audio position (seconds) and listened duration (seconds) for a story
+// @Tags         history
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RecordProgressRequest  true  "Playback progress"
+// @Success      201  {object}  domain.ListeningHistory
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /history [post]
+// @Security     BearerAuth
+func (h *HistoryHandler) RecordProgress(c *gin.Context) {
+	var req RecordProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID := c.GetString("user_id")
+	res, err := h.uc.RecordProgress(c.Request.Context(), userID, req.StoryID, req.ChapterID, req.SlideSequence, req.Position, req.Duration)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, res)
+}
+
+// ContinueListening godoc
+// @Summary      Continue listening shelf
+// @Description  Latest progress for every story the user has listened to, most recent first
+// @Tags         history
+// @Produce      json
+// @Param        limit  query     int  false  "Limit"
+// @Success      200  {array}   domain.ListeningProgress
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /history/continue [get]
+// @Security     BearerAuth
+func (h *HistoryHandler) ContinueListening(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+
+	res, err := h.uc.GetContinueListening(c.Request.Context(), c.GetString("user_id"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// GetStoryProgress godoc
+// @Summary      Get story progress
+// @Description  Resume point and progress percentage of the user on a story
+// @Tags         history
+// @Produce      json
+// @Param        uuid   path      string  true  "Story UUID"
+// @Success      200  {object}  domain.ListeningProgress
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/progress [get]
+// @Security     BearerAuth
+func (h *HistoryHandler) GetStoryProgress(c *gin.Context) {
+	res, err := h.uc.GetStoryProgress(c.Request.Context(), c.GetString("user_id"), c.Param("uuid"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+func (h *HistoryHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
\ No newline at end of file
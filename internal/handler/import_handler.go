@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type ImportHandler struct {
+	uc domain.ImportUseCase
+}
+
+func NewImportHandler(uc domain.ImportUseCase) *ImportHandler {
+	return &ImportHandler{uc: uc}
+}
+
+type ImportRequest struct {
+	Format string `form:"format"`
+	DryRun bool   `form:"dry_run"`
+}
+
+func importErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// SubmitImport godoc
+// @Summary      Start a bulk import
+// @Description  Queue a CSV or JSON file of story, chapter and slide rows for background import. Every row has a type (story, chapter or slide); chapter and slide rows point to their parent with the ref of an earlier row or the UUID of an existing story or chapter. CSV columns and JSON fields: type, ref, story, chapter, title, description, category (UUID or name), synopsis, duration_seconds, sequence, content, image, sound. image and sound are paths inside the optional ZIP archive. Rows go through the same checks as the story and chapter endpoints and failures are reported per row; rows whose parent failed are skipped. With dry_run nothing is written. The format defaults to the file extension. The file and archive together are limited to IMPORT_MAX_SIZE_MB.
+// @Tags         imports
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "CSV or JSON rows"
+// @Param        archive  formData  file    false  "ZIP with media referenced by the rows"
+// @Param        format   formData  string  false  "csv or json"
+// @Param        dry_run  formData  bool    false  "Validate only"
+// @Success      202  {object}  domain.ImportJob
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      413  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/imports [post]
+// @Security     BearerAuth
+func (h *ImportHandler) Submit(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		formFileError(c, err)
+		return
+	}
+	data, err := readFormFile(header)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var archive []byte
+	if archiveHeader, err := c.FormFile("archive"); err == nil {
+		if archive, err = readFormFile(archiveHeader); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	format := req.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+
+	job, err := h.uc.Submit(c.Request.Context(), format, data, archive, req.DryRun)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusAccepted, job)
+}
+
+// GetImport godoc
+// @Summary      Get a bulk import
+// @Description  Status of an import job. Once completed, report lists the result of every row (ok, failed or skipped) with the created story or chapter UUID or slide ID. failed_rows counts failed and skipped rows.
+// @Tags         imports
+// @Produce      json
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {object}  domain.ImportJob
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/imports/{id} [get]
+// @Security     BearerAuth
+func (h *ImportHandler) Get(c *gin.Context) {
+	job, err := h.uc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, job)
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type JobHandler struct {
+	uc domain.JobUseCase
+}
+
+func NewJobHandler(uc domain.JobUseCase) *JobHandler {
+	return &JobHandler{uc: uc}
+}
+
+func jobErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// GetJob godoc
+// @Summary      Get a background job
+// @Description  Status of a background job, e.g. the media processing job returned as job_id when a slide is added with an image or sound. pending jobs wait for a worker or for their next retry at run_at; dead jobs ran out of attempts and are listed in the dead-letter queue.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  domain.Job
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/jobs/{id} [get]
+// @Security     BearerAuth
+func (h *JobHandler) Get(c *gin.Context) {
+	job, err := h.uc.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		jobErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, job)
+}
+
+// ListDeadJobs godoc
+// @Summary      List dead jobs
+// @Description  Jobs that failed on every attempt, newest first, with their payload and last error.
+// @Tags         jobs
+// @Produce      json
+// @Param        limit  query     int  false "Limit (max 100)"
+// @Success      200  {array}   domain.DeadJob
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/jobs/dead [get]
+// @Security     BearerAuth
+func (h *JobHandler) ListDead(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	dead, err := h.uc.ListDead(c.Request.Context(), limit)
+	if err != nil {
+		jobErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, dead)
+}
+
+// RetryDeadJob godoc
+// @Summary      Retry a dead job
+// @Description  Move a job from the dead-letter queue back to the queue with a fresh set of attempts.
+// @Tags         jobs
+// @Produce      json
+// @Param        id   path      int  true  "Dead job ID"
+// @Success      200  {object}  domain.Job
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/jobs/dead/{id}/retry [post]
+// @Security     BearerAuth
+func (h *JobHandler) RetryDead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid dead job id")
+		return
+	}
+
+	job, err := h.uc.Retry(c.Request.Context(), uint(id))
+	if err != nil {
+		jobErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, job)
+}
\ No newline at end of file
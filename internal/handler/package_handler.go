@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type PackageHandler struct {
+	uc domain.PackageUseCase
+}
+
+func NewPackageHandler(uc domain.PackageUseCase) *PackageHandler {
+	return &PackageHandler{uc: uc}
+}
+
+// formFileError menjawab gagalnya c.FormFile. Body yang terpotong oleh
+// middleware.MaxBodySize dijawab 413.
+func formFileError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, "file is too large")
+		return
+	}
+	utils.ErrorResponse(c, http.StatusBadRequest, "file is required")
+}
+
+func packageErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		utils.ErrorResponse(c, http.StatusConflict, "a chapter in the package belongs to another story")
+	case errors.Is(err, domain.ErrInvalidTransition):
+		utils.ErrorResponse(c, http.StatusConflict, "the story is published or in review, move it back to draft before importing")
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ExportStoryPackage godoc
+// @Summary      Export a story package
+// @Description  Download the story with its category reference, all chapters (drafts included), slides and media as a ZIP with a manifest.json. The ZIP can be imported into another environment.
+// @Tags         packages
+// @Produce      application/zip
+// @Param        uuid  path      string  true  "Story UUID"
+// @Success      200  {file}    binary
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/package [get]
+// @Security     BearerAuth
+func (h *PackageHandler) Export(c *gin.Context) {
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="story-`+c.Param("uuid")+`.zip"`)
+
+	if err := h.uc.Export(c.Request.Context(), c.Param("uuid"), c.Writer); err != nil {
+		// setelah ZIP mulai terkirim, error hanya bisa memutus respons
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		packageErrorResponse(c, err)
+	}
+}
+
+// ImportStoryPackage godoc
+// @Summary      Import a story package
+// @Description  Import a ZIP produced by the export endpoint. Story and chapters are matched by UUID, so importing the same package again updates instead of duplicating. New stories and chapters are created as Draft; existing ones keep their status. Published and InReview stories are rejected with 409 and must be moved back to Draft first. Slides are matched by sequence, slides and chapters missing from the package are deleted. Changes to existing stories, chapters and slides are recorded as revisions. The category is matched by UUID, then by name and type.
+// @Tags         packages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "Story package ZIP"
+// @Success      200  {object}  domain.PackageImportResult
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      413  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/packages [post]
+// @Security     BearerAuth
+func (h *PackageHandler) Import(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		formFileError(c, err)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	res, err := h.uc.Import(c.Request.Context(), file, header.Size)
+	if err != nil {
+		packageErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +25,59 @@ type SavePreferencesRequest struct {
 	HadistCategories []string `json:"hadist_categories"`
 }
 
+func (r SavePreferencesRequest) selection() domain.PreferenceSelection {
+	return domain.PreferenceSelection{Story: r.StoryCategories, Dakwah: r.DakwahCategories, Hadist: r.HadistCategories}
+}
+
+type UpdatePreferencesRequest struct {
+	Add    SavePreferencesRequest `json:"add"`
+	Remove SavePreferencesRequest `json:"remove"`
+}
+
+func preferenceErrorResponse(c *gin.Context, err error) {
+	var invalid *domain.InvalidCategoriesError
+	switch {
+	case errors.As(err, &invalid):
+		utils.ErrorResponseWithData(c, http.StatusBadRequest, "kategori tidak dikenal atau bukan dari section yang sesuai", invalid)
+	case errors.Is(err, domain.ErrTooManyChoices):
+		utils.ErrorResponse(c, http.StatusBadRequest, "Maksimal pilih 5 kategori per section")
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// Get godoc
+// @Summary      Get my preferences
+// @Description  Return the categories chosen by the current user, grouped per section.
+// @Tags         preferences
+// @Produce      json
+// @Success      200  {object}  domain.UserPreferences
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /preferences [get]
+// @Security     BearerAuth
+func (h *PreferenceHandler) Get(c *gin.Context) {
+	prefs, err := h.uc.GetPreferences(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		preferenceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, prefs)
+}
+
+// Save godoc
+// @Summary      Replace my preferences
+// @Description  Replace all chosen categories at once. Unknown category UUIDs are listed per section in the error data.
+// @Tags         preferences
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SavePreferencesRequest  true  "Chosen categories, at most 5 per section"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /preferences [post]
+// @Security     BearerAuth
 func (h *PreferenceHandler) Save(c *gin.Context) {
 	var req SavePreferencesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -31,17 +85,17 @@ func (h *PreferenceHandler) Save(c *gin.Context) {
 		return
 	}
 
-	if len(req.StoryCategories) > 5 {
+	if len(req.StoryCategories) > domain.MaxPreferenceChoices {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Maksimal pilih 5 Kategori Story")
 		return
 	}
 
-	if len(req.DakwahCategories) > 5 {
+	if len(req.DakwahCategories) > domain.MaxPreferenceChoices {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Maksimal pilih 5 Kategori Dakwah")
 		return
 	}
 
-	if len(req.HadistCategories) > 5 {
+	if len(req.HadistCategories) > domain.MaxPreferenceChoices {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Maksimal pilih 5 Kategori Hadist")
 		return
 	}
@@ -50,9 +104,36 @@ func (h *PreferenceHandler) Save(c *gin.Context) {
 	err := h.uc.SavePreferences(c.Request.Context(), userID, req.StoryCategories, req.DakwahCategories, req.HadistCategories)
 	
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		preferenceErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessMessage(c, http.StatusOK, "preferences saved")
+}
+
+// Update godoc
+// @Summary      Add or remove preferred categories
+// @Description  Add and remove individual categories per section. Removals are applied first; the result may hold at most 5 categories per section.
+// @Tags         preferences
+// @Accept       json
+// @Produce      json
+// @Param        request  body      UpdatePreferencesRequest  true  "Categories to add and remove"
+// @Success      200  {object}  domain.UserPreferences
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /preferences [patch]
+// @Security     BearerAuth
+func (h *PreferenceHandler) Update(c *gin.Context) {
+	var req UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prefs, err := h.uc.UpdatePreferences(c.Request.Context(), c.GetString("user_id"), req.Add.selection(), req.Remove.selection())
+	if err != nil {
+		preferenceErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, prefs)
 }
\ No newline at end of file
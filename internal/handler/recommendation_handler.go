@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type RecommendationHandler struct {
+	uc domain.RecommendationUseCase
+}
+
+func NewRecommendationHandler(uc domain.RecommendationUseCase) *RecommendationHandler {
+	return &RecommendationHandler{uc: uc}
+}
+
+// GetRecommendations godoc
+// @Summary      Get recommended stories
+// @Description  Personalized stories scored from preferences, listening history, recency and category popularity. Empty while the first calculation for a new user is still queued
+// @Tags         stories
+// @Produce      json
+// @Success      200  {array}   domain.Recommendation
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/recommendations [get]
+// @Security     BearerAuth
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	recs, err := h.uc.GetForUser(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, recs)
+}
\ No newline at end of file
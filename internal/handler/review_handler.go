@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type ReviewHandler struct {
+	uc domain.ReviewUseCase
+}
+
+func NewReviewHandler(uc domain.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{uc: uc}
+}
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Body   string `json:"body"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected hidden"`
+	Reason string `json:"reason"`
+}
+
+func reviewErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidTransition):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// SubmitReview godoc
+// @Summary      Rate and review a story
+// @Description  Create or replace the user's rating (1-5) and optional review text for a published story. A rating without text is approved immediately; text reviews wait for moderation unless auto-approval is enabled and the text passes the keyword filter.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string         true  "Story UUID"
+// @Param        request  body      ReviewRequest  true  "Rating and review"
+// @Success      200  {object}  domain.Review
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/review [put]
+// @Security     BearerAuth
+func (h *ReviewHandler) Submit(c *gin.Context) {
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.uc.Submit(c.Request.Context(), c.GetString("user_id"), c.Param("uuid"), req.Rating, req.Body)
+	if err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// GetMyReview godoc
+// @Summary      Get my review of a story
+// @Description  The current user's review of the story, including its moderation status
+// @Tags         reviews
+// @Produce      json
+// @Param        uuid  path      string  true  "Story UUID"
+// @Success      200  {object}  domain.Review
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/review [get]
+// @Security     BearerAuth
+func (h *ReviewHandler) GetMine(c *gin.Context) {
+	res, err := h.uc.GetMine(c.Request.Context(), c.GetString("user_id"), c.Param("uuid"))
+	if err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
+
+// DeleteMyReview godoc
+// @Summary      Delete my review of a story
+// @Description  Remove the current user's rating and review. The story's aggregate rating is recalculated.
+// @Tags         reviews
+// @Produce      json
+// @Param        uuid  path      string  true  "Story UUID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/review [delete]
+// @Security     BearerAuth
+func (h *ReviewHandler) Delete(c *gin.Context) {
+	if err := h.uc.Delete(c.Request.Context(), c.GetString("user_id"), c.Param("uuid")); err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessMessage(c, http.StatusOK, "deleted")
+}
+
+// storyReviewListSpec adalah whitelist sort untuk ulasan publik sebuah story.
+var storyReviewListSpec = utils.ListSpec{
+	SortFields:   []string{"created_at", "rating"},
+	DefaultSort:  []domain.SortField{{Field: "created_at", Desc: true}},
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// ListStoryReviews godoc
+// @Summary      List reviews of a story
+// @Description  Approved text reviews of a published story, newest first
+// @Tags         reviews
+// @Produce      json
+// @Param        uuid        path      string  true  "Story UUID"
+// @Param        limit       query     int     false "Limit (max 100)"
+// @Param        cursor      query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total  query     bool    false "Include total count in meta"
+// @Param        sort        query     string  false "Comma separated fields, prefix with - for descending: created_at, rating"
+// @Success      200  {array}   domain.Review
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /stories/{uuid}/reviews [get]
+func (h *ReviewHandler) ListForStory(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), storyReviewListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, page, err := h.uc.ListForStory(c.Request.Context(), c.Param("uuid"), q)
+	if err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, res, page)
+}
+
+// reviewModerationListSpec adalah whitelist sort dan filter antrean moderasi. Tanpa
+// filter status yang tampil adalah ulasan pending, yang terlama lebih dulu.
+var reviewModerationListSpec = utils.ListSpec{
+	SortFields: []string{"created_at", "rating"},
+	Filters: map[string]utils.FilterRule{
+		domain.FilterStatus: {Kind: utils.FilterEnum, Values: []string{
+			domain.ReviewStatusPending, domain.ReviewStatusApproved, domain.ReviewStatusRejected, domain.ReviewStatusHidden,
+		}},
+		domain.FilterStory:   {Kind: utils.FilterUUID},
+		domain.FilterFlagged: {Kind: utils.FilterBool},
+	},
+	DefaultSort:  []domain.SortField{{Field: "created_at"}},
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// ListReviews godoc
+// @Summary      Review moderation queue
+// @Description  Reviews for moderation, oldest first. Defaults to pending reviews when no status filter is given.
+// @Tags         reviews
+// @Produce      json
+// @Param        limit            query     int     false "Limit (max 100)"
+// @Param        cursor           query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total       query     bool    false "Include total count in meta"
+// @Param        sort             query     string  false "Comma separated fields, prefix with - for descending: created_at, rating"
+// @Param        filter[status]   query     string  false "pending, approved, rejected or hidden"
+// @Param        filter[story]    query     string  false "Story UUID"
+// @Param        filter[flagged]  query     bool    false "Only reviews that matched (true) or passed (false) the keyword filter"
+// @Success      200  {array}   domain.Review
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/reviews [get]
+// @Security     BearerAuth
+func (h *ReviewHandler) List(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), reviewModerationListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, ok := q.Filters[domain.FilterStatus]; !ok {
+		if q.Filters == nil {
+			q.Filters = map[string]string{}
+		}
+		q.Filters[domain.FilterStatus] = domain.ReviewStatusPending
+	}
+
+	res, page, err := h.uc.List(c.Request.Context(), q)
+	if err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, res, page)
+}
+
+// ModerateReview godoc
+// @Summary      Moderate a review
+// @Description  Approve, reject or hide a review. A reason is required when rejecting or hiding. Allowed moves: pending to approved/rejected, approved to hidden, rejected or hidden back to approved.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "Review UUID"
+// @Param        request  body      ModerateReviewRequest  true  "New status and reason"
+// @Success      200  {object}  domain.Review
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/reviews/{id}/moderation [post]
+// @Security     BearerAuth
+func (h *ReviewHandler) Moderate(c *gin.Context) {
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.uc.Moderate(c.Request.Context(), c.Param("id"), req.Status, req.Reason)
+	if err != nil {
+		reviewErrorResponse(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, res)
+}
\ No newline at end of file
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type RevisionHandler struct {
+	uc domain.RevisionUseCase
+}
+
+func NewRevisionHandler(uc domain.RevisionUseCase) *RevisionHandler {
+	return &RevisionHandler{uc: uc}
+}
+
+// revisionListSpec adalah whitelist sort untuk GET /api/admin/stories/:uuid/revisions.
+var revisionListSpec = utils.ListSpec{
+	SortFields:   []string{"created_at"},
+	DefaultSort:  []domain.SortField{{Field: "created_at", Desc: true}},
+	DefaultLimit: 20,
+	MaxLimit:     100,
+}
+
+// ListRevisions godoc
+// @Summary      List revisions of a story
+// @Description  Revisions of the story and its chapters and slides, newest first. Each revision holds the snapshot after the change and the field-level diff from the previous version.
+// @Tags         revisions
+// @Produce      json
+// @Param        uuid        path      string  true  "Story UUID"
+// @Param        entity      query     string  false "story, chapter or slide"
+// @Param        limit       query     int     false "Limit (max 100)"
+// @Param        cursor      query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total  query     bool    false "Include total count in meta"
+// @Success      200  {array}   domain.Revision
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/revisions [get]
+// @Security     BearerAuth
+func (h *RevisionHandler) ListByStory(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), revisionListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	revisions, page, err := h.uc.ListByStory(c.Request.Context(), c.Param("uuid"), c.Query("entity"), q)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SuccessResponseWithMeta(c, http.StatusOK, revisions, page)
+}
+
+// GetRevision godoc
+// @Summary      Get revision
+// @Description  Snapshot and diff of a single revision
+// @Tags         revisions
+// @Produce      json
+// @Param        id   path      int  true  "Revision ID"
+// @Success      200  {object}  domain.Revision
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/revisions/{id} [get]
+// @Security     BearerAuth
+func (h *RevisionHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid revision id")
+		return
+	}
+
+	rev, err := h.uc.Get(c.Request.Context(), uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, rev)
+}
+
+// RollbackRevision godoc
+// @Summary      Roll back to a revision
+// @Description  Restore the entity to the snapshot of this revision, including its media URLs. Story status and schedule, chapter position and slide sequence are not restored. The rollback itself is recorded as a new revision.
+// @Tags         revisions
+// @Produce      json
+// @Param        id   path      int  true  "Revision ID"
+// @Success      200  {object}  domain.Revision
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/revisions/{id}/rollback [post]
+// @Security     BearerAuth
+func (h *RevisionHandler) Rollback(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid revision id")
+		return
+	}
+
+	rev, err := h.uc.Rollback(c.Request.Context(), uint(id))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, rev)
+}
+
+func (h *RevisionHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		utils.ErrorResponse(c, http.StatusConflict, "the slide media changed while rolling back, try again")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+}
\ No newline at end of file
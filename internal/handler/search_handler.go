@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type SearchHandler struct {
+	uc domain.SearchUseCase
+}
+
+func NewSearchHandler(uc domain.SearchUseCase) *SearchHandler {
+	return &SearchHandler{uc: uc}
+}
+
+// Search godoc
+// @Summary      Unified search
+// @Description  Search categories, stories, chapters and slide excerpts at once. Hits are ordered by relevance, matching categories are listed first on the first page only. Only published stories are searched. Facets count matching stories by category.
+// @Tags         search
+// @Produce      json
+// @Param        q             query     string  true  "Search Query"
+// @Param        category      query     string  false "Category UUID"
+// @Param        has_audio     query     bool    false "Only hits with (true) or without (false) audio"
+// @Param        created_from  query     string  false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        created_to    query     string  false "Created at or before (RFC3339 or YYYY-MM-DD)"
+// @Param        cursor        query     string  false "Cursor from meta.next_cursor"
+// @Param        limit         query     int     false "Limit (max 50)"
+// @Success      200  {object}  domain.SearchResult
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "query required")
+		return
+	}
+
+	filter, err := parseSearchFilter(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	result, err := h.uc.Search(c.Request.Context(), q, filter, c.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid query or cursor")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponseWithMeta(c, http.StatusOK, result, domain.PageInfo{
+		NextCursor: result.NextCursor,
+		HasMore:    result.HasMore,
+	})
+}
+
+func parseSearchFilter(c *gin.Context) (domain.SearchFilter, error) {
+	var filter domain.SearchFilter
+
+	if v := c.Query("category"); v != "" {
+		if _, err := uuid.Parse(v); err != nil {
+			return filter, fmt.Errorf("invalid category id")
+		}
+		filter.CategoryUUID = v
+	}
+	if v := c.Query("has_audio"); v != "" {
+		hasAudio, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid has_audio value")
+		}
+		filter.HasAudio = &hasAudio
+	}
+	if v := c.Query("created_from"); v != "" {
+		t, err := parseSearchTime(v, false)
+		if err != nil {
+			return filter, fmt.Errorf("invalid created_from date")
+		}
+		filter.CreatedFrom = &t
+	}
+	if v := c.Query("created_to"); v != "" {
+		t, err := parseSearchTime(v, true)
+		if err != nil {
+			return filter, fmt.Errorf("invalid created_to date")
+		}
+		filter.CreatedTo = &t
+	}
+	return filter, nil
+}
+
+// parseSearchTime menerima RFC3339 atau tanggal saja. Untuk batas akhir, tanggal saja
+// dianggap sampai akhir hari tersebut.
+func parseSearchTime(v string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return t, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// Suggest godoc
+// @Summary      Search autocomplete
+// @Description  Typo-tolerant story title and category name suggestions while the user types
+// @Tags         search
+// @Produce      json
+// @Param        q      query     string  true  "Partial query"
+// @Param        limit  query     int     false "Limit (max 20)"
+// @Success      200  {array}   domain.SearchSuggestion
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+
+	suggestions, err := h.uc.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "query required")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, suggestions)
+}
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -13,11 +14,12 @@ import (
 )
 
 type StoryHandler struct {
-	uc domain.StoryUseCase
+	uc         domain.StoryUseCase
+	favourites domain.FavouriteUseCase
 }
 
-func NewStoryHandler(uc domain.StoryUseCase) *StoryHandler {
-	return &StoryHandler{uc: uc}
+func NewStoryHandler(uc domain.StoryUseCase, favourites domain.FavouriteUseCase) *StoryHandler {
+	return &StoryHandler{uc: uc, favourites: favourites}
 }
 
 type CreateStoryRequest struct {
@@ -30,7 +32,16 @@ type UpdateStoryRequest struct {
 	Title       string `form:"title"`
 	Description string `form:"description"`
 	CategoryID  string `form:"category_id"`
-	Status      string `form:"status"`
+}
+
+type TransitionStoryRequest struct {
+	Status string `form:"status" json:"status" binding:"required"`
+}
+
+// ScheduleStoryRequest menggantikan seluruh jadwal story, field yang kosong menghapus jadwal.
+type ScheduleStoryRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
 type AddSlideRequest struct {
@@ -38,9 +49,13 @@ type AddSlideRequest struct {
 	Sequence int    `form:"sequence" binding:"required"`
 }
 
+type ReorderSlidesRequest struct {
+	SlideIDs []uint `json:"slide_ids" binding:"required"`
+}
+
 // CreateStory godoc
 // @Summary      Create a new story
-// @Description  Create a new story with thumbnail
+// @Description  Create a new story with thumbnail. The dominant colour is taken from the thumbnail in the background.
 // @Tags         stories
 // @Accept       multipart/form-data
 // @Produce      json
@@ -78,7 +93,7 @@ func (h *StoryHandler) Create(c *gin.Context) {
 
 // UpdateStory godoc
 // @Summary      Update a story
-// @Description  Update story details
+// @Description  Update story details. Status is changed through POST /admin/stories/{uuid}/status. A new thumbnail keeps the previous dominant colour until the background job replaces it.
 // @Tags         stories
 // @Accept       multipart/form-data
 // @Produce      json
@@ -86,9 +101,9 @@ func (h *StoryHandler) Create(c *gin.Context) {
 // @Param        title        formData  string  false "Title"
 // @Param        description  formData  string  false "Description"
 // @Param        category_id  formData  string  false "Category UUID"
-// @Param        status       formData  string  false "Status"
 // @Param        file         formData  file    false "Thumbnail Image"
 // @Success      200  {object}  domain.Story
+// @Failure      400  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /admin/stories/{uuid} [put]
 // @Security     BearerAuth
@@ -96,10 +111,15 @@ func (h *StoryHandler) Update(c *gin.Context) {
 	var req UpdateStoryRequest
 	_ = c.ShouldBind(&req)
 
+	if c.PostForm("status") != "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "status can only be changed through /admin/stories/{uuid}/status")
+		return
+	}
+
 	uuid := c.Param("uuid")
 	file, header, _ := c.Request.FormFile("file")
 
-	story, err := h.uc.Update(c.Request.Context(), uuid, req.Title, req.Description, req.CategoryID, req.Status, file, header)
+	story, err := h.uc.Update(c.Request.Context(), uuid, req.Title, req.Description, req.CategoryID, file, header)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -108,34 +128,106 @@ func (h *StoryHandler) Update(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, story)
 }
 
+// storyListSpec adalah whitelist sort dan filter untuk GET /api/stories. Endpoint publik
+// selalu dibatasi ke story Published sehingga filter status hanya ada di versi admin.
+var storyListSpec = utils.ListSpec{
+	SortFields: []string{"created_at", "updated_at", "title", "slide_count", "rating"},
+	Filters: map[string]utils.FilterRule{
+		domain.FilterCategory: {Kind: utils.FilterUUID},
+		domain.FilterHasAudio: {Kind: utils.FilterBool},
+	},
+	DefaultSort:  []domain.SortField{{Field: "created_at", Desc: true}},
+	DefaultLimit: 10,
+	MaxLimit:     100,
+}
+
+var adminStoryListSpec = utils.ListSpec{
+	SortFields: storyListSpec.SortFields,
+	Filters: map[string]utils.FilterRule{
+		domain.FilterCategory: {Kind: utils.FilterUUID},
+		domain.FilterStatus: {Kind: utils.FilterEnum, Values: []string{
+			domain.StatusPendingUpload, domain.StatusDraft, domain.StatusInReview, domain.StatusPublished, domain.StatusArchived,
+		}},
+		domain.FilterHasAudio: {Kind: utils.FilterBool},
+	},
+	DefaultSort:  storyListSpec.DefaultSort,
+	DefaultLimit: storyListSpec.DefaultLimit,
+	MaxLimit:     storyListSpec.MaxLimit,
+}
+
 // GetAllStories godoc
 // @Summary      Get all stories
-// @Description  Get stories with pagination
+// @Description  Get published stories with pagination, sorting and filters. With a bearer token every story carries a favourited flag.
 // @Tags         stories
 // @Produce      json
-// @Param        page   query     int     false "Page"
-// @Param        limit  query     int     false "Limit"
-// @Param        sort   query     string  false "Sort (e.g., created_at desc)"
+// @Param        limit              query     int     false "Limit (max 100)"
+// @Param        cursor             query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total         query     bool    false "Include total count in meta"
+// @Param        sort               query     string  false "Comma separated fields, prefix with - for descending: created_at, updated_at, title, slide_count, rating (e.g. -created_at,title)"
+// @Param        filter[category]   query     string  false "Category UUID"
+// @Param        filter[has_audio]  query     bool    false "Only stories with (true) or without (false) audio"
 // @Success      200  {array}   domain.Story
+// @Failure      400  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /stories [get]
 func (h *StoryHandler) GetAll(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	sort := c.DefaultQuery("sort", "created_at desc")
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), storyListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.Filters[domain.FilterStatus] = domain.StatusPublished
+
+	h.list(c, q, true)
+}
 
-	stories, err := h.uc.GetAll(c.Request.Context(), page, limit, sort)
+// AdminGetAllStories godoc
+// @Summary      Get all stories (admin)
+// @Description  Get stories in any workflow status with pagination, sorting and filters
+// @Tags         stories
+// @Produce      json
+// @Param        limit              query     int     false "Limit (max 100)"
+// @Param        cursor             query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        with_total         query     bool    false "Include total count in meta"
+// @Param        sort               query     string  false "Comma separated fields, prefix with - for descending: created_at, updated_at, title, slide_count, rating"
+// @Param        filter[category]   query     string  false "Category UUID"
+// @Param        filter[status]     query     string  false "Pending_Upload, Draft, InReview, Published or Archived"
+// @Param        filter[has_audio]  query     bool    false "Only stories with (true) or without (false) audio"
+// @Success      200  {array}   domain.Story
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories [get]
+// @Security     BearerAuth
+func (h *StoryHandler) AdminGetAll(c *gin.Context) {
+	q, err := utils.ParseListQuery(c.Request.URL.Query(), adminStoryListSpec)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.list(c, q, false)
+}
+
+func (h *StoryHandler) list(c *gin.Context, q domain.ListQuery, personalise bool) {
+	stories, page, err := h.uc.GetAll(c.Request.Context(), q)
 	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if personalise {
+		h.markFavourited(c, stories)
+	}
 
-	utils.SuccessResponse(c, http.StatusOK, stories)
+	utils.SuccessResponseWithMeta(c, http.StatusOK, stories, page)
 }
 
 // GetStory godoc
 // @Summary      Get story by UUID
-// @Description  Retrieve a single story
+// @Description  Retrieve a single published story. With a bearer token the story carries a favourited flag.
 // @Tags         stories
 // @Produce      json
 // @Param        uuid   path      string  true  "Story UUID"
@@ -144,8 +236,42 @@ func (h *StoryHandler) GetAll(c *gin.Context) {
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /stories/{uuid} [get]
 func (h *StoryHandler) GetOne(c *gin.Context) {
-	uuid := c.Param("uuid")
-	story, err := h.uc.GetByUUID(c.Request.Context(), uuid)
+	story, err := h.uc.GetPublished(c.Request.Context(), c.Param("uuid"))
+	if err == nil {
+		single := []domain.Story{*story}
+		h.markFavourited(c, single)
+		story.Favourited = single[0].Favourited
+	}
+	h.respondStory(c, story, err)
+}
+
+// markFavourited hanya berlaku jika request membawa token. Kegagalan membaca favorit
+// tidak menggagalkan respons, story dikirim tanpa flag.
+func (h *StoryHandler) markFavourited(c *gin.Context, stories []domain.Story) {
+	userID := c.GetString("user_id")
+	if h.favourites == nil || userID == "" {
+		return
+	}
+	_ = h.favourites.MarkFavourited(c.Request.Context(), userID, stories)
+}
+
+// AdminGetStory godoc
+// @Summary      Get story by UUID (admin)
+// @Description  Retrieve a single story in any workflow status
+// @Tags         stories
+// @Produce      json
+// @Param        uuid   path      string  true  "Story UUID"
+// @Success      200  {object}  domain.Story
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid} [get]
+// @Security     BearerAuth
+func (h *StoryHandler) AdminGetOne(c *gin.Context) {
+	story, err := h.uc.GetByUUID(c.Request.Context(), c.Param("uuid"))
+	h.respondStory(c, story, err)
+}
+
+func (h *StoryHandler) respondStory(c *gin.Context, story *domain.Story, err error) {
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
 			utils.ErrorResponse(c, http.StatusNotFound, "story not found")
@@ -157,13 +283,117 @@ func (h *StoryHandler) GetOne(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, story)
 }
 
+// TransitionStory godoc
+// @Summary      Change story workflow status
+// @Description  Move a story along Draft -> InReview -> Published -> Archived. Editors may submit to and withdraw from review, only admins publish, archive and restore.
+// @Tags         stories
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                          true  "Story UUID"
+// @Param        request  body      handler.TransitionStoryRequest  true  "Target status"
+// @Success      200  {object}  domain.Story
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      403  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/status [post]
+// @Security     BearerAuth
+func (h *StoryHandler) Transition(c *gin.Context) {
+	var req TransitionStoryRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	story, err := h.uc.Transition(c.Request.Context(), c.Param("uuid"), req.Status, c.GetString("role"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "story not found")
+		case errors.Is(err, domain.ErrForbidden):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, domain.ErrInvalidTransition):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, story)
+}
+
+// ScheduleStory godoc
+// @Summary      Schedule publishing of a story
+// @Description  Set publish_at and/or unpublish_at (RFC3339). A story is published at publish_at once it is InReview, and archived at unpublish_at. Omitted fields clear the schedule.
+// @Tags         stories
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                        true  "Story UUID"
+// @Param        request  body      handler.ScheduleStoryRequest  true  "Schedule"
+// @Success      200  {object}  domain.Story
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/schedule [put]
+// @Security     BearerAuth
+func (h *StoryHandler) Schedule(c *gin.Context) {
+	var req ScheduleStoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	story, err := h.uc.Schedule(c.Request.Context(), c.Param("uuid"), req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "story not found")
+		case errors.Is(err, domain.ErrBadParamInput):
+			utils.ErrorResponse(c, http.StatusBadRequest, "schedule must be in the future and unpublish_at after publish_at")
+		case errors.Is(err, domain.ErrInvalidTransition):
+			utils.ErrorResponse(c, http.StatusConflict, "story status does not allow this schedule")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, story)
+}
+
+// ListScheduledStories godoc
+// @Summary      List upcoming scheduled changes
+// @Description  Scheduled publish and unpublish changes ordered by time. Entries in the past are waiting for the story to reach the required status.
+// @Tags         stories
+// @Produce      json
+// @Param        limit  query     int  false "Limit (max 100)"
+// @Success      200  {array}   domain.ScheduledChange
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/schedule [get]
+// @Security     BearerAuth
+func (h *StoryHandler) ListScheduled(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	changes, err := h.uc.ListScheduled(c.Request.Context(), limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, changes)
+}
+
 // SearchStories godoc
 // @Summary      Search stories
-// @Description  Search stories by title or description
+// @Description  Full-text search over title, description, category name and slide content of published stories, ordered by relevance
 // @Tags         stories
 // @Produce      json
-// @Param        q    query     string  true  "Search Query"
-// @Success      200  {array}   domain.Story
+// @Param        q      query     string  true  "Search Query"
+// @Param        cursor query     string  false "Cursor from meta.next_cursor or meta.prev_cursor"
+// @Param        limit  query     int     false "Limit (max 50)"
+// @Success      200  {array}   domain.StorySearchHit
+// @Failure      400  {object}  utils.APIResponse
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /search/stories [get]
 func (h *StoryHandler) Search(c *gin.Context) {
@@ -173,13 +403,23 @@ func (h *StoryHandler) Search(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusBadRequest, "query required")
 		return
 	}
-	stories, err := h.uc.Search(c.Request.Context(), q)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	params := domain.SearchParams{Query: q, Cursor: c.Query("cursor"), Limit: limit}
+	hits, page, err := h.uc.Search(c.Request.Context(), params)
 	if err != nil {
+		if errors.Is(err, domain.ErrBadParamInput) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid query or cursor")
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, stories)
+	utils.SuccessResponseWithMeta(c, http.StatusOK, hits, page)
 }
 
 // DeleteStory godoc
@@ -213,6 +453,9 @@ func (h *StoryHandler) Delete(c *gin.Context) {
 // @Param        sequence formData  int     true  "Sequence Number"
 // @Param        file     formData  file    false "Slide Image"
 // @Success      201  {object}  domain.Slide
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      409  {object}  utils.APIResponse  "Sequence already used"
+// @Failure      422  {object}  utils.APIResponse  "Slide limit reached"
 // @Failure      500  {object}  utils.APIResponse
 // @Router       /admin/stories/{uuid}/slides [post]
 // @Security     BearerAuth
@@ -228,22 +471,126 @@ func (h *StoryHandler) AddSlide(c *gin.Context) {
 
 	slide, err := h.uc.AddSlide(c.Request.Context(), storyUUID, req.Content, req.Sequence, file, header)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		slideErrorResponse(c, err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusCreated, slide)
 }
 
-func (h *StoryHandler) GetRecommendations(c *gin.Context) {
-    userID := c.GetString("user_id")
-    
-    // GUNAKAN h.uc (Bukan h.StoryUseCase)
-    recs, err := h.uc.GetRecommendations(c.Request.Context(), userID)
-    
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-        return
-    }
-    c.JSON(http.StatusOK, gin.H{"data": recs})
+// UpdateSlide godoc
+// @Summary      Update a story slide
+// @Description  Replace the text and/or image of a slide. Fields that are not sent are kept. The previous version is kept in the revision history.
+// @Tags         stories
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        uuid     path      string  true  "Story UUID"
+// @Param        id       path      int     true  "Slide ID"
+// @Param        content  formData  string  false "Content Text"
+// @Param        file     formData  file    false "Slide Image"
+// @Success      200  {object}  domain.Slide
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/slides/{id} [patch]
+// @Security     BearerAuth
+func (h *StoryHandler) UpdateSlide(c *gin.Context) {
+	slideID, ok := parseSlideID(c)
+	if !ok {
+		return
+	}
+
+	var content *string
+	if value, exists := c.GetPostForm("content"); exists {
+		content = &value
+	}
+	file, header, _ := c.Request.FormFile("file")
+
+	slide, err := h.uc.UpdateSlide(c.Request.Context(), c.Param("uuid"), slideID, content, file, header)
+	if err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, slide)
+}
+
+// DeleteSlide godoc
+// @Summary      Delete a story slide
+// @Description  Delete a slide and its image
+// @Tags         stories
+// @Produce      json
+// @Param        uuid  path      string  true  "Story UUID"
+// @Param        id    path      int     true  "Slide ID"
+// @Success      200  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/slides/{id} [delete]
+// @Security     BearerAuth
+func (h *StoryHandler) DeleteSlide(c *gin.Context) {
+	slideID, ok := parseSlideID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteSlide(c.Request.Context(), c.Param("uuid"), slideID); err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessMessage(c, http.StatusOK, "slide deleted successfully")
+}
+
+// ReorderSlides godoc
+// @Summary      Reorder story slides
+// @Description  Rewrite the sequence of all slides of the story in the given order. slide_ids must contain every slide of the story exactly once.
+// @Tags         stories
+// @Accept       json
+// @Produce      json
+// @Param        uuid     path      string                true  "Story UUID"
+// @Param        request  body      ReorderSlidesRequest  true  "Slide IDs in the new order"
+// @Success      200  {array}   domain.Slide
+// @Failure      400  {object}  utils.APIResponse
+// @Failure      404  {object}  utils.APIResponse
+// @Failure      500  {object}  utils.APIResponse
+// @Router       /admin/stories/{uuid}/slides/order [put]
+// @Security     BearerAuth
+func (h *StoryHandler) ReorderSlides(c *gin.Context) {
+	var req ReorderSlidesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slides, err := h.uc.ReorderSlides(c.Request.Context(), c.Param("uuid"), req.SlideIDs)
+	if err != nil {
+		slideErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, slides)
+}
+
+func parseSlideID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid slide id")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func slideErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrSlideSequenceTaken):
+		utils.ErrorResponse(c, http.StatusConflict, err.Error())
+	case errors.Is(err, domain.ErrSlideLimitReached):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, domain.ErrBadParamInput):
+		utils.ErrorResponse(c, http.StatusBadRequest, "slide_ids must contain every slide exactly once")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+	}
 }
\ No newline at end of file
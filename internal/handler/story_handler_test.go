@@ -23,27 +23,64 @@ func TestStoryHandler_GetAll(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockUC := new(mocks.StoryUseCaseMock)
-		h := handler.NewStoryHandler(mockUC)
+		h := handler.NewStoryHandler(mockUC, nil)
 
 		expectedStories := []domain.Story{
 			{Title: "Story 1", UUID: "uuid-1"},
 			{Title: "Story 2", UUID: "uuid-2"},
 		}
 
-		mockUC.On("GetAll", mock.Anything, 1, 10, "desc").Return(expectedStories, nil)
+		total := int64(2)
+		expectedQuery := domain.ListQuery{
+			Limit:     10,
+			Sort:      []domain.SortField{{Field: "created_at", Desc: true}, {Field: "title"}},
+			Filters:   map[string]string{"status": "Published", "has_audio": "true"},
+			Cursor:    "abc",
+			WithTotal: true,
+		}
+		mockUC.On("GetAll", mock.Anything, expectedQuery).Return(expectedStories, &domain.PageInfo{NextCursor: "def", PrevCursor: "abc", HasMore: true, Total: &total}, nil)
 
 		r := gin.Default()
 		r.GET("/stories", h.GetAll)
 
 		w := httptest.NewRecorder()
-		req, _ := http.NewRequest(http.MethodGet, "/stories?page=1&limit=10&sort=desc", nil)
+		req, _ := http.NewRequest(http.MethodGet, "/stories?limit=10&cursor=abc&with_total=true&sort=-created_at,title&filter[has_audio]=1", nil)
 		r.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response map[string]interface{}
+		var response struct {
+			Meta domain.PageInfo `json:"meta"`
+		}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
+		assert.Equal(t, "def", response.Meta.NextCursor)
+		assert.True(t, response.Meta.HasMore)
+		assert.Equal(t, total, *response.Meta.Total)
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		mockUC := new(mocks.StoryUseCaseMock)
+		h := handler.NewStoryHandler(mockUC, nil)
+
+		r := gin.Default()
+		r.GET("/stories", h.GetAll)
+
+		for _, query := range []string{
+			"sort=created_at%20desc",
+			"sort=-password",
+			"filter[author]=x",
+			"filter[category]=not-a-uuid",
+			"filter[status]=Draft",
+			"with_total=maybe",
+		} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/stories?"+query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+		mockUC.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
 }
 
@@ -52,9 +89,9 @@ func TestStoryHandler_Create(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockUC := new(mocks.StoryUseCaseMock)
-		h := handler.NewStoryHandler(mockUC)
+		h := handler.NewStoryHandler(mockUC, nil)
 
-		mockUC.On("Create", mock.Anything, "Title", "Desc", uint(1), mock.Anything, mock.Anything).
+		mockUC.On("Create", mock.Anything, "Title", "Desc", "cat-uuid", "", mock.Anything, mock.Anything).
 			Return(&domain.Story{Title: "Title"}, nil)
 
 		r := gin.Default()
@@ -65,7 +102,7 @@ func TestStoryHandler_Create(t *testing.T) {
 
 		_ = writer.WriteField("title", "Title")
 		_ = writer.WriteField("description", "Desc")
-		_ = writer.WriteField("category_id", "1")
+		_ = writer.WriteField("category_id", "cat-uuid")
 
 		part, _ := writer.CreateFormFile("file", "test.jpg")
 		part.Write([]byte("dummy image content"))
@@ -78,7 +115,7 @@ func TestStoryHandler_Create(t *testing.T) {
 
 		r.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.StatusCreated, w.Code)
 	})
 }
 
@@ -87,12 +124,12 @@ func TestStoryHandler_Delete(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockUC := new(mocks.StoryUseCaseMock)
-		h := handler.NewStoryHandler(mockUC)
+		h := handler.NewStoryHandler(mockUC, nil)
 
 		mockUC.On("Delete", mock.Anything, "uuid-123").Return(nil)
 
 		r := gin.Default()
-		r.DELETE("/stories/:id", h.Delete)
+		r.DELETE("/stories/:uuid", h.Delete)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodDelete, "/stories/uuid-123", nil)
@@ -100,4 +137,91 @@ func TestStoryHandler_Delete(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
+}
+
+func TestStoryHandler_AddSlide(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"limit reached", domain.ErrSlideLimitReached, http.StatusUnprocessableEntity},
+		{"sequence taken", domain.ErrSlideSequenceTaken, http.StatusConflict},
+		{"story not found", domain.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUC := new(mocks.StoryUseCaseMock)
+			h := handler.NewStoryHandler(mockUC, nil)
+
+			mockUC.On("AddSlide", mock.Anything, "s-1", "Content", 3, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+			r := gin.Default()
+			r.POST("/stories/:uuid/slides", h.AddSlide)
+
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			_ = writer.WriteField("content", "Content")
+			_ = writer.WriteField("sequence", "3")
+			writer.Close()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/stories/s-1/slides", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func TestStoryHandler_GetOneFavourited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name   string
+		userID string
+		want   string
+	}{
+		{name: "with token", userID: "user-1", want: `"favourited":true`},
+		{name: "anonymous", userID: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUC := new(mocks.StoryUseCaseMock)
+			mockFav := new(mocks.FavouriteUseCaseMock)
+			h := handler.NewStoryHandler(mockUC, mockFav)
+
+			mockUC.On("GetPublished", mock.Anything, "uuid-1").Return(&domain.Story{ID: 1, UUID: "uuid-1", Status: domain.StatusPublished}, nil)
+			mockFav.On("MarkFavourited", mock.Anything, "user-1", mock.AnythingOfType("[]domain.Story")).
+				Run(func(args mock.Arguments) {
+					flag := true
+					args.Get(2).([]domain.Story)[0].Favourited = &flag
+				}).
+				Return(nil)
+
+			r := gin.Default()
+			r.GET("/stories/:uuid", func(c *gin.Context) {
+				if tc.userID != "" {
+					c.Set("user_id", tc.userID)
+				}
+			}, h.GetOne)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/stories/uuid-1", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if tc.want != "" {
+				assert.Contains(t, w.Body.String(), tc.want)
+			} else {
+				assert.NotContains(t, w.Body.String(), "favourited")
+				mockFav.AssertNotCalled(t, "MarkFavourited", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/stretchr/testify/mock"
@@ -35,16 +36,37 @@ func (m *CategoryRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*d
 	return args.Get(0).(*domain.Category), args.Error(1)
 }
 
-func (m *CategoryRepositoryMock) GetAll(ctx context.Context) ([]domain.Category, error) {
-	args := m.Called(ctx)
+func (m *CategoryRepositoryMock) GetByUUIDs(ctx context.Context, uuids []string) ([]domain.Category, error) {
+	args := m.Called(ctx, uuids)
+	return args.Get(0).([]domain.Category), args.Error(1)
+}
+
+func (m *CategoryRepositoryMock) ListByType(ctx context.Context, categoryType string) ([]domain.Category, error) {
+	args := m.Called(ctx, categoryType)
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
+func (m *CategoryRepositoryMock) CountChildren(ctx context.Context, id uint) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *CategoryRepositoryMock) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Category, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Category), page, args.Error(2)
+}
+
 func (m *CategoryRepositoryMock) Search(ctx context.Context, query string) ([]domain.Category, error) {
 	args := m.Called(ctx, query)
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
+func (m *CategoryRepositoryMock) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]domain.SearchSuggestion), args.Error(1)
+}
+
 func (m *CategoryRepositoryMock) Update(ctx context.Context, category *domain.Category) error {
 	args := m.Called(ctx, category)
 	return args.Error(0)
@@ -55,11 +77,21 @@ func (m *CategoryRepositoryMock) Delete(ctx context.Context, uuid string) error
 	return args.Error(0)
 }
 
+func (m *CategoryRepositoryMock) CountReferences(ctx context.Context, id uint) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *CategoryRepositoryMock) UpdateColor(ctx context.Context, id uint, color string) error {
 	args := m.Called(ctx, id, color)
 	return args.Error(0)
 }
 
+func (m *CategoryRepositoryMock) UpdateDominantColor(ctx context.Context, category *domain.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
 type StoryRepositoryMock struct {
 	mock.Mock
 }
@@ -85,14 +117,34 @@ func (m *StoryRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*doma
 	return args.Get(0).(*domain.Story), args.Error(1)
 }
 
-func (m *StoryRepositoryMock) GetAll(ctx context.Context, page, limit int, sort string) ([]domain.Story, error) {
-	args := m.Called(ctx, page, limit, sort)
-	return args.Get(0).([]domain.Story), args.Error(1)
+func (m *StoryRepositoryMock) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Story, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Story), page, args.Error(2)
 }
 
-func (m *StoryRepositoryMock) Search(ctx context.Context, query string) ([]domain.Story, error) {
-	args := m.Called(ctx, query)
-	return args.Get(0).([]domain.Story), args.Error(1)
+func (m *StoryRepositoryMock) Search(ctx context.Context, params domain.SearchParams) ([]domain.StorySearchHit, *domain.PageInfo, error) {
+	args := m.Called(ctx, params)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.StorySearchHit), page, args.Error(2)
+}
+
+func (m *StoryRepositoryMock) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]domain.SearchSuggestion), args.Error(1)
+}
+
+func (m *StoryRepositoryMock) SearchSlides(ctx context.Context, params domain.SearchParams) ([]domain.SearchHit, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]domain.SearchHit), args.Error(1)
+}
+
+func (m *StoryRepositoryMock) SearchFacets(ctx context.Context, params domain.SearchParams) (*domain.SearchFacets, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SearchFacets), args.Error(1)
 }
 
 func (m *StoryRepositoryMock) Update(ctx context.Context, story *domain.Story) error {
@@ -105,21 +157,84 @@ func (m *StoryRepositoryMock) Delete(ctx context.Context, uuid string) error {
 	return args.Error(0)
 }
 
+func (m *StoryRepositoryMock) UpdateLifecycle(ctx context.Context, s *domain.Story, from string) error {
+	args := m.Called(ctx, s, from)
+	return args.Error(0)
+}
+
 func (m *StoryRepositoryMock) UpdateColor(ctx context.Context, id uint, color string) error {
 	args := m.Called(ctx, id, color)
 	return args.Error(0)
 }
 
-func (m *StoryRepositoryMock) CreateSlide(ctx context.Context, slide *domain.Slide) error {
-	args := m.Called(ctx, slide)
+func (m *StoryRepositoryMock) CreateSlide(ctx context.Context, slide *domain.Slide, limit int) error {
+	args := m.Called(ctx, slide, limit)
 	return args.Error(0)
 }
 
+func (m *StoryRepositoryMock) CheckDuplicate(ctx context.Context, title, description string) (bool, error) {
+	args := m.Called(ctx, title, description)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *StoryRepositoryMock) CountSlides(ctx context.Context, storyID uint) (int64, error) {
 	args := m.Called(ctx, storyID)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *StoryRepositoryMock) GetSlideByID(ctx context.Context, id uint) (*domain.Slide, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Slide), args.Error(1)
+}
+
+func (m *StoryRepositoryMock) UpdateSlide(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) UpdateDominantColor(ctx context.Context, s *domain.Story) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) SetSlideJob(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) UpdateSlideMedia(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) DeleteSlide(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) ReorderSlides(ctx context.Context, storyID uint, slideIDs []uint) error {
+	args := m.Called(ctx, storyID, slideIDs)
+	return args.Error(0)
+}
+
+func (m *StoryRepositoryMock) ListScheduled(ctx context.Context, limit int) ([]domain.ScheduledChange, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.ScheduledChange), args.Error(1)
+}
+
+func (m *StoryRepositoryMock) ApplyDueSchedules(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *StoryRepositoryMock) Import(ctx context.Context, s *domain.Story) (bool, error) {
+	args := m.Called(ctx, s)
+	return args.Bool(0), args.Error(1)
+}
+
 type RedisRepositoryMock struct {
 	mock.Mock
 }
@@ -142,4 +257,517 @@ func (m *RedisRepositoryMock) Del(ctx context.Context, key string) error {
 func (m *RedisRepositoryMock) DeletePrefix(ctx context.Context, prefix string) error {
 	args := m.Called(ctx, prefix)
 	return args.Error(0)
+}
+
+type StorageRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *StorageRepositoryMock) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	args := m.Called(ctx, file, filename)
+	return args.String(0), args.Error(1)
+}
+
+func (m *StorageRepositoryMock) Delete(ctx context.Context, fileURL string) error {
+	args := m.Called(ctx, fileURL)
+	return args.Error(0)
+}
+
+func (m *StorageRepositoryMock) EnsureContainer(ctx context.Context, containerName string) error {
+	args := m.Called(ctx, containerName)
+	return args.Error(0)
+}
+
+func (m *StorageRepositoryMock) UploadToContainer(ctx context.Context, file io.Reader, containerName, filename string) (string, error) {
+	args := m.Called(ctx, file, containerName, filename)
+	return args.String(0), args.Error(1)
+}
+
+func (m *StorageRepositoryMock) DeleteFromContainer(ctx context.Context, containerName, fileURL string) error {
+	args := m.Called(ctx, containerName, fileURL)
+	return args.Error(0)
+}
+
+func (m *StorageRepositoryMock) ListContainer(ctx context.Context, containerName string) ([]domain.StoredObject, error) {
+	args := m.Called(ctx, containerName)
+	return args.Get(0).([]domain.StoredObject), args.Error(1)
+}
+
+func (m *StorageRepositoryMock) OpenFromContainer(ctx context.Context, containerName, fileURL string) (io.ReadCloser, error) {
+	args := m.Called(ctx, containerName, fileURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+type ChapterRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ChapterRepositoryMock) Create(ctx context.Context, c *domain.Chapter) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) Update(ctx context.Context, c *domain.Chapter) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) ReorderChapters(ctx context.Context, storyID uint, chapterUUIDs []string) error {
+	args := m.Called(ctx, storyID, chapterUUIDs)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*domain.Chapter, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Chapter), args.Error(1)
+}
+
+func (m *ChapterRepositoryMock) GetAllByStoryID(ctx context.Context, storyID uint) ([]domain.Chapter, error) {
+	args := m.Called(ctx, storyID)
+	return args.Get(0).([]domain.Chapter), args.Error(1)
+}
+
+func (m *ChapterRepositoryMock) ListByStoryID(ctx context.Context, storyID uint, q domain.ListQuery) ([]domain.Chapter, *domain.PageInfo, error) {
+	args := m.Called(ctx, storyID, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Chapter), page, args.Error(2)
+}
+
+func (m *ChapterRepositoryMock) Search(ctx context.Context, params domain.SearchParams) ([]domain.SearchHit, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]domain.SearchHit), args.Error(1)
+}
+
+func (m *ChapterRepositoryMock) Delete(ctx context.Context, uuid string) error {
+	args := m.Called(ctx, uuid)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) CreateSlide(ctx context.Context, s *domain.Slide, limit int) error {
+	args := m.Called(ctx, s, limit)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) CountSlides(ctx context.Context, chapterID uint) (int64, error) {
+	args := m.Called(ctx, chapterID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ChapterRepositoryMock) UpdateSlide(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) DeleteSlide(ctx context.Context, s *domain.Slide) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *ChapterRepositoryMock) ReorderSlides(ctx context.Context, chapterID uint, slideIDs []uint) error {
+	args := m.Called(ctx, chapterID, slideIDs)
+	return args.Error(0)
+}
+
+type PreferenceRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PreferenceRepositoryMock) GetChoices(ctx context.Context, userID string) (*domain.UserPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserPreferences), args.Error(1)
+}
+
+func (m *PreferenceRepositoryMock) ReplaceChoices(ctx context.Context, userID string, choices domain.PreferenceChoices) error {
+	args := m.Called(ctx, userID, choices)
+	return args.Error(0)
+}
+
+func (m *PreferenceRepositoryMock) UpdateChoices(ctx context.Context, userID string, add, remove domain.PreferenceChoices, limit int) error {
+	args := m.Called(ctx, userID, add, remove, limit)
+	return args.Error(0)
+}
+
+type HistoryRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *HistoryRepositoryMock) Create(ctx context.Context, h *domain.ListeningHistory) error {
+	args := m.Called(ctx, h)
+	return args.Error(0)
+}
+
+func (m *HistoryRepositoryMock) GetLatestPerStory(ctx context.Context, userID string, limit int) ([]domain.ListeningHistory, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]domain.ListeningHistory), args.Error(1)
+}
+
+func (m *HistoryRepositoryMock) GetLatestForStory(ctx context.Context, userID string, storyID uint) (*domain.ListeningHistory, error) {
+	args := m.Called(ctx, userID, storyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ListeningHistory), args.Error(1)
+}
+
+type RecommendationRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RecommendationRepositoryMock) GetForUser(ctx context.Context, userID string, limit int) ([]domain.Recommendation, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]domain.Recommendation), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) MarkRequested(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) ReplaceForUser(ctx context.Context, userID string, recs []domain.Recommendation) error {
+	args := m.Called(ctx, userID, recs)
+	return args.Error(0)
+}
+
+func (m *RecommendationRepositoryMock) GetActiveUserIDs(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) GetUserSignals(ctx context.Context, userID string) (*domain.UserSignals, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserSignals), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) GetCandidateStories(ctx context.Context) ([]domain.Story, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Story), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) GetCategoryPopularity(ctx context.Context, since time.Time) (map[uint]int, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) GetStoryFavourites(ctx context.Context) (map[uint]int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[uint]int), args.Error(1)
+}
+
+func (m *RecommendationRepositoryMock) WithLeaderLock(ctx context.Context, fn func() error) error {
+	args := m.Called(ctx)
+	if !args.Bool(0) {
+		return args.Error(1)
+	}
+	return fn()
+}
+
+type RevisionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *RevisionRepositoryMock) Record(ctx context.Context, rev *domain.Revision, baseline domain.RevisionFields) error {
+	args := m.Called(ctx, rev, baseline)
+	return args.Error(0)
+}
+
+func (m *RevisionRepositoryMock) ListByStory(ctx context.Context, storyID uint, entityType string, q domain.ListQuery) ([]domain.Revision, *domain.PageInfo, error) {
+	args := m.Called(ctx, storyID, entityType, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Revision), page, args.Error(2)
+}
+
+func (m *RevisionRepositoryMock) GetByID(ctx context.Context, id uint) (*domain.Revision, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Revision), args.Error(1)
+}
+
+type CollectionRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *CollectionRepositoryMock) Create(ctx context.Context, c *domain.Collection) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *CollectionRepositoryMock) Update(ctx context.Context, c *domain.Collection) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *CollectionRepositoryMock) UpdateDominantColor(ctx context.Context, c *domain.Collection) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *CollectionRepositoryMock) Delete(ctx context.Context, uuid string) error {
+	args := m.Called(ctx, uuid)
+	return args.Error(0)
+}
+
+func (m *CollectionRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*domain.Collection, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Collection), args.Error(1)
+}
+
+func (m *CollectionRepositoryMock) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Collection, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Collection), page, args.Error(2)
+}
+
+func (m *CollectionRepositoryMock) ListStories(ctx context.Context, collectionID uint, publishedOnly bool) ([]domain.Story, error) {
+	args := m.Called(ctx, collectionID, publishedOnly)
+	return args.Get(0).([]domain.Story), args.Error(1)
+}
+
+func (m *CollectionRepositoryMock) SetStories(ctx context.Context, collectionID uint, storyUUIDs []string) error {
+	args := m.Called(ctx, collectionID, storyUUIDs)
+	return args.Error(0)
+}
+
+func (m *CollectionRepositoryMock) SeriesForStory(ctx context.Context, storyID uint) ([]domain.SeriesNavigation, error) {
+	args := m.Called(ctx, storyID)
+	return args.Get(0).([]domain.SeriesNavigation), args.Error(1)
+}
+
+type FavouriteRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *FavouriteRepositoryMock) Save(ctx context.Context, f *domain.Favourite) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *FavouriteRepositoryMock) Remove(ctx context.Context, userID string, storyID uint, chapterID *uint) error {
+	args := m.Called(ctx, userID, storyID, chapterID)
+	return args.Error(0)
+}
+
+func (m *FavouriteRepositoryMock) List(ctx context.Context, userID string, q domain.ListQuery) ([]domain.Favourite, *domain.PageInfo, error) {
+	args := m.Called(ctx, userID, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Favourite), page, args.Error(2)
+}
+
+func (m *FavouriteRepositoryMock) FavouritedStoryIDs(ctx context.Context, userID string, storyIDs []uint) (map[uint]bool, error) {
+	args := m.Called(ctx, userID, storyIDs)
+	return args.Get(0).(map[uint]bool), args.Error(1)
+}
+
+func (m *FavouriteRepositoryMock) CreateFolder(ctx context.Context, f *domain.FavouriteFolder) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *FavouriteRepositoryMock) UpdateFolder(ctx context.Context, f *domain.FavouriteFolder) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *FavouriteRepositoryMock) DeleteFolder(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *FavouriteRepositoryMock) GetFolder(ctx context.Context, userID, uuid string) (*domain.FavouriteFolder, error) {
+	args := m.Called(ctx, userID, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FavouriteFolder), args.Error(1)
+}
+
+func (m *FavouriteRepositoryMock) ListFolders(ctx context.Context, userID string) ([]domain.FavouriteFolder, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.FavouriteFolder), args.Error(1)
+}
+
+func (m *FavouriteRepositoryMock) MostFavourited(ctx context.Context, since time.Time, limit int) ([]domain.StoryPopularity, error) {
+	args := m.Called(ctx, since, limit)
+	return args.Get(0).([]domain.StoryPopularity), args.Error(1)
+}
+
+type ReviewRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ReviewRepositoryMock) Upsert(ctx context.Context, r *domain.Review) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *ReviewRepositoryMock) Update(ctx context.Context, r *domain.Review) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *ReviewRepositoryMock) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ReviewRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*domain.Review, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) GetByUser(ctx context.Context, storyID uint, userID string) (*domain.Review, error) {
+	args := m.Called(ctx, storyID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *ReviewRepositoryMock) ListByStory(ctx context.Context, storyID uint, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	args := m.Called(ctx, storyID, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Review), page, args.Error(2)
+}
+
+func (m *ReviewRepositoryMock) List(ctx context.Context, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Review), page, args.Error(2)
+}
+
+type ImportJobRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *ImportJobRepositoryMock) Create(ctx context.Context, j *domain.ImportJob) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*domain.ImportJob, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportJob), args.Error(1)
+}
+
+func (m *ImportJobRepositoryMock) ClaimNext(ctx context.Context) (*domain.ImportJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportJob), args.Error(1)
+}
+
+func (m *ImportJobRepositoryMock) Heartbeat(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepositoryMock) Finish(ctx context.Context, j *domain.ImportJob) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepositoryMock) FailStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, startedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type JobRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *JobRepositoryMock) Enqueue(ctx context.Context, j *domain.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) GetByUUID(ctx context.Context, uuid string) (*domain.Job, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *JobRepositoryMock) Claim(ctx context.Context) (*domain.Job, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *JobRepositoryMock) Complete(ctx context.Context, j *domain.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) Retry(ctx context.Context, j *domain.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) Bury(ctx context.Context, j *domain.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) Heartbeat(ctx context.Context, j *domain.Job) error {
+	args := m.Called(ctx, j)
+	return args.Error(0)
+}
+
+func (m *JobRepositoryMock) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, []domain.Job, error) {
+	args := m.Called(ctx, lockedBefore)
+	jobs, _ := args.Get(1).([]domain.Job)
+	return args.Get(0).(int64), jobs, args.Error(2)
+}
+
+func (m *JobRepositoryMock) ListDead(ctx context.Context, limit int) ([]domain.DeadJob, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.DeadJob), args.Error(1)
+}
+
+func (m *JobRepositoryMock) Revive(ctx context.Context, deadID uint) (*domain.Job, error) {
+	args := m.Called(ctx, deadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+// TransactorMock menjalankan fn langsung. InTx bernilai true selama fn berjalan, sehingga
+// test bisa memastikan pemanggilan repository terjadi di dalam transaksi.
+type TransactorMock struct {
+	mock.Mock
+	InTx bool
+}
+
+func (m *TransactorMock) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Called(ctx)
+	m.InTx = true
+	defer func() { m.InTx = false }()
+	return fn(ctx)
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"mime/multipart"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
@@ -14,16 +15,22 @@ type CategoryUseCaseMock struct {
 	mock.Mock
 }
 
-func (m *CategoryUseCaseMock) Create(ctx context.Context, name string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
-	args := m.Called(ctx, name, file, header)
+func (m *CategoryUseCaseMock) Create(ctx context.Context, name, categoryType, parentUUID string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+	args := m.Called(ctx, name, categoryType, parentUUID, file, header)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Category), args.Error(1)
 }
 
-func (m *CategoryUseCaseMock) GetAll(ctx context.Context) ([]domain.Category, error) {
-	args := m.Called(ctx)
+func (m *CategoryUseCaseMock) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Category, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Category), page, args.Error(2)
+}
+
+func (m *CategoryUseCaseMock) Tree(ctx context.Context, categoryType string) ([]domain.Category, error) {
+	args := m.Called(ctx, categoryType)
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
@@ -40,8 +47,8 @@ func (m *CategoryUseCaseMock) Search(ctx context.Context, query string) ([]domai
 	return args.Get(0).([]domain.Category), args.Error(1)
 }
 
-func (m *CategoryUseCaseMock) Update(ctx context.Context, uuid string, name string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
-	args := m.Called(ctx, uuid, name, file, header)
+func (m *CategoryUseCaseMock) Update(ctx context.Context, uuid string, name, categoryType string, parentUUID *string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+	args := m.Called(ctx, uuid, name, categoryType, parentUUID, file, header)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -57,25 +64,74 @@ type StoryUseCaseMock struct {
 	mock.Mock
 }
 
-func (m *StoryUseCaseMock) Create(ctx context.Context, title, desc string, categoryID uint, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
-	args := m.Called(ctx, title, desc, categoryID, file, header)
+func (m *StoryUseCaseMock) Create(ctx context.Context, title, desc string, categoryUUID string, userID string, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
+	args := m.Called(ctx, title, desc, categoryUUID, userID, file, header)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Story), args.Error(1)
 }
 
-func (m *StoryUseCaseMock) GetAll(ctx context.Context, page, limit int, sort string) ([]domain.Story, error) {
-	args := m.Called(ctx, page, limit, sort)
-	return args.Get(0).([]domain.Story), args.Error(1)
+func (m *StoryUseCaseMock) Update(ctx context.Context, storyUUID string, title, desc, categoryUUID string, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
+	args := m.Called(ctx, storyUUID, title, desc, categoryUUID, file, header)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Story), args.Error(1)
 }
 
-func (m *StoryUseCaseMock) Search(ctx context.Context, query string) (*[]domain.Story, error) {
-	args := m.Called(ctx, query)
+func (m *StoryUseCaseMock) Transition(ctx context.Context, storyUUID, status, role string) (*domain.Story, error) {
+	args := m.Called(ctx, storyUUID, status, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Story), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Story, *domain.PageInfo, error) {
+	args := m.Called(ctx, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Story), page, args.Error(2)
+}
+
+func (m *StoryUseCaseMock) GetByUUID(ctx context.Context, uuid string) (*domain.Story, error) {
+	args := m.Called(ctx, uuid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Story), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) Schedule(ctx context.Context, storyUUID string, publishAt, unpublishAt *time.Time) (*domain.Story, error) {
+	args := m.Called(ctx, storyUUID, publishAt, unpublishAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Story), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) ListScheduled(ctx context.Context, limit int) ([]domain.ScheduledChange, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]domain.ScheduledChange), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) RunScheduler(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) GetPublished(ctx context.Context, uuid string) (*domain.Story, error) {
+	args := m.Called(ctx, uuid)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*[]domain.Story), args.Error(1)
+	return args.Get(0).(*domain.Story), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) Search(ctx context.Context, params domain.SearchParams) ([]domain.StorySearchHit, *domain.PageInfo, error) {
+	args := m.Called(ctx, params)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.StorySearchHit), page, args.Error(2)
 }
 
 func (m *StoryUseCaseMock) Delete(ctx context.Context, uuid string) error {
@@ -89,4 +145,116 @@ func (m *StoryUseCaseMock) AddSlide(ctx context.Context, storyUUID string, conte
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Slide), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) UpdateSlide(ctx context.Context, storyUUID string, slideID uint, content *string, file multipart.File, header *multipart.FileHeader) (*domain.Slide, error) {
+	args := m.Called(ctx, storyUUID, slideID, content, file, header)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Slide), args.Error(1)
+}
+
+func (m *StoryUseCaseMock) DeleteSlide(ctx context.Context, storyUUID string, slideID uint) error {
+	args := m.Called(ctx, storyUUID, slideID)
+	return args.Error(0)
+}
+
+func (m *StoryUseCaseMock) ReorderSlides(ctx context.Context, storyUUID string, slideIDs []uint) ([]domain.Slide, error) {
+	args := m.Called(ctx, storyUUID, slideIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Slide), args.Error(1)
+}
+
+type FavouriteUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *FavouriteUseCaseMock) AddStory(ctx context.Context, userID, storyUUID, folderUUID string) (*domain.Favourite, error) {
+	args := m.Called(ctx, userID, storyUUID, folderUUID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
+func (m *FavouriteUseCaseMock) AddChapter(ctx context.Context, userID, chapterUUID, folderUUID string) (*domain.Favourite, error) {
+	args := m.Called(ctx, userID, chapterUUID, folderUUID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
+func (m *FavouriteUseCaseMock) RemoveStory(ctx context.Context, userID, storyUUID string) error {
+	args := m.Called(ctx, userID, storyUUID)
+	return args.Error(0)
+}
+
+func (m *FavouriteUseCaseMock) RemoveChapter(ctx context.Context, userID, chapterUUID string) error {
+	args := m.Called(ctx, userID, chapterUUID)
+	return args.Error(0)
+}
+
+func (m *FavouriteUseCaseMock) List(ctx context.Context, userID string, q domain.ListQuery) ([]domain.Favourite, *domain.PageInfo, error) {
+	args := m.Called(ctx, userID, q)
+	page, _ := args.Get(1).(*domain.PageInfo)
+	return args.Get(0).([]domain.Favourite), page, args.Error(2)
+}
+
+func (m *FavouriteUseCaseMock) MarkFavourited(ctx context.Context, userID string, stories []domain.Story) error {
+	args := m.Called(ctx, userID, stories)
+	return args.Error(0)
+}
+
+func (m *FavouriteUseCaseMock) CreateFolder(ctx context.Context, userID, name string) (*domain.FavouriteFolder, error) {
+	args := m.Called(ctx, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FavouriteFolder), args.Error(1)
+}
+
+func (m *FavouriteUseCaseMock) RenameFolder(ctx context.Context, userID, folderUUID, name string) (*domain.FavouriteFolder, error) {
+	args := m.Called(ctx, userID, folderUUID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FavouriteFolder), args.Error(1)
+}
+
+func (m *FavouriteUseCaseMock) DeleteFolder(ctx context.Context, userID, folderUUID string) error {
+	args := m.Called(ctx, userID, folderUUID)
+	return args.Error(0)
+}
+
+func (m *FavouriteUseCaseMock) ListFolders(ctx context.Context, userID string) ([]domain.FavouriteFolder, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.FavouriteFolder), args.Error(1)
+}
+
+func (m *FavouriteUseCaseMock) Popular(ctx context.Context, since time.Time, limit int) ([]domain.StoryPopularity, error) {
+	args := m.Called(ctx, since, limit)
+	return args.Get(0).([]domain.StoryPopularity), args.Error(1)
+}
+
+type RecommendationUseCaseMock struct {
+	mock.Mock
+}
+
+func (m *RecommendationUseCaseMock) GetForUser(ctx context.Context, userID string) ([]domain.Recommendation, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Recommendation), args.Error(1)
+}
+
+func (m *RecommendationUseCaseMock) GenerateForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *RecommendationUseCaseMock) GenerateAll(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
 }
\ No newline at end of file
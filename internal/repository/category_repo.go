@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"khalif-stories/internal/domain"
 
@@ -20,7 +21,7 @@ func NewCategoryRepository(db *gorm.DB) *CategoryRepo {
 
 func (r *CategoryRepo) GetByName(ctx context.Context, name string) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.WithContext(ctx).Model(&domain.Category{}).Where("name = ?", name).First(&category).Error
+	err := dbFrom(ctx, r.db).Model(&domain.Category{}).Where("name = ?", name).First(&category).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -32,28 +33,71 @@ func (r *CategoryRepo) GetByName(ctx context.Context, name string) (*domain.Cate
 
 func (r *CategoryRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.WithContext(ctx).Model(&domain.Category{}).Where("uuid = ?", uuid).First(&category).Error
+	err := dbFrom(ctx, r.db).Model(&domain.Category{}).Preload("Parent").Where("uuid = ?", uuid).First(&category).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &category, nil
 }
 
+func (r *CategoryRepo) GetByUUIDs(ctx context.Context, uuids []string) ([]domain.Category, error) {
+	var categories []domain.Category
+	if len(uuids) == 0 {
+		return categories, nil
+	}
+	err := dbFrom(ctx, r.db).Where("uuid IN ?", uuids).Find(&categories).Error
+	return categories, err
+}
+
+// ListByType mengembalikan semua kategori satu section, atau semua section jika
+// categoryType kosong, terurut nama. Dipakai untuk menyusun pohon kategori.
+func (r *CategoryRepo) ListByType(ctx context.Context, categoryType string) ([]domain.Category, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Category{})
+	if categoryType != "" {
+		db = db.Where("type = ?", categoryType)
+	}
+
+	var categories []domain.Category
+	err := db.Order("name ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *CategoryRepo) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := dbFrom(ctx, r.db).Model(&domain.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountReferences menghitung story dan pilihan user yang memakai kategori.
+func (r *CategoryRepo) CountReferences(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := dbFrom(ctx, r.db).Raw(`SELECT
+		(SELECT count(*) FROM stories WHERE category_id = ?) +
+		(SELECT count(*) FROM user_choice_stories WHERE category_id = ?) +
+		(SELECT count(*) FROM user_choice_dakwahs WHERE category_id = ?) +
+		(SELECT count(*) FROM user_choice_hadists WHERE category_id = ?)`, id, id, id, id).Scan(&count).Error
+	return count, err
+}
+
 func (r *CategoryRepo) Create(ctx context.Context, c *domain.Category) error {
-	return r.db.WithContext(ctx).Create(c).Error
+	return dbFrom(ctx, r.db).Omit("Parent", "Children", "Stories").Create(c).Error
 }
 
+// Update tidak menulis warna dominan, kolom itu diisi job palette lewat UpdateDominantColor.
 func (r *CategoryRepo) Update(ctx context.Context, c *domain.Category) error {
-	return r.db.WithContext(ctx).Save(c).Error
+	return dbFrom(ctx, r.db).Omit("Parent", "Children", "Stories", "DominantColor").Save(c).Error
 }
 
 func (r *CategoryRepo) Delete(ctx context.Context, uuid string) error {
 	var category domain.Category
-	if err := r.db.WithContext(ctx).Select("id").Where("uuid = ?", uuid).First(&category).Error; err != nil {
+	if err := dbFrom(ctx, r.db).Select("id").Where("uuid = ?", uuid).First(&category).Error; err != nil {
 		return err
 	}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Exec("DELETE FROM slides WHERE story_id IN (SELECT id FROM stories WHERE category_id = ?)", category.ID).Error; err != nil {
 			return err
 		}
@@ -64,19 +108,78 @@ func (r *CategoryRepo) Delete(ctx context.Context, uuid string) error {
 	})
 }
 
-func (r *CategoryRepo) GetAll(ctx context.Context) ([]domain.Category, error) {
-	var cats []domain.Category
-	err := r.db.WithContext(ctx).Order("id ASC").Find(&cats).Error
-	return cats, err
+var categoryKeyset = keyset[domain.Category]{
+	columns: map[string]keysetColumn{
+		"created_at": {Column: "created_at", Kind: keyTime},
+		"name":       {Column: "name", Kind: keyString},
+	},
+	value: func(c domain.Category, field string) interface{} {
+		if field == "name" {
+			return c.Name
+		}
+		return c.CreatedAt
+	},
+	id: func(c domain.Category) uint { return c.ID },
+}
+
+func (r *CategoryRepo) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Category, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Category{})
+
+	for name, value := range q.Filters {
+		switch name {
+		case domain.FilterType:
+			db = db.Where("type = ?", value)
+		case domain.FilterParent:
+			db = db.Where("parent_id = (SELECT id FROM categories WHERE uuid = ?)", value)
+		default:
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+
+	return paginate(db, q, categoryKeyset, "Parent")
 }
 
 func (r *CategoryRepo) Search(ctx context.Context, query string) ([]domain.Category, error) {
 	var categories []domain.Category
 	pattern := "%" + query + "%"
-	err := r.db.WithContext(ctx).Where("name ILIKE ?", pattern).Limit(10).Find(&categories).Error
+	err := dbFrom(ctx, r.db).
+		Where("name ILIKE ? OR normalize_translit(?) <% normalize_translit(name)", pattern, query).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "word_similarity(normalize_translit(?), normalize_translit(name)) DESC",
+			Vars:               []interface{}{query},
+			WithoutParentheses: true,
+		}}).
+		Limit(10).Find(&categories).Error
 	return categories, err
 }
 
+func (r *CategoryRepo) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
+	var suggestions []domain.SearchSuggestion
+	err := dbFrom(ctx, r.db).Model(&domain.Category{}).
+		Select("uuid AS id, name AS text, word_similarity(normalize_translit(?), normalize_translit(name)) AS score", query).
+		Where("normalize_translit(?) <% normalize_translit(name) OR normalize_translit(name) LIKE '%' || normalize_translit(?) || '%'", query, query).
+		Order("score DESC").Limit(limit).Scan(&suggestions).Error
+	for i := range suggestions {
+		suggestions[i].Type = domain.SearchTypeCategory
+	}
+	return suggestions, err
+}
+
 func (r *CategoryRepo) UpdateColor(ctx context.Context, id uint, color string) error {
-	return r.db.WithContext(ctx).Model(&domain.Category{}).Where("id = ?", id).Update("dominant_color", color).Error
+	return dbFrom(ctx, r.db).Model(&domain.Category{}).Where("id = ?", id).Update("dominant_color", color).Error
+}
+
+// UpdateDominantColor hanya menulis warna dominan. Mengembalikan ErrConflict jika
+// gambar kategori sudah bukan c.ImageURL.
+func (r *CategoryRepo) UpdateDominantColor(ctx context.Context, c *domain.Category) error {
+	res := dbFrom(ctx, r.db).Model(&domain.Category{}).
+		Where("id = ? AND image_url = ?", c.ID, c.ImageURL).
+		Update("dominant_color", c.DominantColor)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
 }
\ No newline at end of file
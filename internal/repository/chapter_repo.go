@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"khalif-stories/internal/domain"
 
@@ -17,35 +20,145 @@ func NewChapterRepository(db *gorm.DB) *ChapterRepo {
 	return &ChapterRepo{db: db}
 }
 
+// Create menaruh chapter baru di posisi terakhir story. Baris story dikunci agar dua
+// chapter yang dibuat bersamaan tidak mendapat posisi yang sama.
 func (r *ChapterRepo) Create(ctx context.Context, c *domain.Chapter) error {
-	return r.db.WithContext(ctx).Create(c).Error
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM stories WHERE id = ? FOR UPDATE", c.StoryID).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&domain.Chapter{}).
+			Select("COALESCE(MAX(position), 0)").
+			Where("story_id = ?", c.StoryID).
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		c.Position = last + 1
+		return tx.Create(c).Error
+	})
+}
+
+func (r *ChapterRepo) Update(ctx context.Context, c *domain.Chapter) error {
+	return dbFrom(ctx, r.db).Omit("Slides").Save(c).Error
+}
+
+// ReorderChapters menulis ulang position menjadi 1..n sesuai urutan chapterUUIDs, yang
+// harus berisi tepat semua chapter milik story tanpa duplikat.
+func (r *ChapterRepo) ReorderChapters(ctx context.Context, storyID uint, chapterUUIDs []string) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current []string
+		if err := tx.Model(&domain.Chapter{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("story_id = ?", storyID).
+			Pluck("uuid", &current).Error; err != nil {
+			return err
+		}
+		if len(current) != len(chapterUUIDs) {
+			return domain.ErrBadParamInput
+		}
+
+		owned := make(map[string]bool, len(current))
+		for _, id := range current {
+			owned[id] = true
+		}
+		for _, id := range chapterUUIDs {
+			if !owned[id] {
+				return domain.ErrBadParamInput
+			}
+			delete(owned, id)
+		}
+		if len(chapterUUIDs) == 0 {
+			return nil
+		}
+
+		// uq_chapters_story_position DEFERRABLE sehingga pertukaran posisi dalam satu
+		// statement tidak dianggap duplikat
+		return tx.Exec(`UPDATE chapters c SET position = o.pos, updated_at = now()
+			FROM unnest(string_to_array(?, ',')::uuid[]) WITH ORDINALITY AS o(uuid, pos)
+			WHERE c.uuid = o.uuid AND c.story_id = ?`, strings.Join(chapterUUIDs, ","), storyID).Error
+	})
 }
 
 func (r *ChapterRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Chapter, error) {
 	var chapter domain.Chapter
 	// Preload Slides dengan urutan sequence
-	err := r.db.WithContext(ctx).
+	err := dbFrom(ctx, r.db).
 		Preload("Slides", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sequence ASC")
 		}).
 		Where("uuid = ?", uuid).
 		First(&chapter).Error
-	return &chapter, err
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &chapter, nil
 }
 
 func (r *ChapterRepo) GetAllByStoryID(ctx context.Context, storyID uint) ([]domain.Chapter, error) {
 	var chapters []domain.Chapter
-	err := r.db.WithContext(ctx).Where("story_id = ?", storyID).Find(&chapters).Error
+	err := dbFrom(ctx, r.db).Where("story_id = ?", storyID).Order("position ASC, id ASC").Find(&chapters).Error
 	return chapters, err
 }
 
+var chapterKeyset = keyset[domain.Chapter]{
+	columns: map[string]keysetColumn{
+		"position":   {Column: "position", Kind: keyInt},
+		"created_at": {Column: "created_at", Kind: keyTime},
+	},
+	value: func(c domain.Chapter, field string) interface{} {
+		if field == "position" {
+			return c.Position
+		}
+		return c.CreatedAt
+	},
+	id: func(c domain.Chapter) uint { return c.ID },
+}
+
+func (r *ChapterRepo) ListByStoryID(ctx context.Context, storyID uint, q domain.ListQuery) ([]domain.Chapter, *domain.PageInfo, error) {
+	base := dbFrom(ctx, r.db).Model(&domain.Chapter{}).Where("story_id = ?", storyID)
+	if status := q.Filters[domain.FilterStatus]; status != "" {
+		base = base.Where("status = ?", status)
+	}
+	return paginate(base, q, chapterKeyset)
+}
+
+// Search mencari chapter yang isi slide-nya cocok dengan query, skor diambil dari slide terbaik.
+func (r *ChapterRepo) Search(ctx context.Context, params domain.SearchParams) ([]domain.SearchHit, error) {
+	filter, args := searchFilterSQL(params, "EXISTS (SELECT 1 FROM slides sa WHERE sa.chapter_id = ch.id AND sa.sound_url <> '')")
+
+	var hits []domain.SearchHit
+	err := dbFrom(ctx, r.db).Raw(`WITH q AS (
+			SELECT websearch_to_tsquery(search_config(), @query) AS query
+		)
+		SELECT ch.uuid::text AS id, s.uuid::text AS story_id, ch.uuid::text AS chapter_id,
+			COALESCE(NULLIF(ch.title, ''), s.title) AS title,
+			max(ts_rank(sl.search_vector, q.query)) AS score,
+			ts_headline(search_config(), string_agg(sl.content, ' ' ORDER BY sl.sequence), q.query, @opts) AS snippet
+		FROM slides sl
+		JOIN chapters ch ON ch.id = sl.chapter_id
+		JOIN stories s ON s.id = ch.story_id, q
+		WHERE sl.search_vector @@ q.query AND ch.status = @published`+filter+`
+		GROUP BY ch.id, ch.uuid, ch.title, s.uuid, s.title, q.query
+		ORDER BY score DESC, ch.id DESC
+		LIMIT @limit OFFSET @offset`, args).Scan(&hits).Error
+	for i := range hits {
+		hits[i].Type = domain.SearchTypeChapter
+	}
+	return hits, err
+}
+
 func (r *ChapterRepo) Delete(ctx context.Context, uuid string) error {
 	var chapter domain.Chapter
-	if err := r.db.WithContext(ctx).Select("id").Where("uuid = ?", uuid).First(&chapter).Error; err != nil {
+	if err := dbFrom(ctx, r.db).Select("id").Where("uuid = ?", uuid).First(&chapter).Error; err != nil {
 		return err
 	}
 
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		// Hapus Slide terkait
 		if err := tx.Where("chapter_id = ?", chapter.ID).Delete(&domain.Slide{}).Error; err != nil {
 			return err
@@ -54,20 +167,35 @@ func (r *ChapterRepo) Delete(ctx context.Context, uuid string) error {
 	})
 }
 
-func (r *ChapterRepo) CreateSlide(ctx context.Context, s *domain.Slide) error {
-	// Logic Transaction manual karena stored procedure 'add_slide_safe' mungkin perlu disesuaikan 
-	// atau kita pakai GORM standar saja untuk update slide_count di Chapter
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(s).Error; err != nil {
+// CreateSlide memakai add_slide_safe yang sama dengan slide story, slide_count chapter
+// ikut dinaikkan di dalam function tersebut.
+func (r *ChapterRepo) CreateSlide(ctx context.Context, s *domain.Slide, limit int) error {
+	return createSlide(dbFrom(ctx, r.db), s, limit)
+}
+
+// UpdateSlide hanya menulis konten, lihat StoryRepo.UpdateSlide.
+func (r *ChapterRepo) UpdateSlide(ctx context.Context, s *domain.Slide) error {
+	return dbFrom(ctx, r.db).Model(s).Update("content", s.Content).Error
+}
+
+func (r *ChapterRepo) DeleteSlide(ctx context.Context, s *domain.Slide) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(s).Error; err != nil {
 			return err
 		}
-		// Increment Slide Count di Chapter
-		return tx.Model(&domain.Chapter{}).Where("id = ?", s.ChapterID).Update("slide_count", gorm.Expr("slide_count + 1")).Error
+		// trigger slide_count hanya untuk stories, chapter dikurangi manual
+		return tx.Model(&domain.Chapter{}).Where("id = ?", s.ChapterID).Update("slide_count", gorm.Expr("slide_count - 1")).Error
+	})
+}
+
+func (r *ChapterRepo) ReorderSlides(ctx context.Context, chapterID uint, slideIDs []uint) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return reorderSlides(tx, "chapter_id", chapterID, slideIDs)
 	})
 }
 
 func (r *ChapterRepo) CountSlides(ctx context.Context, chapterID uint) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&domain.Slide{}).Where("chapter_id = ?", chapterID).Count(&count).Error
+	err := dbFrom(ctx, r.db).Model(&domain.Slide{}).Where("chapter_id = ?", chapterID).Count(&count).Error
 	return count, err
 }
\ No newline at end of file
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type CollectionRepo struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) *CollectionRepo {
+	return &CollectionRepo{db: db}
+}
+
+func (r *CollectionRepo) Create(ctx context.Context, c *domain.Collection) error {
+	return dbFrom(ctx, r.db).Create(c).Error
+}
+
+// Update tidak menulis warna dominan, kolom itu diisi job palette lewat UpdateDominantColor.
+func (r *CollectionRepo) Update(ctx context.Context, c *domain.Collection) error {
+	return dbFrom(ctx, r.db).Omit("DominantColor").Save(c).Error
+}
+
+// UpdateDominantColor hanya menulis warna dominan. Mengembalikan ErrConflict jika
+// gambar collection sudah bukan c.ImageURL.
+func (r *CollectionRepo) UpdateDominantColor(ctx context.Context, c *domain.Collection) error {
+	res := dbFrom(ctx, r.db).Model(&domain.Collection{}).
+		Where("id = ? AND image_url = ?", c.ID, c.ImageURL).
+		Update("dominant_color", c.DominantColor)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+// Delete ikut menghapus isi collection_stories lewat ON DELETE CASCADE, story-nya tetap ada.
+func (r *CollectionRepo) Delete(ctx context.Context, uuid string) error {
+	return dbFrom(ctx, r.db).Where("uuid = ?", uuid).Delete(&domain.Collection{}).Error
+}
+
+func (r *CollectionRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Collection, error) {
+	var collection domain.Collection
+	err := dbFrom(ctx, r.db).Where("uuid = ?", uuid).First(&collection).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &collection, nil
+}
+
+var collectionKeyset = keyset[domain.Collection]{
+	columns: map[string]keysetColumn{
+		"created_at": {Column: "created_at", Kind: keyTime},
+		"title":      {Column: "title", Kind: keyString},
+	},
+	value: func(c domain.Collection, field string) interface{} {
+		if field == "title" {
+			return c.Title
+		}
+		return c.CreatedAt
+	},
+	id: func(c domain.Collection) uint { return c.ID },
+}
+
+func (r *CollectionRepo) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Collection, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Collection{})
+
+	for name, value := range q.Filters {
+		switch name {
+		case domain.FilterType:
+			db = db.Where("type = ?", value)
+		default:
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+
+	return paginate(db, q, collectionKeyset)
+}
+
+// ListStories mengembalikan story collection sesuai urutannya. Endpoint publik hanya
+// melihat story Published.
+func (r *CollectionRepo) ListStories(ctx context.Context, collectionID uint, publishedOnly bool) ([]domain.Story, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Story{}).
+		Joins("JOIN collection_stories cs ON cs.story_id = stories.id").
+		Where("cs.collection_id = ?", collectionID)
+	if publishedOnly {
+		db = db.Where("stories.status = ?", domain.StatusPublished)
+	}
+
+	var stories []domain.Story
+	err := db.Preload("Category").Order("cs.position ASC").Find(&stories).Error
+	return stories, err
+}
+
+// SetStories mengganti seluruh isi collection dengan storyUUIDs sesuai urutannya.
+// UUID yang tidak dikenal membatalkan seluruh perubahan.
+func (r *CollectionRepo) SetStories(ctx context.Context, collectionID uint, storyUUIDs []string) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT 1 FROM collections WHERE id = ? FOR UPDATE", collectionID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM collection_stories WHERE collection_id = ?", collectionID).Error; err != nil {
+			return err
+		}
+		if len(storyUUIDs) == 0 {
+			return nil
+		}
+
+		res := tx.Exec(`INSERT INTO collection_stories (collection_id, story_id, position)
+			SELECT ?, s.id, o.pos
+			FROM unnest(string_to_array(?, ',')::uuid[]) WITH ORDINALITY AS o(uuid, pos)
+			JOIN stories s ON s.uuid = o.uuid`, collectionID, strings.Join(storyUUIDs, ","))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(storyUUIDs)) {
+			return domain.ErrBadParamInput
+		}
+		return nil
+	})
+}
+
+type seriesRow struct {
+	CollectionUUID   string
+	Title            string
+	Position         int
+	Total            int
+	PrevUUID         *string
+	PrevTitle        *string
+	PrevThumbnailURL *string
+	NextUUID         *string
+	NextTitle        *string
+	NextThumbnailURL *string
+}
+
+// SeriesForStory menghitung posisi story di setiap series yang memuatnya. Urutan dan
+// tetangga dihitung ulang hanya dari story Published, sehingga story yang belum terbit
+// dilewati.
+func (r *CollectionRepo) SeriesForStory(ctx context.Context, storyID uint) ([]domain.SeriesNavigation, error) {
+	var rows []seriesRow
+	err := dbFrom(ctx, r.db).Raw(`
+		WITH items AS (
+			SELECT cs.collection_id, s.id AS story_id,
+				row_number() OVER w AS position,
+				count(*) OVER (PARTITION BY cs.collection_id) AS total,
+				lag(s.uuid::text) OVER w AS prev_uuid,
+				lag(s.title) OVER w AS prev_title,
+				lag(s.thumbnail_url) OVER w AS prev_thumbnail_url,
+				lead(s.uuid::text) OVER w AS next_uuid,
+				lead(s.title) OVER w AS next_title,
+				lead(s.thumbnail_url) OVER w AS next_thumbnail_url
+			FROM collection_stories cs
+			JOIN stories s ON s.id = cs.story_id AND s.status = @published
+			WHERE cs.collection_id IN (SELECT collection_id FROM collection_stories WHERE story_id = @story)
+			WINDOW w AS (PARTITION BY cs.collection_id ORDER BY cs.position)
+		)
+		SELECT c.uuid AS collection_uuid, c.title, i.position, i.total,
+			i.prev_uuid, i.prev_title, i.prev_thumbnail_url,
+			i.next_uuid, i.next_title, i.next_thumbnail_url
+		FROM items i
+		JOIN collections c ON c.id = i.collection_id AND c.type = @series
+		WHERE i.story_id = @story
+		ORDER BY c.title, c.id`,
+		map[string]interface{}{
+			"published": domain.StatusPublished,
+			"series":    domain.CollectionTypeSeries,
+			"story":     storyID,
+		}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]domain.SeriesNavigation, 0, len(rows))
+	for _, row := range rows {
+		nav := domain.SeriesNavigation{
+			CollectionUUID: row.CollectionUUID,
+			Title:          row.Title,
+			Position:       row.Position,
+			Total:          row.Total,
+		}
+		if row.PrevUUID != nil {
+			nav.Previous = &domain.StoryRef{UUID: *row.PrevUUID, Title: deref(row.PrevTitle), ThumbnailURL: deref(row.PrevThumbnailURL)}
+		}
+		if row.NextUUID != nil {
+			nav.Next = &domain.StoryRef{UUID: *row.NextUUID, Title: deref(row.NextTitle), ThumbnailURL: deref(row.NextThumbnailURL)}
+		}
+		series = append(series, nav)
+	}
+	return series, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
\ No newline at end of file
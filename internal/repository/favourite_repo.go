@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type FavouriteRepo struct {
+	db *gorm.DB
+}
+
+func NewFavouriteRepository(db *gorm.DB) *FavouriteRepo {
+	return &FavouriteRepo{db: db}
+}
+
+// Save menyimpan favorit baru. Jika item yang sama sudah difavoritkan, hanya foldernya
+// yang diperbarui dan f diisi dengan data yang sudah ada.
+func (r *FavouriteRepo) Save(ctx context.Context, f *domain.Favourite) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "favourites:"+f.UserID).Error; err != nil {
+			return err
+		}
+
+		var existing domain.Favourite
+		err := favouriteItem(tx, f.UserID, f.StoryID, f.ChapterID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Omit("Story", "Chapter", "Folder").Create(f).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		f.ID, f.UUID, f.CreatedAt = existing.ID, existing.UUID, existing.CreatedAt
+		return tx.Model(&existing).Update("folder_id", f.FolderID).Error
+	})
+}
+
+func favouriteItem(db *gorm.DB, userID string, storyID uint, chapterID *uint) *gorm.DB {
+	db = db.Model(&domain.Favourite{}).Where("user_id = ?", userID)
+	if chapterID != nil {
+		return db.Where("chapter_id = ?", *chapterID)
+	}
+	return db.Where("story_id = ? AND chapter_id IS NULL", storyID)
+}
+
+func (r *FavouriteRepo) Remove(ctx context.Context, userID string, storyID uint, chapterID *uint) error {
+	return favouriteItem(dbFrom(ctx, r.db), userID, storyID, chapterID).Delete(&domain.Favourite{}).Error
+}
+
+var favouriteKeyset = keyset[domain.Favourite]{
+	columns: map[string]keysetColumn{
+		"created_at": {Column: "created_at", Kind: keyTime},
+	},
+	value: func(f domain.Favourite, field string) interface{} { return f.CreatedAt },
+	id:    func(f domain.Favourite) uint { return f.ID },
+}
+
+// List hanya menampilkan favorit yang masih terbit; story atau chapter yang ditarik
+// tetap tersimpan dan muncul lagi begitu diterbitkan ulang.
+func (r *FavouriteRepo) List(ctx context.Context, userID string, q domain.ListQuery) ([]domain.Favourite, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Favourite{}).
+		Where("user_id = ?", userID).
+		Where("story_id IN (SELECT id FROM stories WHERE status = ?)", domain.StatusPublished).
+		Where("(chapter_id IS NULL OR chapter_id IN (SELECT id FROM chapters WHERE status = ?))", domain.StatusPublished)
+
+	for name, value := range q.Filters {
+		switch name {
+		case domain.FilterType:
+			if value == domain.FavouriteTypeChapter {
+				db = db.Where("chapter_id IS NOT NULL")
+			} else {
+				db = db.Where("chapter_id IS NULL")
+			}
+		case domain.FilterFolder:
+			db = db.Where("folder_id = (SELECT id FROM favourite_folders WHERE uuid = ? AND user_id = ?)", value, userID)
+		default:
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+
+	return paginate(db, q, favouriteKeyset, "Story", "Story.Category", "Chapter", "Folder")
+}
+
+func (r *FavouriteRepo) FavouritedStoryIDs(ctx context.Context, userID string, storyIDs []uint) (map[uint]bool, error) {
+	favourited := make(map[uint]bool, len(storyIDs))
+	if len(storyIDs) == 0 {
+		return favourited, nil
+	}
+
+	var ids []uint
+	err := dbFrom(ctx, r.db).Model(&domain.Favourite{}).
+		Where("user_id = ? AND chapter_id IS NULL AND story_id IN ?", userID, storyIDs).
+		Pluck("story_id", &ids).Error
+	for _, id := range ids {
+		favourited[id] = true
+	}
+	return favourited, err
+}
+
+func (r *FavouriteRepo) CreateFolder(ctx context.Context, f *domain.FavouriteFolder) error {
+	return folderWriteError(dbFrom(ctx, r.db).Create(f).Error)
+}
+
+func (r *FavouriteRepo) UpdateFolder(ctx context.Context, f *domain.FavouriteFolder) error {
+	return folderWriteError(dbFrom(ctx, r.db).Save(f).Error)
+}
+
+// folderWriteError menerjemahkan nama folder ganda milik user yang sama menjadi ErrConflict.
+func folderWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "uq_favourite_folders_user_name" {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+// DeleteFolder tidak menghapus favorit di dalamnya, folder_id menjadi NULL lewat ON DELETE SET NULL.
+func (r *FavouriteRepo) DeleteFolder(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Delete(&domain.FavouriteFolder{}, id).Error
+}
+
+func (r *FavouriteRepo) GetFolder(ctx context.Context, userID, uuid string) (*domain.FavouriteFolder, error) {
+	var folder domain.FavouriteFolder
+	err := dbFrom(ctx, r.db).Where("uuid = ? AND user_id = ?", uuid, userID).First(&folder).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *FavouriteRepo) ListFolders(ctx context.Context, userID string) ([]domain.FavouriteFolder, error) {
+	var folders []domain.FavouriteFolder
+	err := dbFrom(ctx, r.db).Model(&domain.FavouriteFolder{}).
+		Select("favourite_folders.*, (SELECT COUNT(*) FROM favourites f WHERE f.folder_id = favourite_folders.id) AS favourite_count").
+		Where("user_id = ?", userID).
+		Order("lower(name) ASC, id ASC").
+		Find(&folders).Error
+	return folders, err
+}
+
+// MostFavourited mengurutkan story berdasarkan favorit baru sejak since, lalu total
+// favoritnya. Favorit chapter ikut dihitung untuk story-nya, satu user satu kali.
+func (r *FavouriteRepo) MostFavourited(ctx context.Context, since time.Time, limit int) ([]domain.StoryPopularity, error) {
+	var rows []struct {
+		StoryID          uint
+		Favourites       int64
+		RecentFavourites int64
+		Listeners        int64
+	}
+	err := dbFrom(ctx, r.db).Raw(`
+		SELECT f.story_id,
+			COUNT(DISTINCT f.user_id) AS favourites,
+			COUNT(DISTINCT f.user_id) FILTER (WHERE f.created_at >= ?) AS recent_favourites,
+			(SELECT COUNT(DISTINCT h.user_id) FROM listening_histories h
+				WHERE h.story_id = f.story_id AND h.created_at >= ?) AS listeners
+		FROM favourites f
+		GROUP BY f.story_id
+		ORDER BY recent_favourites DESC, favourites DESC, f.story_id DESC
+		LIMIT ?`, since, since, limit).Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.StoryID
+	}
+	var stories []domain.Story
+	if err := dbFrom(ctx, r.db).Preload("Category").Where("id IN ?", ids).Find(&stories).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]domain.Story, len(stories))
+	for _, s := range stories {
+		byID[s.ID] = s
+	}
+
+	popularity := make([]domain.StoryPopularity, 0, len(rows))
+	for _, row := range rows {
+		story, ok := byID[row.StoryID]
+		if !ok {
+			continue
+		}
+		popularity = append(popularity, domain.StoryPopularity{
+			Story:            story,
+			Favourites:       row.Favourites,
+			RecentFavourites: row.RecentFavourites,
+			Listeners:        row.Listeners,
+		})
+	}
+	return popularity, nil
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type HistoryRepo struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepository(db *gorm.DB) *HistoryRepo {
+	return &HistoryRepo{db: db}
+}
+
+func (r *HistoryRepo) Create(ctx context.Context, h *domain.ListeningHistory) error {
+	return dbFrom(ctx, r.db).Create(h).Error
+}
+
+// GetLatestPerStory mengambil satu entri terbaru untuk setiap story yang pernah
+// didengar user, diurutkan dari yang paling baru.
+func (r *HistoryRepo) GetLatestPerStory(ctx context.Context, userID string, limit int) ([]domain.ListeningHistory, error) {
+	var histories []domain.ListeningHistory
+
+	latest := r.db.Model(&domain.ListeningHistory{}).
+		Select("DISTINCT ON (story_id) *").
+		Where("user_id = ?", userID).
+		Order("story_id, created_at DESC, id DESC")
+
+	err := dbFrom(ctx, r.db).
+		Table("(?) AS listening_histories", latest).
+		Preload("Story").
+		Preload("Story.Category").
+		Preload("Chapter").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&histories).Error
+
+	return histories, err
+}
+
+func (r *HistoryRepo) GetLatestForStory(ctx context.Context, userID string, storyID uint) (*domain.ListeningHistory, error) {
+	var history domain.ListeningHistory
+	err := dbFrom(ctx, r.db).
+		Preload("Chapter").
+		Where("user_id = ? AND story_id = ?", userID, storyID).
+		Order("created_at DESC, id DESC").
+		First(&history).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &history, nil
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type ImportJobRepo struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *ImportJobRepo {
+	return &ImportJobRepo{db: db}
+}
+
+func (r *ImportJobRepo) Create(ctx context.Context, j *domain.ImportJob) error {
+	return dbFrom(ctx, r.db).Create(j).Error
+}
+
+// GetByUUID tidak memuat Payload dan Archive.
+func (r *ImportJobRepo) GetByUUID(ctx context.Context, uuid string) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	if err := dbFrom(ctx, r.db).Omit("payload", "archive").Where("uuid = ?", uuid).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ClaimNext mengambil job pending tertua dan menandainya running. SKIP LOCKED membuat
+// beberapa replika bisa menjalankan worker tanpa mengambil job yang sama.
+func (r *ImportJobRepo) ClaimNext(ctx context.Context) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	res := dbFrom(ctx, r.db).Raw(`
+		UPDATE import_jobs SET status = ?, started_at = now(), heartbeat_at = now()
+		WHERE id = (
+			SELECT id FROM import_jobs WHERE status = ?
+			ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, domain.ImportStatusRunning, domain.ImportStatusPending).Scan(&job)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// Heartbeat menandai job running masih diproses agar tidak digagalkan FailStale.
+func (r *ImportJobRepo) Heartbeat(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Model(&domain.ImportJob{}).
+		Where("id = ? AND status = ?", id, domain.ImportStatusRunning).
+		Update("heartbeat_at", time.Now()).Error
+}
+
+// Finish menyimpan hasil job dan membuang file masukannya. Job yang sudah digagalkan
+// FailStale tidak ditimpa.
+func (r *ImportJobRepo) Finish(ctx context.Context, j *domain.ImportJob) error {
+	return dbFrom(ctx, r.db).Model(&domain.ImportJob{}).Where("id = ? AND status = ?", j.ID, domain.ImportStatusRunning).Updates(map[string]interface{}{
+		"status":         j.Status,
+		"total_rows":     j.TotalRows,
+		"succeeded_rows": j.SucceededRows,
+		"failed_rows":    j.FailedRows,
+		"report":         j.Report,
+		"error":          j.Error,
+		"finished_at":    j.FinishedAt,
+		"payload":        nil,
+		"archive":        nil,
+	}).Error
+}
+
+// FailStale menggagalkan job running yang heartbeat terakhirnya sebelum heartbeatBefore,
+// biasanya karena prosesnya mati di tengah jalan. Job tidak diulang karena sebagian
+// baris mungkin sudah tersimpan.
+func (r *ImportJobRepo) FailStale(ctx context.Context, heartbeatBefore time.Time) (int64, error) {
+	res := dbFrom(ctx, r.db).Model(&domain.ImportJob{}).
+		Where("status = ? AND COALESCE(heartbeat_at, started_at) < ?", domain.ImportStatusRunning, heartbeatBefore).
+		Updates(map[string]interface{}{
+			"status":      domain.ImportStatusFailed,
+			"error":       "job interrupted",
+			"finished_at": time.Now(),
+			"payload":     nil,
+			"archive":     nil,
+		})
+	return res.RowsAffected, res.Error
+}
\ No newline at end of file
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type JobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) *JobRepo {
+	return &JobRepo{db: db}
+}
+
+func (r *JobRepo) Enqueue(ctx context.Context, j *domain.Job) error {
+	return dbFrom(ctx, r.db).Create(j).Error
+}
+
+func (r *JobRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Job, error) {
+	var job domain.Job
+	if err := dbFrom(ctx, r.db).Where("uuid = ?", uuid).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Claim mengambil job pending yang sudah jatuh tempo, menandainya running dan menaikkan
+// Attempts. SKIP LOCKED membuat semua worker di semua replika bisa claim bersamaan.
+func (r *JobRepo) Claim(ctx context.Context) (*domain.Job, error) {
+	var job domain.Job
+	res := dbFrom(ctx, r.db).Raw(`
+		UPDATE jobs SET status = ?, attempts = attempts + 1, locked_at = now(), updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs WHERE status = ? AND run_at <= now()
+			ORDER BY run_at, id LIMIT 1 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, domain.JobStatusRunning, domain.JobStatusPending).Scan(&job)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &job, nil
+}
+
+// Heartbeat memperbarui locked_at selama handler berjalan agar RequeueStale tidak
+// mengembalikan job yang masih dikerjakan.
+func (r *JobRepo) Heartbeat(ctx context.Context, j *domain.Job) error {
+	return claimed(dbFrom(ctx, r.db), j).Update("locked_at", time.Now()).Error
+}
+
+// Complete, Retry dan Bury hanya mengubah job yang masih running pada percobaan yang
+// sama. ErrConflict berarti job sudah diambil alih RequeueStale, hasilnya dibuang.
+func (r *JobRepo) Complete(ctx context.Context, j *domain.Job) error {
+	return affectedOne(claimed(dbFrom(ctx, r.db), j).Updates(map[string]interface{}{
+		"status":      domain.JobStatusCompleted,
+		"last_error":  j.LastError,
+		"locked_at":   nil,
+		"finished_at": j.FinishedAt,
+	}))
+}
+
+// Retry mengembalikan job ke pending dengan RunAt baru.
+func (r *JobRepo) Retry(ctx context.Context, j *domain.Job) error {
+	return affectedOne(claimed(dbFrom(ctx, r.db), j).Updates(map[string]interface{}{
+		"status":     domain.JobStatusPending,
+		"run_at":     j.RunAt,
+		"last_error": j.LastError,
+		"locked_at":  nil,
+	}))
+}
+
+// Bury menandai job dead dan menyalinnya ke dead_jobs dalam satu transaksi.
+func (r *JobRepo) Bury(ctx context.Context, j *domain.Job) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := affectedOne(claimed(tx, j).Updates(map[string]interface{}{
+			"status":      domain.JobStatusDead,
+			"last_error":  j.LastError,
+			"locked_at":   nil,
+			"finished_at": j.FinishedAt,
+		}))
+		if err != nil {
+			return err
+		}
+		return tx.Create(&domain.DeadJob{
+			JobID:     j.ID,
+			Type:      j.Type,
+			Payload:   j.Payload,
+			Attempts:  j.Attempts,
+			LastError: j.LastError,
+		}).Error
+	})
+}
+
+// claimed membatasi update ke job j selama masih dipegang worker yang meng-claim-nya.
+func claimed(db *gorm.DB, j *domain.Job) *gorm.DB {
+	return db.Model(&domain.Job{}).Where("id = ? AND status = ? AND attempts = ?", j.ID, domain.JobStatusRunning, j.Attempts)
+}
+
+func affectedOne(res *gorm.DB) error {
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+const staleJobError = "worker lost before the job finished"
+
+// RequeueStale mengembalikan job running yang terkunci sejak sebelum lockedBefore ke
+// pending, biasanya karena worker-nya mati. Job yang percobaannya sudah habis dikubur
+// dan dikembalikan agar pemanggil bisa menjalankan JobHandler.Dead.
+func (r *JobRepo) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, []domain.Job, error) {
+	var requeued int64
+	var buried []domain.Job
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`
+			UPDATE jobs SET status = ?, last_error = ?, locked_at = NULL, finished_at = now(), updated_at = now()
+			WHERE status = ? AND locked_at < ? AND attempts >= max_attempts
+			RETURNING *`,
+			domain.JobStatusDead, staleJobError, domain.JobStatusRunning, lockedBefore).Scan(&buried).Error
+		if err != nil {
+			return err
+		}
+		for _, j := range buried {
+			dead := domain.DeadJob{JobID: j.ID, Type: j.Type, Payload: j.Payload, Attempts: j.Attempts, LastError: j.LastError}
+			if err := tx.Create(&dead).Error; err != nil {
+				return err
+			}
+		}
+
+		res := tx.Model(&domain.Job{}).Where("status = ? AND locked_at < ?", domain.JobStatusRunning, lockedBefore).
+			Updates(map[string]interface{}{
+				"status":     domain.JobStatusPending,
+				"run_at":     time.Now(),
+				"last_error": staleJobError,
+				"locked_at":  nil,
+			})
+		requeued = res.RowsAffected
+		return res.Error
+	})
+	return requeued, buried, err
+}
+
+func (r *JobRepo) ListDead(ctx context.Context, limit int) ([]domain.DeadJob, error) {
+	var dead []domain.DeadJob
+	err := dbFrom(ctx, r.db).Preload("Job").Order("failed_at DESC, id DESC").Limit(limit).Find(&dead).Error
+	return dead, err
+}
+
+// Revive menjalankan ulang job dari dead_jobs dengan jatah percobaan baru.
+func (r *JobRepo) Revive(ctx context.Context, deadID uint) (*domain.Job, error) {
+	var job *domain.Job
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var dead domain.DeadJob
+		if err := tx.First(&dead, deadID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := tx.Delete(&dead).Error; err != nil {
+			return err
+		}
+
+		job = &domain.Job{ID: dead.JobID}
+		err := tx.Model(job).Updates(map[string]interface{}{
+			"status":      domain.JobStatusPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.First(job, dead.JobID).Error
+	})
+	return job, err
+}
\ No newline at end of file
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+)
+
+func TestJobRepoFinishesOnlyItsOwnAttempt(t *testing.T) {
+	rec, db := newRecorder(t)
+	repo := repository.NewJobRepository(db)
+
+	now := time.Now()
+	job := &domain.Job{ID: 4, Attempts: 2, FinishedAt: &now}
+	require.NoError(t, repo.Heartbeat(context.Background(), job))
+	require.NoError(t, repo.Complete(context.Background(), job))
+
+	updates := rec.updates()
+	require.Len(t, updates, 2)
+	for _, s := range updates {
+		assert.Contains(t, s, `WHERE id = $`)
+		assert.Contains(t, s, `AND status = $`)
+		assert.Contains(t, s, `AND attempts = $`)
+	}
+	assert.Contains(t, updates[0], `SET "locked_at"=$1`)
+}
\ No newline at end of file
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+// keysetCursor adalah isi cursor opaque: nilai kolom urutan dan id dari baris
+// terakhir (atau pertama, jika Prev) pada halaman sebelumnya.
+type keysetCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+	ID     uint          `json:"id"`
+	Prev   bool          `json:"p,omitempty"`
+}
+
+type keysetKind int
+
+const (
+	keyString keysetKind = iota
+	keyInt
+	keyFloat
+	keyTime
+)
+
+type keysetColumn struct {
+	Column string
+	Kind   keysetKind
+}
+
+// keyset mendeskripsikan field yang boleh dipakai sebagai urutan paginasi untuk satu entity.
+// Kolom id selalu ditambahkan sebagai penentu urutan terakhir.
+type keyset[T any] struct {
+	columns map[string]keysetColumn
+	value   func(item T, field string) interface{}
+	id      func(item T) uint
+}
+
+func sortSignature(sort []domain.SortField) string {
+	parts := make([]string, len(sort))
+	for i, s := range sort {
+		parts[i] = s.Field
+		if s.Desc {
+			parts[i] = "-" + s.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// decode membaca cursor dari client. Cursor yang rusak atau dibuat dengan urutan
+// berbeda ditolak sebagai ErrBadParamInput.
+func (k keyset[T]) decode(q domain.ListQuery) (*keysetCursor, error) {
+	if q.Cursor == "" {
+		return nil, nil
+	}
+
+	var cur keysetCursor
+	if err := utils.DecodeCursor(q.Cursor, &cur); err != nil {
+		return nil, domain.ErrBadParamInput
+	}
+	if cur.Sort != sortSignature(q.Sort) || len(cur.Values) != len(q.Sort) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	for i, s := range q.Sort {
+		switch k.columns[s.Field].Kind {
+		case keyTime:
+			str, _ := cur.Values[i].(string)
+			t, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return nil, domain.ErrBadParamInput
+			}
+			cur.Values[i] = t
+		case keyInt:
+			f, ok := cur.Values[i].(float64)
+			if !ok {
+				return nil, domain.ErrBadParamInput
+			}
+			cur.Values[i] = int64(f)
+		case keyFloat:
+			if _, ok := cur.Values[i].(float64); !ok {
+				return nil, domain.ErrBadParamInput
+			}
+		default:
+			if _, ok := cur.Values[i].(string); !ok {
+				return nil, domain.ErrBadParamInput
+			}
+		}
+	}
+	return &cur, nil
+}
+
+func (k keyset[T]) encode(item T, sort []domain.SortField, prev bool) string {
+	cur := keysetCursor{Sort: sortSignature(sort), ID: k.id(item), Prev: prev}
+	for _, s := range sort {
+		cur.Values = append(cur.Values, k.value(item, s.Field))
+	}
+	return utils.EncodeCursor(cur)
+}
+
+// keysetCondition membangun (a < ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id < ?)
+// sesuai arah tiap kolom. backward membalik semua arah untuk mengambil halaman sebelumnya.
+func keysetCondition(columns []string, descs []bool, values []interface{}, backward bool) (string, []interface{}) {
+	var ors []string
+	var args []interface{}
+	for i := range columns {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, columns[j]+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if descs[i] != backward {
+			op = "<"
+		}
+		ands = append(ands, columns[i]+" "+op+" ?")
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// paginate menjalankan query listing dengan paginasi keyset. base sudah berisi filter
+// tetapi belum berisi urutan maupun limit.
+func paginate[T any](base *gorm.DB, q domain.ListQuery, k keyset[T], preloads ...string) ([]T, *domain.PageInfo, error) {
+	for _, s := range q.Sort {
+		if _, ok := k.columns[s.Field]; !ok {
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+	cur, err := k.decode(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &domain.PageInfo{}
+	if q.WithTotal {
+		var total int64
+		if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, nil, err
+		}
+		info.Total = &total
+	}
+
+	// id mengikuti arah kolom urutan terakhir agar hasil tetap stabil
+	idDesc := true
+	if len(q.Sort) > 0 {
+		idDesc = q.Sort[len(q.Sort)-1].Desc
+	}
+	columns := make([]string, 0, len(q.Sort)+1)
+	descs := make([]bool, 0, len(q.Sort)+1)
+	for _, s := range q.Sort {
+		columns = append(columns, k.columns[s.Field].Column)
+		descs = append(descs, s.Desc)
+	}
+	columns = append(columns, "id")
+	descs = append(descs, idDesc)
+
+	backward := cur != nil && cur.Prev
+	tx := base.Session(&gorm.Session{})
+	for _, p := range preloads {
+		tx = tx.Preload(p)
+	}
+	if cur != nil {
+		cond, args := keysetCondition(columns, descs, append(cur.Values, cur.ID), backward)
+		tx = tx.Where(cond, args...)
+	}
+	for i, column := range columns {
+		tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: descs[i] != backward})
+	}
+
+	var items []T
+	if err := tx.Limit(q.Limit + 1).Find(&items).Error; err != nil {
+		return nil, nil, err
+	}
+
+	more := len(items) > q.Limit
+	if more {
+		items = items[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+	k.fillPageInfo(info, items, q.Sort, cur, more)
+	return items, info, nil
+}
+
+// fillPageInfo mengisi cursor next/prev dari baris pertama dan terakhir halaman.
+// more berarti masih ada baris lain searah dengan pengambilan.
+func (k keyset[T]) fillPageInfo(info *domain.PageInfo, items []T, sort []domain.SortField, cur *keysetCursor, more bool) {
+	if len(items) == 0 {
+		return
+	}
+
+	first, last := items[0], items[len(items)-1]
+	if cur != nil && cur.Prev {
+		// halaman sebelumnya selalu punya kelanjutan ke arah depan
+		info.HasMore = true
+		info.NextCursor = k.encode(last, sort, false)
+		if more {
+			info.PrevCursor = k.encode(first, sort, true)
+		}
+		return
+	}
+
+	info.HasMore = more
+	if more {
+		info.NextCursor = k.encode(last, sort, false)
+	}
+	if cur != nil {
+		info.PrevCursor = k.encode(first, sort, true)
+	}
+}
\ No newline at end of file
@@ -17,38 +17,97 @@ func NewPreferenceRepository(db *gorm.DB) *PreferenceRepo {
 	return &PreferenceRepo{db: db}
 }
 
-func (r *PreferenceRepo) ClearChoices(ctx context.Context, userID string) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserChoiceStory{}).Error; err != nil {
-			return err
+// choiceTables mengikuti urutan section di PreferenceChoices: story, dakwah, hadist.
+var choiceTables = [3]string{"user_choice_stories", "user_choice_dakwahs", "user_choice_hadists"}
+
+func choiceSections(c domain.PreferenceChoices) [3][]uint {
+	return [3][]uint{c.Story, c.Dakwah, c.Hadist}
+}
+
+// lockChoices menyerialkan perubahan preferensi satu user agar PATCH yang berjalan
+// bersamaan tidak saling menimpa atau melewati batas jumlah pilihan.
+func lockChoices(tx *gorm.DB, userID string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "preferences:"+userID).Error
+}
+
+func insertChoices(tx *gorm.DB, table, userID string, categoryIDs []uint) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	return tx.Exec("INSERT INTO "+table+" (user_id, category_id) SELECT ?, id FROM categories WHERE id IN ? ON CONFLICT DO NOTHING", userID, categoryIDs).Error
+}
+
+func (r *PreferenceRepo) GetChoices(ctx context.Context, userID string) (*domain.UserPreferences, error) {
+	var sections [3][]domain.Category
+	for i, table := range choiceTables {
+		err := dbFrom(ctx, r.db).Model(&domain.Category{}).
+			Joins("JOIN "+table+" uc ON uc.category_id = categories.id").
+			Where("uc.user_id = ?", userID).
+			Preload("Parent").
+			Order("categories.name ASC, categories.id ASC").
+			Find(&sections[i]).Error
+		if err != nil {
+			return nil, err
 		}
-		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserChoiceDakwah{}).Error; err != nil {
+	}
+
+	return &domain.UserPreferences{
+		StoryCategories:  sections[0],
+		DakwahCategories: sections[1],
+		HadistCategories: sections[2],
+	}, nil
+}
+
+// ReplaceChoices mengganti seluruh pilihan user dalam satu transaksi, sehingga tidak
+// ada keadaan di mana pilihan lama sudah terhapus tetapi pilihan baru belum tersimpan.
+func (r *PreferenceRepo) ReplaceChoices(ctx context.Context, userID string, choices domain.PreferenceChoices) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockChoices(tx, userID); err != nil {
 			return err
 		}
-		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserChoiceHadist{}).Error; err != nil {
-			return err
+		for i, ids := range choiceSections(choices) {
+			if err := tx.Exec("DELETE FROM "+choiceTables[i]+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+			if err := insertChoices(tx, choiceTables[i], userID, ids); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
 }
 
-func (r *PreferenceRepo) SaveStoryChoices(ctx context.Context, choices []domain.UserChoiceStory) error {
-	if len(choices) == 0 {
-		return nil
-	}
-	return r.db.WithContext(ctx).Create(&choices).Error
-}
+// UpdateChoices menambah dan menghapus pilihan per section. Kategori yang sudah dipilih
+// tidak digandakan. Jika hasil akhirnya melebihi limit di salah satu section, semua
+// perubahan dibatalkan dengan ErrTooManyChoices.
+func (r *PreferenceRepo) UpdateChoices(ctx context.Context, userID string, add, remove domain.PreferenceChoices, limit int) error {
+	adds, removes := choiceSections(add), choiceSections(remove)
 
-func (r *PreferenceRepo) SaveDakwahChoices(ctx context.Context, choices []domain.UserChoiceDakwah) error {
-	if len(choices) == 0 {
-		return nil
-	}
-	return r.db.WithContext(ctx).Create(&choices).Error
-}
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockChoices(tx, userID); err != nil {
+			return err
+		}
+		for i, table := range choiceTables {
+			if len(removes[i]) > 0 {
+				if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ? AND category_id IN ?", userID, removes[i]).Error; err != nil {
+					return err
+				}
+			}
+			if len(adds[i]) == 0 {
+				continue
+			}
+			if err := insertChoices(tx, table, userID, adds[i]); err != nil {
+				return err
+			}
 
-func (r *PreferenceRepo) SaveHadistChoices(ctx context.Context, choices []domain.UserChoiceHadist) error {
-	if len(choices) == 0 {
+			var count int64
+			if err := tx.Table(table).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > int64(limit) {
+				return domain.ErrTooManyChoices
+			}
+		}
 		return nil
-	}
-	return r.db.WithContext(ctx).Create(&choices).Error
+	})
 }
\ No newline at end of file
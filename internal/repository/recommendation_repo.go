@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type RecommendationRepo struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) *RecommendationRepo {
+	return &RecommendationRepo{db: db}
+}
+
+func (r *RecommendationRepo) GetForUser(ctx context.Context, userID string, limit int) ([]domain.Recommendation, error) {
+	var recs []domain.Recommendation
+
+	// Menggunakan Preload untuk memuat data Story dan Category terkait
+	err := dbFrom(ctx, r.db).
+		Preload("Story").
+		Preload("Story.Category").
+		Joins("JOIN stories ON stories.id = recommendations.story_id AND stories.status = ?", domain.StatusPublished).
+		Where("recommendations.user_id = ?", userID).
+		Order("recommendations.score DESC").
+		Limit(limit).
+		Find(&recs).Error
+
+	return recs, err
+}
+
+// recommendationLockKey dipakai pg_try_advisory_lock agar hanya satu replika yang
+// menghitung ulang rekomendasi semua user pada satu waktu.
+const recommendationLockKey int64 = 7_241_003
+
+func (r *RecommendationRepo) ReplaceForUser(ctx context.Context, userID string, recs []domain.Recommendation) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// job berkala dan perubahan preferensi bisa menulis user yang sama bersamaan
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "recommendations:"+userID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.Recommendation{}).Error; err != nil {
+			return err
+		}
+		if len(recs) == 0 {
+			return nil
+		}
+		return tx.Omit("Story").Create(&recs).Error
+	})
+}
+
+// MarkRequested mencatat bahwa rekomendasi user sudah diminta. false berarti user sudah
+// pernah ditandai sebelumnya.
+func (r *RecommendationRepo) MarkRequested(ctx context.Context, userID string) (bool, error) {
+	res := dbFrom(ctx, r.db).Exec("INSERT INTO recommendation_requests (user_id) VALUES (?) ON CONFLICT DO NOTHING", userID)
+	return res.RowsAffected > 0, res.Error
+}
+
+// WithLeaderLock menjalankan fn sambil memegang session advisory lock di satu koneksi.
+// Jika replika lain sedang memegang lock, fn tidak dijalankan.
+func (r *RecommendationRepo) WithLeaderLock(ctx context.Context, fn func() error) error {
+	return dbFrom(ctx, r.db).Connection(func(conn *gorm.DB) error {
+		var leader bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", recommendationLockKey).Scan(&leader).Error; err != nil {
+			return err
+		}
+		if !leader {
+			return nil
+		}
+		// unlock tetap dijalankan meski ctx sudah batal, lock ikut koneksi yang kembali ke pool
+		defer conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", recommendationLockKey)
+
+		return fn()
+	})
+}
+
+// GetActiveUserIDs mengembalikan user yang punya preferensi atau riwayat mendengarkan.
+func (r *RecommendationRepo) GetActiveUserIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := dbFrom(ctx, r.db).Raw(`
+		SELECT user_id FROM user_choice_stories
+		UNION SELECT user_id FROM user_choice_dakwahs
+		UNION SELECT user_id FROM user_choice_hadists
+		UNION SELECT user_id FROM listening_histories
+		UNION SELECT user_id FROM favourites
+		UNION SELECT user_id FROM recommendations
+	`).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *RecommendationRepo) GetUserSignals(ctx context.Context, userID string) (*domain.UserSignals, error) {
+	db := dbFrom(ctx, r.db)
+	signals := &domain.UserSignals{
+		PreferredCategories: map[uint]bool{},
+		CategoryListening:   map[uint]int{},
+		ListenedStories:     map[uint]bool{},
+	}
+
+	var preferred []uint
+	if err := db.Raw(`
+		SELECT category_id FROM user_choice_stories WHERE user_id = ?
+		UNION SELECT category_id FROM user_choice_dakwahs WHERE user_id = ?
+		UNION SELECT category_id FROM user_choice_hadists WHERE user_id = ?
+	`, userID, userID, userID).Scan(&preferred).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range preferred {
+		signals.PreferredCategories[id] = true
+	}
+
+	var listening []struct {
+		CategoryID uint
+		Seconds    int
+	}
+	if err := db.Raw(`
+		SELECT s.category_id, COALESCE(SUM(h.duration), 0) AS seconds
+		FROM listening_histories h
+		JOIN stories s ON s.id = h.story_id
+		WHERE h.user_id = ?
+		GROUP BY s.category_id
+	`, userID).Scan(&listening).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range listening {
+		signals.CategoryListening[row.CategoryID] = row.Seconds
+	}
+
+	var listened []uint
+	if err := db.Model(&domain.ListeningHistory{}).
+		Distinct("story_id").
+		Where("user_id = ?", userID).
+		Pluck("story_id", &listened).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range listened {
+		signals.ListenedStories[id] = true
+	}
+
+	return signals, nil
+}
+
+func (r *RecommendationRepo) GetCandidateStories(ctx context.Context) ([]domain.Story, error) {
+	var stories []domain.Story
+	err := dbFrom(ctx, r.db).
+		Select("id", "category_id", "created_at").
+		Where("status = ?", domain.StatusPublished).
+		Find(&stories).Error
+	return stories, err
+}
+
+// GetCategoryPopularity menghitung jumlah pendengar unik per kategori sejak waktu tertentu.
+func (r *RecommendationRepo) GetCategoryPopularity(ctx context.Context, since time.Time) (map[uint]int, error) {
+	var rows []struct {
+		CategoryID uint
+		Listeners  int
+	}
+	err := dbFrom(ctx, r.db).Raw(`
+		SELECT s.category_id, COUNT(DISTINCT h.user_id) AS listeners
+		FROM listening_histories h
+		JOIN stories s ON s.id = h.story_id
+		WHERE h.created_at >= ?
+		GROUP BY s.category_id
+	`, since).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	popularity := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		popularity[row.CategoryID] = row.Listeners
+	}
+	return popularity, nil
+}
+
+// GetStoryFavourites menghitung jumlah user yang memfavoritkan setiap story, termasuk
+// lewat salah satu chapter-nya.
+func (r *RecommendationRepo) GetStoryFavourites(ctx context.Context) (map[uint]int, error) {
+	var rows []struct {
+		StoryID    uint
+		Favourites int
+	}
+	err := dbFrom(ctx, r.db).Raw(`
+		SELECT story_id, COUNT(DISTINCT user_id) AS favourites
+		FROM favourites
+		GROUP BY story_id
+	`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	favourites := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		favourites[row.StoryID] = row.Favourites
+	}
+	return favourites, nil
+}
\ No newline at end of file
@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+)
+
+// recorder adalah driver database/sql palsu yang mencatat setiap statement. Query yang
+// cocok dengan salah satu prefix di rows mengembalikan baris tersebut, sisanya kosong.
+type recorder struct {
+	mu         sync.Mutex
+	statements []string
+	rows       map[string]fakeRows
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func newRecorder(t *testing.T) (*recorder, *gorm.DB) {
+	t.Helper()
+	rec := &recorder{rows: map[string]fakeRows{}}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(rec)}), &gorm.Config{
+		Logger: gormlogger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec, db
+}
+
+func (r *recorder) returns(prefix string, columns []string, values ...[]driver.Value) {
+	r.rows[prefix] = fakeRows{columns: columns, values: values}
+}
+
+func (r *recorder) record(query string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+}
+
+// deletes mengembalikan statement DELETE sesuai urutan eksekusi.
+func (r *recorder) deletes() []string { return r.matching("DELETE") }
+
+// updates mengembalikan statement UPDATE sesuai urutan eksekusi.
+func (r *recorder) updates() []string { return r.matching("UPDATE") }
+
+func (r *recorder) matching(prefix string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	for _, s := range r.statements {
+		if strings.HasPrefix(s, prefix) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) { return &fakeConn{r}, nil }
+func (r *recorder) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ rec *recorder }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.rec.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.rec.record(query)
+	for prefix, rows := range c.rec.rows {
+		if strings.HasPrefix(query, prefix) {
+			return &fakeResult{rows: rows}, nil
+		}
+	}
+	return &fakeResult{}, nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, nil)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, nil)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeResult struct {
+	rows fakeRows
+	next int
+}
+
+func (r *fakeResult) Columns() []string { return r.rows.columns }
+func (r *fakeResult) Close() error      { return nil }
+
+func (r *fakeResult) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows.values) {
+		return io.EOF
+	}
+	copy(dest, r.rows.values[r.next])
+	r.next++
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type ReviewRepo struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) *ReviewRepo {
+	return &ReviewRepo{db: db}
+}
+
+// Upsert menyimpan ulasan user untuk sebuah story, menggantikan ulasan sebelumnya jika
+// ada. UUID dan waktu dibuat ulasan lama dipertahankan; r diisi ulang dari database.
+func (r *ReviewRepo) Upsert(ctx context.Context, review *domain.Review) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Story").Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "story_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"rating", "body", "status", "flagged_terms", "moderation_reason", "moderated_by", "moderated_at", "updated_at",
+			}),
+		}).Create(review).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("story_id = ? AND user_id = ?", review.StoryID, review.UserID).First(review).Error
+	})
+}
+
+func (r *ReviewRepo) Update(ctx context.Context, review *domain.Review) error {
+	return dbFrom(ctx, r.db).Omit("Story").Save(review).Error
+}
+
+func (r *ReviewRepo) Delete(ctx context.Context, id uint) error {
+	return dbFrom(ctx, r.db).Delete(&domain.Review{}, id).Error
+}
+
+func (r *ReviewRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Review, error) {
+	return r.first(dbFrom(ctx, r.db).Preload("Story").Where("uuid = ?", uuid))
+}
+
+func (r *ReviewRepo) GetByUser(ctx context.Context, storyID uint, userID string) (*domain.Review, error) {
+	return r.first(dbFrom(ctx, r.db).Where("story_id = ? AND user_id = ?", storyID, userID))
+}
+
+func (r *ReviewRepo) first(db *gorm.DB) (*domain.Review, error) {
+	var review domain.Review
+	if err := db.First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+var reviewKeyset = keyset[domain.Review]{
+	columns: map[string]keysetColumn{
+		"created_at": {Column: "created_at", Kind: keyTime},
+		"rating":     {Column: "rating", Kind: keyInt},
+	},
+	value: func(r domain.Review, field string) interface{} {
+		if field == "rating" {
+			return r.Rating
+		}
+		return r.CreatedAt
+	},
+	id: func(r domain.Review) uint { return r.ID },
+}
+
+// ListByStory hanya memuat ulasan approved yang berisi teks; rating tanpa teks cukup
+// terlihat di rating_average story.
+func (r *ReviewRepo) ListByStory(ctx context.Context, storyID uint, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Review{}).
+		Where("story_id = ? AND status = ? AND body <> ''", storyID, domain.ReviewStatusApproved)
+	return paginate(db, q, reviewKeyset)
+}
+
+// List adalah antrean moderasi admin.
+func (r *ReviewRepo) List(ctx context.Context, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Review{})
+
+	for name, value := range q.Filters {
+		switch name {
+		case domain.FilterStatus:
+			db = db.Where("status = ?", value)
+		case domain.FilterStory:
+			db = db.Where("story_id = (SELECT id FROM stories WHERE uuid = ?)", value)
+		case domain.FilterFlagged:
+			if value == "true" {
+				db = db.Where("flagged_terms <> ''")
+			} else {
+				db = db.Where("flagged_terms = ''")
+			}
+		default:
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+
+	return paginate(db, q, reviewKeyset, "Story")
+}
\ No newline at end of file
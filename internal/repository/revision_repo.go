@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type RevisionRepo struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) *RevisionRepo {
+	return &RevisionRepo{db: db}
+}
+
+// Record menyimpan revisi baru dengan nomor versi berikutnya. Jika entity belum punya
+// revisi sama sekali, baseline (keadaan sebelum perubahan) disimpan dulu sebagai versi 1
+// agar perubahan pertama tetap bisa di-rollback.
+func (r *RevisionRepo) Record(ctx context.Context, rev *domain.Revision, baseline domain.RevisionFields) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// lock per entity agar dua update bersamaan tidak mendapat versi yang sama
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", rev.EntityType+":"+rev.EntityKey).Error; err != nil {
+			return err
+		}
+
+		var version int
+		if err := tx.Model(&domain.Revision{}).
+			Select("COALESCE(MAX(version), 0)").
+			Where("entity_type = ? AND entity_key = ?", rev.EntityType, rev.EntityKey).
+			Scan(&version).Error; err != nil {
+			return err
+		}
+
+		if version == 0 && baseline != nil {
+			version++
+			base := &domain.Revision{
+				StoryID:    rev.StoryID,
+				EntityType: rev.EntityType,
+				EntityKey:  rev.EntityKey,
+				Version:    version,
+				Action:     domain.RevisionActionBaseline,
+				Snapshot:   baseline,
+				Changes:    domain.RevisionDiff{},
+			}
+			if err := tx.Create(base).Error; err != nil {
+				return err
+			}
+		}
+
+		rev.Version = version + 1
+		return tx.Create(rev).Error
+	})
+}
+
+var revisionKeyset = keyset[domain.Revision]{
+	columns: map[string]keysetColumn{
+		"created_at": {Column: "created_at", Kind: keyTime},
+	},
+	value: func(r domain.Revision, _ string) interface{} { return r.CreatedAt },
+	id:    func(r domain.Revision) uint { return r.ID },
+}
+
+func (r *RevisionRepo) ListByStory(ctx context.Context, storyID uint, entityType string, q domain.ListQuery) ([]domain.Revision, *domain.PageInfo, error) {
+	base := dbFrom(ctx, r.db).Model(&domain.Revision{}).Where("story_id = ?", storyID)
+	if entityType != "" {
+		base = base.Where("entity_type = ?", entityType)
+	}
+	return paginate(base, q, revisionKeyset)
+}
+
+func (r *RevisionRepo) GetByID(ctx context.Context, id uint) (*domain.Revision, error) {
+	var rev domain.Revision
+	if err := dbFrom(ctx, r.db).First(&rev, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rev, nil
+}
\ No newline at end of file
@@ -2,8 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"khalif-stories/internal/domain"
 
@@ -18,12 +26,12 @@ func NewStoryRepository(db *gorm.DB) *StoryRepo {
 }
 
 func (r *StoryRepo) Create(ctx context.Context, s *domain.Story) error {
-	return r.db.WithContext(ctx).Create(s).Error
+	return dbFrom(ctx, r.db).Create(s).Error
 }
 
 func (r *StoryRepo) CheckDuplicate(ctx context.Context, title, description string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&domain.Story{}).
+	err := dbFrom(ctx, r.db).Model(&domain.Story{}).
 		Where("title = ?", title).
 		Count(&count).Error
 
@@ -33,56 +41,347 @@ func (r *StoryRepo) CheckDuplicate(ctx context.Context, title, description strin
 	return count > 0, nil
 }
 
-func (r *StoryRepo) GetAll(ctx context.Context, page, limit int, sort string) ([]domain.Story, error) {
-	var stories []domain.Story
-	offset := (page - 1) * limit
-	err := r.db.WithContext(ctx).Preload("Category").
-		Order(sort).Limit(limit).Offset(offset).Find(&stories).Error
-	return stories, err
+// storyKeyset adalah whitelist field sort untuk listing story.
+var storyKeyset = keyset[domain.Story]{
+	columns: map[string]keysetColumn{
+		"created_at":  {Column: "created_at", Kind: keyTime},
+		"updated_at":  {Column: "updated_at", Kind: keyTime},
+		"title":       {Column: "title", Kind: keyString},
+		"slide_count": {Column: "slide_count", Kind: keyInt},
+		"rating":      {Column: "rating_average", Kind: keyFloat},
+	},
+	value: func(s domain.Story, field string) interface{} {
+		switch field {
+		case "created_at":
+			return s.CreatedAt
+		case "updated_at":
+			return s.UpdatedAt
+		case "title":
+			return s.Title
+		case "rating":
+			return s.RatingAverage
+		default:
+			return s.SlideCount
+		}
+	},
+	id: func(s domain.Story) uint { return s.ID },
+}
+
+func (r *StoryRepo) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Story, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db).Model(&domain.Story{})
+
+	for name, value := range q.Filters {
+		switch name {
+		case domain.FilterCategory:
+			db = db.Where("category_id = (SELECT id FROM categories WHERE uuid = ?)", value)
+		case domain.FilterStatus:
+			db = db.Where("status = ?", value)
+		case domain.FilterHasAudio:
+			audio := storyHasAudioSQL("stories.id")
+			if value == "true" {
+				db = db.Where(audio)
+			} else {
+				db = db.Where("NOT " + audio)
+			}
+		default:
+			return nil, nil, domain.ErrBadParamInput
+		}
+	}
+
+	return paginate(db, q, storyKeyset, "Category")
+}
+
+// searchHeadlineOptions mengatur format cuplikan ts_headline yang dikirim ke client.
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" ... \""
+
+// storyMatchSQL mencocokkan story lewat full-text atau kemiripan trigram judul.
+const storyMatchSQL = "(s.search_vector @@ websearch_to_tsquery(search_config(), @query) OR normalize_translit(@query) <% normalize_translit(s.title))"
+
+// searchFilterSQL menerjemahkan SearchFilter menjadi kondisi SQL atas alias story "s".
+// audioSQL adalah ekspresi "punya audio" untuk jenis hasil yang sedang dicari.
+// Pencarian bersifat publik, jadi hanya story Published yang ikut.
+func searchFilterSQL(params domain.SearchParams, audioSQL string) (string, map[string]interface{}) {
+	args := map[string]interface{}{
+		"query":     params.Query,
+		"limit":     params.Limit,
+		"offset":    params.Offset,
+		"opts":      searchHeadlineOptions,
+		"published": domain.StatusPublished,
+	}
+
+	conds := []string{"s.status = @published"}
+	f := params.Filter
+	if f.CategoryUUID != "" {
+		conds = append(conds, "s.category_id = (SELECT id FROM categories WHERE uuid = @category)")
+		args["category"] = f.CategoryUUID
+	}
+	if f.CreatedFrom != nil {
+		conds = append(conds, "s.created_at >= @created_from")
+		args["created_from"] = *f.CreatedFrom
+	}
+	if f.CreatedTo != nil {
+		conds = append(conds, "s.created_at <= @created_to")
+		args["created_to"] = *f.CreatedTo
+	}
+	if f.HasAudio != nil {
+		if *f.HasAudio {
+			conds = append(conds, audioSQL)
+		} else {
+			conds = append(conds, "NOT "+audioSQL)
+		}
+	}
+
+	return " AND " + strings.Join(conds, " AND "), args
+}
+
+// storyHasAudioSQL bernilai true jika story (atau salah satu chapter-nya) punya slide dengan audio.
+func storyHasAudioSQL(storyID string) string {
+	return fmt.Sprintf(`EXISTS (
+	SELECT 1 FROM slides sa LEFT JOIN chapters ca ON ca.id = sa.chapter_id
+	WHERE (sa.story_id = %[1]s OR ca.story_id = %[1]s) AND sa.sound_url <> ''
+)`, storyID)
 }
 
-func (r *StoryRepo) Search(ctx context.Context, query string) ([]domain.Story, error) {
+// storySearchKeyset mengurutkan hasil pencarian berdasarkan skor relevansi lalu id.
+var (
+	storySearchSort   = []domain.SortField{{Field: "rank", Desc: true}}
+	storySearchKeyset = keyset[domain.StorySearchHit]{
+		columns: map[string]keysetColumn{"rank": {Column: "rank", Kind: keyFloat}},
+		value:   func(h domain.StorySearchHit, _ string) interface{} { return h.Rank },
+		id:      func(h domain.StorySearchHit) uint { return h.Story.ID },
+	}
+)
+
+func (r *StoryRepo) Search(ctx context.Context, params domain.SearchParams) ([]domain.StorySearchHit, *domain.PageInfo, error) {
+	db := dbFrom(ctx, r.db)
+	filter, args := searchFilterSQL(params, storyHasAudioSQL("s.id"))
+
+	cur, err := storySearchKeyset.decode(domain.ListQuery{Cursor: params.Cursor, Sort: storySearchSort})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var total int64
+	if err := db.Raw("SELECT count(*) FROM stories s WHERE "+storyMatchSQL+filter, args).Scan(&total).Error; err != nil {
+		return nil, nil, err
+	}
+	info := &domain.PageInfo{Total: &total}
+	if total == 0 {
+		return []domain.StorySearchHit{}, info, nil
+	}
+
+	backward := cur != nil && cur.Prev
+	op, order := "<", "DESC"
+	if backward {
+		op, order = ">", "ASC"
+	}
+	keysetSQL := ""
+	if cur != nil {
+		keysetSQL = fmt.Sprintf("WHERE rank %[1]s @cursor_rank OR (rank = @cursor_rank AND id %[1]s @cursor_id)", op)
+		args["cursor_rank"] = cur.Values[0]
+		args["cursor_id"] = cur.ID
+		args["offset"] = 0
+	}
+	args["limit"] = params.Limit + 1
+
+	var rows []struct {
+		ID       uint
+		Rank     float64
+		Headline string
+		Snippet  string
+	}
+	err = db.Raw(`WITH q AS (
+			SELECT websearch_to_tsquery(search_config(), @query) AS query, normalize_translit(@query) AS norm
+		), scored AS (
+			SELECT s.id, s.title, s.description,
+				ts_rank(s.search_vector, q.query) + word_similarity(q.norm, normalize_translit(s.title)) AS rank
+			FROM stories s, q
+			WHERE `+storyMatchSQL+filter+`
+		), ranked AS (
+			SELECT * FROM scored `+keysetSQL+`
+			ORDER BY rank `+order+`, id `+order+`
+			LIMIT @limit OFFSET @offset
+		)
+		SELECT ranked.id, ranked.rank,
+			ts_headline(search_config(), COALESCE(ranked.title, ''), q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS headline,
+			ts_headline(search_config(), COALESCE(ranked.description, '') || ' ' || story_search_body(ranked.id), q.query, @opts) AS snippet
+		FROM ranked, q
+		ORDER BY ranked.rank `+order+`, ranked.id `+order, args).Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	more := len(rows) > params.Limit
+	if more {
+		rows = rows[:params.Limit]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return []domain.StorySearchHit{}, info, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
 	var stories []domain.Story
-	pattern := "%" + query + "%"
-	err := r.db.WithContext(ctx).Preload("Category").
-		Where("title ILIKE ? OR description ILIKE ?", pattern, pattern).
-		Limit(20).Find(&stories).Error
-	return stories, err
+	if err := db.Preload("Category").Where("id IN ?", ids).Find(&stories).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[uint]domain.Story, len(stories))
+	for _, s := range stories {
+		byID[s.ID] = s
+	}
+
+	hits := make([]domain.StorySearchHit, 0, len(rows))
+	for _, row := range rows {
+		story, ok := byID[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, domain.StorySearchHit{
+			Story:    story,
+			Rank:     row.Rank,
+			Headline: row.Headline,
+			Snippet:  row.Snippet,
+		})
+	}
+	storySearchKeyset.fillPageInfo(info, hits, storySearchSort, cur, more)
+	return hits, info, nil
+}
+
+func (r *StoryRepo) SearchSlides(ctx context.Context, params domain.SearchParams) ([]domain.SearchHit, error) {
+	filter, args := searchFilterSQL(params, "sl.sound_url <> ''")
+
+	var hits []domain.SearchHit
+	err := dbFrom(ctx, r.db).Raw(`WITH q AS (
+			SELECT websearch_to_tsquery(search_config(), @query) AS query
+		)
+		SELECT sl.id::text AS id, s.uuid::text AS story_id, COALESCE(ch.uuid::text, '') AS chapter_id,
+			s.title AS title, sl.sequence,
+			ts_rank(sl.search_vector, q.query) AS score,
+			ts_headline(search_config(), COALESCE(sl.content, ''), q.query, @opts) AS snippet
+		FROM slides sl
+		LEFT JOIN chapters ch ON ch.id = sl.chapter_id
+		JOIN stories s ON s.id = COALESCE(sl.story_id, ch.story_id), q
+		WHERE sl.search_vector @@ q.query AND (ch.id IS NULL OR ch.status = @published)`+filter+`
+		ORDER BY score DESC, sl.id DESC
+		LIMIT @limit OFFSET @offset`, args).Scan(&hits).Error
+	for i := range hits {
+		hits[i].Type = domain.SearchTypeSlide
+	}
+	return hits, err
+}
+
+func (r *StoryRepo) SearchFacets(ctx context.Context, params domain.SearchParams) (*domain.SearchFacets, error) {
+	db := dbFrom(ctx, r.db)
+	filter, args := searchFilterSQL(params, storyHasAudioSQL("s.id"))
+
+	facets := &domain.SearchFacets{Categories: []domain.FacetCount{}}
+	err := db.Raw(`SELECT c.uuid::text AS value, c.name AS label, count(*) AS count
+		FROM stories s JOIN categories c ON c.id = s.category_id
+		WHERE `+storyMatchSQL+filter+`
+		GROUP BY c.uuid, c.name
+		ORDER BY count DESC, c.name`, args).Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+func (r *StoryRepo) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
+	var suggestions []domain.SearchSuggestion
+	err := dbFrom(ctx, r.db).Model(&domain.Story{}).
+		Select("uuid AS id, title AS text, word_similarity(normalize_translit(?), normalize_translit(title)) AS score", query).
+		Where("normalize_translit(?) <% normalize_translit(title) OR normalize_translit(title) LIKE '%' || normalize_translit(?) || '%'", query, query).
+		Where("status = ?", domain.StatusPublished).
+		Order("score DESC").Limit(limit).Scan(&suggestions).Error
+	for i := range suggestions {
+		suggestions[i].Type = domain.SearchTypeStory
+	}
+	return suggestions, err
 }
 
 func (r *StoryRepo) GetByID(ctx context.Context, id uint) (*domain.Story, error) {
 	var story domain.Story
-	err := r.db.WithContext(ctx).First(&story, id).Error
+	err := dbFrom(ctx, r.db).First(&story, id).Error
 	return &story, err
 }
 
 func (r *StoryRepo) GetByUUID(ctx context.Context, uuid string) (*domain.Story, error) {
 	var story domain.Story
-	err := r.db.WithContext(ctx).
+	err := dbFrom(ctx, r.db).
 		Preload("Category").
 		Preload("Slides", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sequence ASC")
 		}).
 		Where("uuid = ?", uuid).
 		First(&story).Error
-	
-	return &story, err
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &story, nil
 }
 
+// Update hanya menulis metadata yang bisa diubah admin. Status, jadwal, warna dominan
+// dan slide_count ditulis lewat method masing-masing agar perubahan scheduler, worker
+// atau slide yang terjadi selama request berjalan tidak tertimpa nilai lama.
 func (r *StoryRepo) Update(ctx context.Context, s *domain.Story) error {
-	return r.db.WithContext(ctx).Save(s).Error
+	return dbFrom(ctx, r.db).Model(&domain.Story{}).Where("id = ?", s.ID).Updates(map[string]interface{}{
+		"title":         s.Title,
+		"description":   s.Description,
+		"category_id":   s.CategoryID,
+		"thumbnail_url": s.ThumbnailURL,
+		"updated_at":    s.UpdatedAt,
+	}).Error
+}
+
+// UpdateLifecycle menyimpan status, waktu terbit/arsip dan jadwal story hanya jika
+// statusnya masih from, sehingga tidak menimpa perubahan scheduler atau admin lain
+// yang terjadi sejak story dibaca.
+func (r *StoryRepo) UpdateLifecycle(ctx context.Context, s *domain.Story, from string) error {
+	res := dbFrom(ctx, r.db).Model(&domain.Story{}).
+		Where("id = ? AND status = ?", s.ID, from).
+		Updates(map[string]interface{}{
+			"status":       s.Status,
+			"published_at": s.PublishedAt,
+			"archived_at":  s.ArchivedAt,
+			"publish_at":   s.PublishAt,
+			"unpublish_at": s.UnpublishAt,
+			"updated_at":   s.UpdatedAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrInvalidTransition
+	}
+	return nil
 }
 
 func (r *StoryRepo) UpdateColor(ctx context.Context, id uint, color string) error {
-	return r.db.WithContext(ctx).Model(&domain.Story{}).Where("id = ?", id).Update("dominant_color", color).Error
+	return dbFrom(ctx, r.db).Model(&domain.Story{}).Where("id = ?", id).Update("dominant_color", color).Error
 }
 
 func (r *StoryRepo) Delete(ctx context.Context, uuid string) error {
 	var story domain.Story
-	if err := r.db.WithContext(ctx).Select("id").Where("uuid = ?", uuid).First(&story).Error; err != nil {
+	if err := dbFrom(ctx, r.db).Select("id").Where("uuid = ?", uuid).First(&story).Error; err != nil {
 		return err
 	}
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// FK chapter dan slide tidak cascade, jadi slide chapter dan chapter dihapus lebih dulu
+		chapters := tx.Model(&domain.Chapter{}).Select("id").Where("story_id = ?", story.ID)
+		if err := tx.Where("chapter_id IN (?)", chapters).Delete(&domain.Slide{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("story_id = ?", story.ID).Delete(&domain.Chapter{}).Error; err != nil {
+			return err
+		}
 		if err := tx.Where("story_id = ?", story.ID).Delete(&domain.Slide{}).Error; err != nil {
 			return err
 		}
@@ -90,27 +389,367 @@ func (r *StoryRepo) Delete(ctx context.Context, uuid string) error {
 	})
 }
 
-func (r *StoryRepo) CreateSlide(ctx context.Context, s *domain.Slide) error {
-	return r.db.WithContext(ctx).Exec("CALL add_slide_safe(?, ?, ?, ?)", s.StoryID, s.ImageURL, s.Content, s.Sequence).Error
+func (r *StoryRepo) CreateSlide(ctx context.Context, s *domain.Slide, limit int) error {
+	return createSlide(dbFrom(ctx, r.db), s, limit)
+}
+
+// createSlide menyimpan slide story atau chapter lewat add_slide_safe, yang menegakkan
+// batas jumlah slide di database. s diisi ulang dengan baris yang tersimpan.
+func createSlide(db *gorm.DB, s *domain.Slide, limit int) error {
+	err := db.Raw("SELECT * FROM add_slide_safe(?, ?, ?, ?, ?, ?, ?)",
+		s.StoryID, s.ChapterID, s.ImageURL, s.SoundURL, s.Content, s.Sequence, limit).Scan(s).Error
+	return slideWriteError(err)
+}
+
+// slideWriteError menerjemahkan pelanggaran batas slide dan sequence ganda menjadi error domain.
+func slideWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch {
+	case pgErr.Code == "23514" && pgErr.ConstraintName == "chk_slide_limit":
+		return domain.ErrSlideLimitReached
+	case pgErr.Code == "23505" && strings.HasPrefix(pgErr.ConstraintName, "uq_slides_"):
+		return domain.ErrSlideSequenceTaken
+	}
+	return err
+}
+
+func (r *StoryRepo) GetSlideByID(ctx context.Context, id uint) (*domain.Slide, error) {
+	var slide domain.Slide
+	if err := dbFrom(ctx, r.db).First(&slide, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &slide, nil
+}
+
+// UpdateSlide hanya menulis konten slide. Media, status dan job_id ditulis lewat
+// SetSlideJob dan UpdateSlideMedia agar hasil job media yang selesai selama request
+// berjalan tidak tertimpa nilai lama. Berlaku untuk slide story dan chapter.
+func (r *StoryRepo) UpdateSlide(ctx context.Context, s *domain.Slide) error {
+	return dbFrom(ctx, r.db).Model(s).Update("content", s.Content).Error
+}
+
+// UpdateSlideMedia hanya menulis kolom media dan status agar perubahan konten yang
+// terjadi selama media diproses tidak tertimpa. Berlaku untuk slide story dan chapter.
+// Mengembalikan ErrConflict jika job_id slide sudah bukan s.JobID, artinya job media
+// ini sudah digantikan job yang lebih baru.
+func (r *StoryRepo) UpdateSlideMedia(ctx context.Context, s *domain.Slide) error {
+	res := dbFrom(ctx, r.db).Model(s).Where("job_id = ?", s.JobID).Updates(map[string]interface{}{
+		"image_url": s.ImageURL,
+		"sound_url": s.SoundURL,
+		"status":    s.Status,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+// UpdateDominantColor hanya menulis warna dominan. Mengembalikan ErrConflict jika
+// thumbnail story sudah bukan s.ThumbnailURL.
+func (r *StoryRepo) UpdateDominantColor(ctx context.Context, s *domain.Story) error {
+	res := dbFrom(ctx, r.db).Model(&domain.Story{}).
+		Where("id = ? AND thumbnail_url = ?", s.ID, s.ThumbnailURL).
+		Update("dominant_color", s.DominantColor)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrConflict
+	}
+	return nil
+}
+
+// SetSlideJob menyimpan job media terbaru slide beserta statusnya.
+func (r *StoryRepo) SetSlideJob(ctx context.Context, s *domain.Slide) error {
+	return dbFrom(ctx, r.db).Model(s).Updates(map[string]interface{}{
+		"job_id": s.JobID,
+		"status": s.Status,
+	}).Error
+}
+
+// DeleteSlide menghapus slide story, slide_count dikurangi oleh trigger trg_update_slide_count.
+func (r *StoryRepo) DeleteSlide(ctx context.Context, s *domain.Slide) error {
+	return dbFrom(ctx, r.db).Delete(s).Error
+}
+
+func (r *StoryRepo) ReorderSlides(ctx context.Context, storyID uint, slideIDs []uint) error {
+	return dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return reorderSlides(tx, "story_id", storyID, slideIDs)
+	})
+}
+
+// reorderSlides menulis ulang sequence menjadi 1..n sesuai urutan slideIDs dalam satu
+// statement. slideIDs harus berisi tepat semua slide milik owner, tanpa duplikat.
+func reorderSlides(tx *gorm.DB, ownerColumn string, ownerID uint, slideIDs []uint) error {
+	var current []uint
+	if err := tx.Model(&domain.Slide{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(ownerColumn+" = ?", ownerID).
+		Pluck("id", &current).Error; err != nil {
+		return err
+	}
+	if len(current) != len(slideIDs) {
+		return domain.ErrBadParamInput
+	}
+
+	owned := make(map[uint]bool, len(current))
+	for _, id := range current {
+		owned[id] = true
+	}
+	ids := make([]string, len(slideIDs))
+	for i, id := range slideIDs {
+		if !owned[id] {
+			return domain.ErrBadParamInput
+		}
+		delete(owned, id)
+		ids[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// constraint uq_slides_*_sequence DEFERRABLE sehingga pertukaran sequence dalam satu
+	// statement ini tidak dianggap duplikat
+	return slideWriteError(tx.Exec(`UPDATE slides s SET sequence = o.seq, updated_at = now()
+		FROM unnest(string_to_array(?, ',')::bigint[]) WITH ORDINALITY AS o(id, seq)
+		WHERE s.id = o.id`, strings.Join(ids, ",")).Error)
 }
 
 func (r *StoryRepo) CountSlides(ctx context.Context, storyID uint) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&domain.Story{}).Select("slide_count").Where("id = ?", storyID).Scan(&count).Error
+	err := dbFrom(ctx, r.db).Model(&domain.Story{}).Select("slide_count").Where("id = ?", storyID).Scan(&count).Error
 	return count, err
 }
 
-func (r *StoryRepo) GetRecommendations(ctx context.Context, userID string) ([]domain.Recommendation, error) {
-	var recs []domain.Recommendation
+// scheduleLockKey dipakai pg_try_advisory_xact_lock agar hanya satu replika yang
+// menjalankan jadwal pada satu waktu.
+const scheduleLockKey int64 = 7_241_002
 
-	// Menggunakan Preload untuk memuat data Story dan Category terkait
-	err := r.db.WithContext(ctx).
-		Preload("Story").
-		Preload("Story.Category").
-		Where("user_id = ?", userID).
-		Order("score DESC").
-		Limit(10).
-		Find(&recs).Error
+func (r *StoryRepo) ListScheduled(ctx context.Context, limit int) ([]domain.ScheduledChange, error) {
+	var changes []domain.ScheduledChange
+	err := dbFrom(ctx, r.db).Raw(`
+		SELECT uuid::text AS story_uuid, title, status, ? AS action, publish_at AS at
+		FROM stories WHERE publish_at IS NOT NULL
+		UNION ALL
+		SELECT uuid::text AS story_uuid, title, status, ? AS action, unpublish_at AS at
+		FROM stories WHERE unpublish_at IS NOT NULL
+		ORDER BY at ASC
+		LIMIT ?
+	`, domain.ScheduleActionPublish, domain.ScheduleActionUnpublish, limit).Scan(&changes).Error
+	return changes, err
+}
 
-	return recs, err
+// ApplyDueSchedules menerbitkan story InReview yang publish_at-nya sudah lewat lalu
+// mengarsipkan story Published yang unpublish_at-nya sudah lewat. Jika replika lain
+// sedang memegang lock, tidak ada yang dikerjakan.
+func (r *StoryRepo) ApplyDueSchedules(ctx context.Context, now time.Time) (int64, error) {
+	var applied int64
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var leader bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", scheduleLockKey).Scan(&leader).Error; err != nil {
+			return err
+		}
+		if !leader {
+			return nil
+		}
+
+		res := tx.Model(&domain.Story{}).
+			Where("publish_at <= ? AND status = ?", now, domain.StatusInReview).
+			Updates(map[string]interface{}{
+				"status":       domain.StatusPublished,
+				"published_at": now,
+				"archived_at":  nil,
+				"publish_at":   nil,
+				"updated_at":   now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		applied += res.RowsAffected
+
+		res = tx.Model(&domain.Story{}).
+			Where("unpublish_at <= ? AND status = ?", now, domain.StatusPublished).
+			Updates(map[string]interface{}{
+				"status":       domain.StatusArchived,
+				"archived_at":  now,
+				"unpublish_at": nil,
+				"updated_at":   now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		applied += res.RowsAffected
+		return nil
+	})
+	return applied, err
+}
+
+// Import menyimpan story dari paket portabel dalam satu transaksi, dicocokkan lewat UUID.
+// Story dan chapter baru masuk sebagai Draft, yang sudah ada mempertahankan statusnya.
+// Story yang Published atau InReview ditolak dengan ErrInvalidTransition agar isinya
+// tidak berubah tanpa review. Slide story dan setiap chapter disamakan dengan paket,
+// chapter yang tidak ada di paket ikut dihapus. Mengembalikan true jika story baru dibuat.
+func (r *StoryRepo) Import(ctx context.Context, s *domain.Story) (bool, error) {
+	var created bool
+	err := dbFrom(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// posisi chapter dan sequence slide baru dicek di akhir transaksi
+		if err := tx.Exec("SET CONSTRAINTS uq_chapters_story_position, uq_slides_story_sequence, uq_slides_chapter_sequence DEFERRED").Error; err != nil {
+			return err
+		}
+
+		var existing domain.Story
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", s.UUID).Take(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			s.Status = domain.StatusDraft
+			if err := tx.Omit(clause.Associations).Create(s).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case existing.Status == domain.StatusPublished || existing.Status == domain.StatusInReview:
+			return domain.ErrInvalidTransition
+		default:
+			s.ID = existing.ID
+			s.Status = existing.Status
+			s.UserID = existing.UserID
+			if err := tx.Model(&existing).Updates(map[string]interface{}{
+				"title":          s.Title,
+				"description":    s.Description,
+				"thumbnail_url":  s.ThumbnailURL,
+				"dominant_color": s.DominantColor,
+				"category_id":    s.CategoryID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := replaceSlides(tx, "story_id", s.ID, s.Slides); err != nil {
+			return err
+		}
+
+		keep := make([]string, len(s.Chapters))
+		for i, c := range s.Chapters {
+			keep[i] = c.UUID
+		}
+		stale := tx.Model(&domain.Chapter{}).Select("id").Where("story_id = ?", s.ID)
+		if len(keep) > 0 {
+			stale = stale.Where("uuid NOT IN ?", keep)
+		}
+		if err := tx.Where("chapter_id IN (?)", stale).Delete(&domain.Slide{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN (?)", stale).Delete(&domain.Chapter{}).Error; err != nil {
+			return err
+		}
+
+		for i := range s.Chapters {
+			if err := importChapter(tx, s.ID, &s.Chapters[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, slideWriteError(err)
+	}
+	return created, nil
+}
+
+func importChapter(tx *gorm.DB, storyID uint, c *domain.Chapter) error {
+	var existing domain.Chapter
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", c.UUID).Take(&existing).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.StoryID = storyID
+		c.Status = domain.StatusDraft
+		c.SlideCount = len(c.Slides)
+		if err := tx.Omit(clause.Associations).Create(c).Error; err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case existing.StoryID != storyID:
+		// UUID chapter sudah dipakai story lain di environment ini
+		return domain.ErrConflict
+	default:
+		c.ID = existing.ID
+		c.StoryID = storyID
+		c.Status = existing.Status
+		c.PublishedAt = existing.PublishedAt
+		c.SlideCount = len(c.Slides)
+		if err := tx.Model(&existing).Updates(map[string]interface{}{
+			"title":            c.Title,
+			"synopsis":         c.Synopsis,
+			"position":         c.Position,
+			"duration_seconds": c.DurationSeconds,
+			"cover_url":        c.CoverURL,
+			"slide_count":      c.SlideCount,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return replaceSlides(tx, "chapter_id", c.ID, c.Slides)
+}
+
+// replaceSlides menyamakan slide milik owner dengan slides. Slide dicocokkan lewat
+// sequence agar ID-nya, dan riwayat revisinya, tetap sama; slide yang tidak ada di
+// slides dihapus. slide_count story ikut terjaga lewat trigger trg_update_slide_count.
+func replaceSlides(tx *gorm.DB, ownerColumn string, ownerID uint, slides []domain.Slide) error {
+	var existing []domain.Slide
+	if err := tx.Where(ownerColumn+" = ?", ownerID).Find(&existing).Error; err != nil {
+		return err
+	}
+	bySequence := make(map[int]uint, len(existing))
+	for _, s := range existing {
+		bySequence[s.Sequence] = s.ID
+	}
+
+	for i := range slides {
+		if ownerColumn == "story_id" {
+			slides[i].StoryID = &ownerID
+		} else {
+			slides[i].ChapterID = &ownerID
+		}
+		id, ok := bySequence[slides[i].Sequence]
+		if !ok {
+			slides[i].ID = 0
+			if err := tx.Create(&slides[i]).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		delete(bySequence, slides[i].Sequence)
+		slides[i].ID = id
+		slides[i].Status = domain.SlideStatusReady
+		// job media yang masih antre untuk slide ini tidak lagi berlaku
+		if err := tx.Model(&domain.Slide{ID: id}).Updates(map[string]interface{}{
+			"content":   slides[i].Content,
+			"image_url": slides[i].ImageURL,
+			"sound_url": slides[i].SoundURL,
+			"status":    slides[i].Status,
+			"job_id":    "",
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(bySequence) > 0 {
+		stale := make([]uint, 0, len(bySequence))
+		for _, id := range bySequence {
+			stale = append(stale, id)
+		}
+		return tx.Delete(&domain.Slide{}, stale).Error
+	}
+	return nil
 }
\ No newline at end of file
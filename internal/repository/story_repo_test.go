@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+)
+
+func TestStoryRepoDeleteRemovesChaptersAndSlides(t *testing.T) {
+	rec, db := newRecorder(t)
+	rec.returns(`SELECT "id" FROM "stories"`, []string{"id"}, []driver.Value{int64(7)})
+
+	err := repository.NewStoryRepository(db).Delete(context.Background(), "story-uuid")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`DELETE FROM "slides" WHERE chapter_id IN (SELECT "id" FROM "chapters" WHERE story_id = $1)`,
+		`DELETE FROM "chapters" WHERE story_id = $1`,
+		`DELETE FROM "slides" WHERE story_id = $1`,
+		`DELETE FROM "stories" WHERE "stories"."id" = $1`,
+	}, rec.deletes())
+}
+
+func TestStoryRepoUpdateWritesOnlyMetadata(t *testing.T) {
+	rec, db := newRecorder(t)
+
+	story := &domain.Story{
+		ID:            7,
+		Title:         "Title",
+		Status:        domain.StatusDraft,
+		DominantColor: "#000000",
+		SlideCount:    3,
+		UpdatedAt:     time.Now(),
+	}
+	err := repository.NewStoryRepository(db).Update(context.Background(), story)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`UPDATE "stories" SET "category_id"=$1,"description"=$2,"thumbnail_url"=$3,"title"=$4,"updated_at"=$5 WHERE id = $6`,
+	}, rec.updates())
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+)
+
+type txKey struct{}
+
+// TxManager menjalankan beberapa pemanggilan repository dalam satu transaksi. Repository
+// yang dipanggil dengan ctx dari WithinTransaction otomatis memakai transaksi tersebut.
+type TxManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction melakukan commit jika fn berhasil dan rollback jika fn mengembalikan
+// error. Pemanggilan bertingkat menjadi savepoint di transaksi luar.
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbFrom(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFrom mengembalikan transaksi yang sedang berjalan di ctx, atau db biasa jika tidak ada.
+func dbFrom(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
\ No newline at end of file
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime"
+	"path"
+	"strconv"
+	"time"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+// bundleCacheTTL cukup panjang karena key cache memuat versi manifest; perubahan isi
+// story otomatis menghasilkan key baru.
+const bundleCacheTTL = 24 * time.Hour
+
+type BundleUC struct {
+	cfg         *config.Config
+	storyRepo   domain.StoryRepository
+	chapterRepo domain.ChapterRepository
+	uploader    domain.StorageRepository
+	redisRepo   domain.RedisRepository
+	signer      domain.ManifestSigner
+}
+
+func NewBundleUseCase(cfg *config.Config, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository, uploader domain.StorageRepository, redisRepo domain.RedisRepository, signer domain.ManifestSigner) *BundleUC {
+	return &BundleUC{cfg: cfg, storyRepo: storyRepo, chapterRepo: chapterRepo, uploader: uploader, redisRepo: redisRepo, signer: signer}
+}
+
+// StoryBundle membuat manifest untuk seluruh story: slide story sendiri dan semua
+// chapter yang sudah terbit.
+func (u *BundleUC) StoryBundle(ctx context.Context, storyUUID string) (*domain.SignedBundle, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	chapters, err := u.chapterRepo.GetAllByStoryID(ctx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := u.newBuilder(domain.BundleKindStory, story)
+	for _, slide := range story.Slides {
+		b.manifest.Slides = append(b.manifest.Slides, b.slide(slide, u.cfg.AzureContainer))
+	}
+	for _, c := range chapters {
+		if c.Status != domain.StatusPublished {
+			continue
+		}
+		// GetAllByStoryID tidak memuat slide
+		chapter, err := u.chapterRepo.GetByUUID(ctx, c.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if chapter == nil {
+			continue
+		}
+		b.manifest.Chapters = append(b.manifest.Chapters, b.chapter(chapter))
+	}
+	return u.finish(ctx, story.UUID, b.build())
+}
+
+// ChapterBundle membuat manifest untuk satu chapter terbit beserta metadata story-nya.
+func (u *BundleUC) ChapterBundle(ctx context.Context, chapterUUID string) (*domain.SignedBundle, error) {
+	chapter, err := u.chapterRepo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil || chapter.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	story, err := u.storyRepo.GetByID(ctx, chapter.StoryID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	b := u.newBuilder(domain.BundleKindChapter, story)
+	b.manifest.Chapters = []domain.BundleChapter{b.chapter(chapter)}
+	return u.finish(ctx, chapter.UUID, b.build())
+}
+
+func (u *BundleUC) PublicKey() (string, string) {
+	return u.signer.Algorithm(), u.signer.PublicKey()
+}
+
+// finish menghitung versi dari isi manifest, lalu checksum setiap file. Checksum mahal
+// karena seluruh file dibaca dari storage, jadi manifest lengkap di-cache per versi.
+// Tanda tangan selalu dibuat ulang supaya mengikuti kunci yang sedang aktif.
+func (u *BundleUC) finish(ctx context.Context, key string, m domain.BundleManifest) (*domain.SignedBundle, error) {
+	m.SchemaVersion = domain.BundleSchemaVersion
+	content, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	m.Version = hex.EncodeToString(sum[:])
+
+	cacheKey := domain.CacheKeyBundlePrefix + m.Kind + ":" + key + ":" + m.Version
+	var raw []byte
+	if u.redisRepo != nil {
+		if cached, _ := u.redisRepo.Get(ctx, cacheKey); cached != "" {
+			raw = []byte(cached)
+		}
+	}
+
+	if raw == nil {
+		for i := range m.Files {
+			if err := u.checksum(ctx, &m.Files[i]); err != nil {
+				return nil, err
+			}
+		}
+		m.GeneratedAt = time.Now().UTC()
+		if raw, err = json.Marshal(m); err != nil {
+			return nil, err
+		}
+		if u.redisRepo != nil {
+			u.redisRepo.Set(ctx, cacheKey, raw, bundleCacheTTL)
+		}
+	}
+
+	return &domain.SignedBundle{
+		Manifest:  raw,
+		Signature: u.signer.Sign(raw),
+		Algorithm: u.signer.Algorithm(),
+		Version:   m.Version,
+		Files:     m.Files,
+	}, nil
+}
+
+func (u *BundleUC) checksum(ctx context.Context, f *domain.BundleFile) error {
+	rc, err := u.uploader.OpenFromContainer(ctx, f.Container, f.URL)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, rc)
+	if err != nil {
+		return err
+	}
+	f.Size = n
+	f.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// WriteZip menulis manifest.json, manifest.sig dan semua file media ke w sebagai ZIP.
+// Media sudah terkompresi sehingga disimpan tanpa deflate.
+func (u *BundleUC) WriteZip(ctx context.Context, bundle *domain.SignedBundle, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{"manifest.json", bundle.Manifest},
+		{"manifest.sig", []byte(bundle.Signature)},
+	}
+	for _, e := range entries {
+		fw, err := zw.Create(e.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(e.data); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range bundle.Files {
+		if err := copyMediaToZip(ctx, u.uploader, zw, f); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func copyMediaToZip(ctx context.Context, uploader domain.StorageRepository, zw *zip.Writer, f domain.BundleFile) error {
+	rc, err := uploader.OpenFromContainer(ctx, f.Container, f.URL)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Path, Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, rc)
+	return err
+}
+
+// mediaSet memetakan setiap URL media ke satu path di ZIP. URL yang sama (misal gambar
+// yang dipakai dua slide) hanya dikemas sekali.
+type mediaSet struct {
+	files []domain.BundleFile
+	paths map[string]string
+	used  map[string]bool
+}
+
+func newMediaSet() *mediaSet {
+	return &mediaSet{files: []domain.BundleFile{}, paths: map[string]string{}, used: map[string]bool{}}
+}
+
+func (m *mediaSet) add(url, container, dir string) string {
+	if url == "" {
+		return ""
+	}
+	if p, ok := m.paths[url]; ok {
+		return p
+	}
+
+	name := path.Base(url)
+	p := dir + "/" + name
+	if m.used[p] {
+		p = dir + "/" + strconv.Itoa(len(m.files)) + "-" + name
+	}
+	m.paths[url] = p
+	m.used[p] = true
+
+	m.files = append(m.files, domain.BundleFile{
+		Path:        p,
+		URL:         url,
+		ContentType: bundleContentType(name),
+		Container:   container,
+	})
+	return p
+}
+
+// bundleBuilder menyusun manifest bundle offline beserta daftar file medianya.
+type bundleBuilder struct {
+	cfg      *config.Config
+	manifest domain.BundleManifest
+	media    *mediaSet
+}
+
+func (u *BundleUC) newBuilder(kind string, story *domain.Story) *bundleBuilder {
+	b := &bundleBuilder{cfg: u.cfg, media: newMediaSet()}
+	b.manifest = domain.BundleManifest{
+		Kind:        kind,
+		StoryID:     story.UUID,
+		Title:       story.Title,
+		Description: story.Description,
+	}
+	b.manifest.Thumbnail = b.media.add(story.ThumbnailURL, u.cfg.AzureContainerStoriesName, "images")
+	return b
+}
+
+func (b *bundleBuilder) build() domain.BundleManifest {
+	b.manifest.Files = b.media.files
+	return b.manifest
+}
+
+func (b *bundleBuilder) chapter(c *domain.Chapter) domain.BundleChapter {
+	bc := domain.BundleChapter{
+		ID:              c.UUID,
+		Title:           c.Title,
+		Synopsis:        c.Synopsis,
+		Position:        c.Position,
+		DurationSeconds: c.DurationSeconds,
+		Cover:           b.media.add(c.CoverURL, b.cfg.AzureContainerChapterImages, "images"),
+		Slides:          []domain.BundleSlide{},
+	}
+	for _, slide := range c.Slides {
+		bc.Slides = append(bc.Slides, b.slide(slide, b.cfg.AzureContainerChapterImages))
+	}
+	return bc
+}
+
+func (b *bundleBuilder) slide(s domain.Slide, imageContainer string) domain.BundleSlide {
+	return domain.BundleSlide{
+		Sequence: s.Sequence,
+		Content:  s.Content,
+		Image:    b.media.add(s.ImageURL, imageContainer, "images"),
+		Sound:    b.media.add(s.SoundURL, b.cfg.AzureContainerChapterSounds, "audio"),
+	}
+}
+
+func bundleContentType(name string) string {
+	ext := path.Ext(name)
+	if ext == ".m4a" {
+		return "audio/mp4"
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
\ No newline at end of file
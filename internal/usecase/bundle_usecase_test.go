@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
+
+)
+
+func newBundleFixture(t *testing.T) (*config.Config, domain.StorageRepository, map[string]string) {
+	cfg := &config.Config{
+		AzureContainer:              "media",
+		AzureContainerStoriesName:   "stories",
+		AzureContainerChapterImages: "chapter-images",
+		AzureContainerChapterSounds: "chapter-sounds",
+	}
+	storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", cfg.AzureContainer)
+	require.NoError(t, err)
+
+	files := map[string]string{
+		"stories/thumb.jpg":        "thumb",
+		"chapter-images/a.jpg":     "image-a",
+		"chapter-sounds/a.m4a":     "sound-a",
+		"chapter-images/cover.png": "cover",
+	}
+	urls := map[string]string{}
+	for name, content := range files {
+		container, filename, _ := strings.Cut(name, "/")
+		url, err := storage.UploadToContainer(context.TODO(), strings.NewReader(content), container, filename)
+		require.NoError(t, err)
+		urls[name] = url
+	}
+	return cfg, storage, urls
+}
+
+func TestBundleUseCase_ChapterBundle(t *testing.T) {
+	ctx := context.TODO()
+	cfg, storage, urls := newBundleFixture(t)
+	signer, err := utils.NewEd25519Signer("")
+	require.NoError(t, err)
+
+	story := &domain.Story{ID: 1, UUID: "s-1", Title: "Nabi Yunus", ThumbnailURL: urls["stories/thumb.jpg"], Status: domain.StatusPublished}
+	chapter := func(content string) *domain.Chapter {
+		return &domain.Chapter{
+			ID: 2, UUID: "c-1", StoryID: 1, Title: "Di perut ikan", Position: 1, Status: domain.StatusPublished,
+			CoverURL: urls["chapter-images/cover.png"],
+			Slides: []domain.Slide{
+				{Sequence: 1, Content: content, ImageURL: urls["chapter-images/a.jpg"], SoundURL: urls["chapter-sounds/a.m4a"]},
+				{Sequence: 2, Content: "Berdoa", ImageURL: urls["chapter-images/a.jpg"]},
+			},
+		}
+	}
+
+	bundle := func(content string) *domain.SignedBundle {
+		mockStories := new(mocks.StoryRepositoryMock)
+		mockChapters := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewBundleUseCase(cfg, mockStories, mockChapters, storage, nil, signer)
+
+		mockChapters.On("GetByUUID", ctx, "c-1").Return(chapter(content), nil)
+		mockStories.On("GetByID", ctx, uint(1)).Return(story, nil)
+
+		res, err := uc.ChapterBundle(ctx, "c-1")
+		require.NoError(t, err)
+		return res
+	}
+
+	first := bundle("Ditelan ikan")
+
+	t.Run("manifest lists each file once with checksum", func(t *testing.T) {
+		var m domain.BundleManifest
+		require.NoError(t, json.Unmarshal(first.Manifest, &m))
+
+		assert.Equal(t, first.Version, m.Version)
+		assert.Equal(t, domain.BundleKindChapter, m.Kind)
+		require.Len(t, m.Files, 4)
+		require.Len(t, m.Chapters, 1)
+		slides := m.Chapters[0].Slides
+		assert.Equal(t, slides[0].Image, slides[1].Image)
+		assert.Equal(t, "audio/a.m4a", slides[0].Sound)
+
+		sum := sha256.Sum256([]byte("sound-a"))
+		for _, f := range m.Files {
+			if f.Path == "audio/a.m4a" {
+				assert.Equal(t, hex.EncodeToString(sum[:]), f.SHA256)
+				assert.Equal(t, int64(7), f.Size)
+				assert.Equal(t, "audio/mp4", f.ContentType)
+			}
+		}
+	})
+
+	t.Run("signature covers manifest bytes", func(t *testing.T) {
+		key, _ := base64.StdEncoding.DecodeString(signer.PublicKey())
+		sig, _ := base64.StdEncoding.DecodeString(first.Signature)
+		assert.True(t, ed25519.Verify(key, first.Manifest, sig))
+	})
+
+	t.Run("version follows content", func(t *testing.T) {
+		assert.Equal(t, first.Version, bundle("Ditelan ikan").Version)
+		assert.NotEqual(t, first.Version, bundle("Ditelan ikan paus").Version)
+	})
+
+	t.Run("zip holds manifest and media", func(t *testing.T) {
+		uc := usecase.NewBundleUseCase(cfg, nil, nil, storage, nil, signer)
+		var buf bytes.Buffer
+		require.NoError(t, uc.WriteZip(ctx, first, &buf))
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		contents := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			contents[f.Name] = string(data)
+		}
+		assert.Equal(t, string(first.Manifest), contents["manifest.json"])
+		assert.Equal(t, first.Signature, contents["manifest.sig"])
+		assert.Equal(t, "image-a", contents["images/a.jpg"])
+		assert.Equal(t, "sound-a", contents["audio/a.m4a"])
+	})
+}
+
+func TestBundleUseCase_UnpublishedChapter(t *testing.T) {
+	ctx := context.TODO()
+	mockChapters := new(mocks.ChapterRepositoryMock)
+	uc := usecase.NewBundleUseCase(&config.Config{}, nil, mockChapters, nil, nil, nil)
+
+	mockChapters.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 2, UUID: "c-1", Status: domain.StatusDraft}, nil)
+
+	_, err := uc.ChapterBundle(ctx, "c-1")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
\ No newline at end of file
@@ -17,22 +17,52 @@ import (
 type CategoryUC struct {
 	categoryRepo domain.CategoryRepository
 	redisRepo    domain.RedisRepository
-	uploader     *utils.AzureUploader
+	uploader     domain.StorageRepository
+	jobRepo      domain.JobRepository
+	tx           domain.Transactor
 	cfg          *config.Config
 }
 
-func NewCategoryUseCase(repo domain.CategoryRepository, redis domain.RedisRepository, uploader *utils.AzureUploader) *CategoryUC {
-	cfg := config.LoadConfig() 
-	
+func NewCategoryUseCase(cfg *config.Config, repo domain.CategoryRepository, redis domain.RedisRepository, uploader domain.StorageRepository, jobRepo domain.JobRepository, tx domain.Transactor) *CategoryUC {
 	return &CategoryUC{
 		categoryRepo: repo,
 		redisRepo:    redis,
 		uploader:     uploader,
+		jobRepo:      jobRepo,
+		tx:           tx,
 		cfg:          cfg,
 	}
 }
 
-func (uc *CategoryUC) Create(ctx context.Context, name string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+func validCategoryType(t string) bool {
+	switch t {
+	case domain.CategoryTypeStory, domain.CategoryTypeDakwah, domain.CategoryTypeHadist:
+		return true
+	}
+	return false
+}
+
+// resolveParent memastikan parent ada, satu section dengan kategori, dan merupakan
+// kategori utama karena sub-kategori hanya satu tingkat.
+func (uc *CategoryUC) resolveParent(ctx context.Context, parentUUID, categoryType string) (*domain.Category, error) {
+	parent, err := uc.categoryRepo.GetByUUID(ctx, parentUUID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil || parent.Type != categoryType || parent.ParentID != nil {
+		return nil, domain.ErrBadParamInput
+	}
+	return parent, nil
+}
+
+func (uc *CategoryUC) Create(ctx context.Context, name, categoryType, parentUUID string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+	if categoryType == "" {
+		categoryType = domain.CategoryTypeStory
+	}
+	if !validCategoryType(categoryType) {
+		return nil, domain.ErrBadParamInput
+	}
+
 	existing, _ := uc.categoryRepo.GetByName(ctx, name)
 	if existing != nil {
 		return nil, domain.ErrConflict
@@ -41,6 +71,16 @@ func (uc *CategoryUC) Create(ctx context.Context, name string, file multipart.Fi
 	category := &domain.Category{
 		UUID: uuid.New().String(),
 		Name: name,
+		Type: categoryType,
+	}
+
+	if parentUUID != "" {
+		parent, err := uc.resolveParent(ctx, parentUUID, categoryType)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		category.Parent = parent
 	}
 
 	if err := uc.categoryRepo.Create(ctx, category); err != nil {
@@ -48,7 +88,8 @@ func (uc *CategoryUC) Create(ctx context.Context, name string, file multipart.Fi
 	}
 
 	if file != nil {
-		imageURL, domColor, err := utils.UploadAndAnalyzeImage(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "categories/", category.UUID)
+		// warna dominan diambil worker, kategori tetap bisa dipakai sebelum warnanya ada
+		imageURL, err := utils.UploadFile(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "categories/", category.UUID)
 		
 		if err != nil {
 			uc.categoryRepo.Delete(ctx, category.UUID)
@@ -56,9 +97,14 @@ func (uc *CategoryUC) Create(ctx context.Context, name string, file multipart.Fi
 		}
 
 		category.ImageURL = imageURL
-		category.DominantColor = domColor
 
-		if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		err = inTransaction(ctx, uc.tx, func(ctx context.Context) error {
+			if err := uc.categoryRepo.Update(ctx, category); err != nil {
+				return err
+			}
+			return uc.queuePalette(ctx, category)
+		})
+		if err != nil {
 			uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, imageURL)
 			uc.categoryRepo.Delete(ctx, category.UUID)
 			return nil, err
@@ -72,11 +118,59 @@ func (uc *CategoryUC) Create(ctx context.Context, name string, file multipart.Fi
 	return category, nil
 }
 
-func (uc *CategoryUC) Update(ctx context.Context, uuid string, name string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+// Update mengubah kategori. parentUUID nil berarti parent tidak diubah, string kosong
+// menjadikannya kategori utama. Type hanya bisa diganti selama kategori belum punya
+// parent maupun sub-kategori, dan belum dipakai story atau pilihan user.
+func (uc *CategoryUC) Update(ctx context.Context, uuid string, name, categoryType string, parentUUID *string, file multipart.File, header *multipart.FileHeader) (*domain.Category, error) {
+	if categoryType != "" && !validCategoryType(categoryType) {
+		return nil, domain.ErrBadParamInput
+	}
+
 	category, err := uc.categoryRepo.GetByUUID(ctx, uuid)
 	if err != nil { return nil, err }
 	if category == nil { return nil, domain.ErrNotFound }
 
+	if parentUUID != nil {
+		category.ParentID = nil
+		category.Parent = nil
+	}
+	typeChanged := categoryType != "" && categoryType != category.Type
+	if typeChanged && category.ParentID != nil {
+		return nil, domain.ErrBadParamInput
+	}
+	if typeChanged || (parentUUID != nil && *parentUUID != "") {
+		// kategori yang punya sub-kategori tidak boleh pindah section atau menjadi sub-kategori
+		children, err := uc.categoryRepo.CountChildren(ctx, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		if children > 0 {
+			return nil, domain.ErrBadParamInput
+		}
+	}
+	if typeChanged {
+		// story dan pilihan user terikat ke section kategorinya
+		refs, err := uc.categoryRepo.CountReferences(ctx, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		if refs > 0 {
+			return nil, domain.ErrCategoryInUse
+		}
+		category.Type = categoryType
+	}
+	if parentUUID != nil && *parentUUID != "" {
+		if *parentUUID == category.UUID {
+			return nil, domain.ErrBadParamInput
+		}
+		parent, err := uc.resolveParent(ctx, *parentUUID, category.Type)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+		category.Parent = parent
+	}
+
 	oldImageURL := category.ImageURL
 
 	if name != "" && name != category.Name {
@@ -91,17 +185,26 @@ func (uc *CategoryUC) Update(ctx context.Context, uuid string, name string, file
 	if file != nil {
 		newUUID := uuid
 		
-		url, color, err := utils.UploadAndAnalyzeImage(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "categories/", newUUID)
+		url, err := utils.UploadFile(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "categories/", newUUID)
 		if err != nil {
 			return nil, err
 		}
 		
 		newImageURL = url
 		category.ImageURL = newImageURL
-		category.DominantColor = color
 	}
 
-	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+	// warna dominan lama dipakai sampai worker selesai mengambil warna gambar baru
+	err = inTransaction(ctx, uc.tx, func(ctx context.Context) error {
+		if err := uc.categoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+		if newImageURL == "" {
+			return nil
+		}
+		return uc.queuePalette(ctx, category)
+	})
+	if err != nil {
 		if newImageURL != "" {
 			uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, newImageURL)
 		}
@@ -139,31 +242,95 @@ func (uc *CategoryUC) Delete(ctx context.Context, uuid string) error {
 	return nil
 }
 
-func (uc *CategoryUC) GetAll(ctx context.Context) ([]domain.Category, error) {
-	cacheKey := domain.CacheKeyCategoryAll
+func (uc *CategoryUC) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Category, *domain.PageInfo, error) {
+	cacheKey := listCacheKey(domain.CacheKeyCategoryAll+":", q)
 
 	if uc.redisRepo != nil {
 		cachedData, err := uc.redisRepo.Get(ctx, cacheKey)
 		if err == nil && cachedData != "" {
-			var categories []domain.Category
-			if err := json.Unmarshal([]byte(cachedData), &categories); err == nil {
-				return categories, nil
+			var page listPage[domain.Category]
+			if err := json.Unmarshal([]byte(cachedData), &page); err == nil && page.Page != nil {
+				return page.Items, page.Page, nil
 			}
 		}
 	}
 
-	categories, err := uc.categoryRepo.GetAll(ctx)
+	categories, info, err := uc.categoryRepo.GetAll(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uc.redisRepo != nil {
+		if data, err := json.Marshal(listPage[domain.Category]{Items: categories, Page: info}); err == nil {
+			_ = uc.redisRepo.Set(ctx, cacheKey, data, 30*time.Minute)
+		}
+	}
+
+	return categories, info, nil
+}
+
+// Tree mengembalikan kategori utama beserta sub-kategorinya. Hasilnya di-cache bersama
+// daftar kategori dan ikut dihapus setiap kali kategori berubah.
+func (uc *CategoryUC) Tree(ctx context.Context, categoryType string) ([]domain.Category, error) {
+	if categoryType != "" && !validCategoryType(categoryType) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	cacheKey := domain.CacheKeyCategoryAll + ":tree:" + categoryType
+	if uc.redisRepo != nil {
+		if cached, _ := uc.redisRepo.Get(ctx, cacheKey); cached != "" {
+			var tree []domain.Category
+			if json.Unmarshal([]byte(cached), &tree) == nil {
+				return tree, nil
+			}
+		}
+	}
+
+	categories, err := uc.categoryRepo.ListByType(ctx, categoryType)
 	if err != nil {
 		return nil, err
 	}
+	tree := buildCategoryTree(categories)
 
 	if uc.redisRepo != nil {
-		if data, err := json.Marshal(categories); err == nil {
+		if data, err := json.Marshal(tree); err == nil {
 			_ = uc.redisRepo.Set(ctx, cacheKey, data, 30*time.Minute)
 		}
 	}
+	return tree, nil
+}
+
+// buildCategoryTree menyusun daftar datar menjadi pohon satu tingkat dengan urutan
+// yang sama seperti masukan. Sub-kategori yang parent-nya tidak ikut dimuat dijadikan
+// kategori utama.
+func buildCategoryTree(categories []domain.Category) []domain.Category {
+	index := make(map[uint]int, len(categories))
+	for i, c := range categories {
+		if c.ParentID == nil {
+			index[c.ID] = i
+		}
+	}
+
+	children := make(map[uint][]domain.Category)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if _, ok := index[*c.ParentID]; ok {
+				children[*c.ParentID] = append(children[*c.ParentID], c)
+			}
+		}
+	}
 
-	return categories, nil
+	tree := make([]domain.Category, 0, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if _, ok := index[*c.ParentID]; ok {
+				continue
+			}
+		}
+		c.Children = children[c.ID]
+		tree = append(tree, c)
+	}
+	return tree
 }
 
 func (uc *CategoryUC) Get(ctx context.Context, uuid string) (*domain.Category, error) {
@@ -175,4 +342,12 @@ func (uc *CategoryUC) Get(ctx context.Context, uuid string) (*domain.Category, e
 
 func (uc *CategoryUC) Search(ctx context.Context, query string) ([]domain.Category, error) {
 	return uc.categoryRepo.Search(ctx, query)
+}
+
+// queuePalette mengantrekan pengambilan warna dominan, kategori tanpa gambar dilewati.
+func (uc *CategoryUC) queuePalette(ctx context.Context, category *domain.Category) error {
+	if category.ImageURL == "" {
+		return nil
+	}
+	return queueImagePalette(ctx, uc.jobRepo, uc.cfg, domain.PaletteEntityCategory, category.UUID, category.ImageURL)
 }
\ No newline at end of file
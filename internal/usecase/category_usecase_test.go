@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"khalif-stories/internal/config"
 	"khalif-stories/internal/domain"
 	"khalif-stories/internal/mocks"
 	"khalif-stories/internal/usecase"
@@ -21,7 +22,7 @@ func TestCategoryUseCase_Create(t *testing.T) {
 		mockRepo := new(mocks.CategoryRepositoryMock)
 		mockRedis := new(mocks.RedisRepositoryMock)
 		
-		uc := usecase.NewCategoryUseCase(mockRepo, mockRedis, nil)
+		uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, mockRedis, nil, nil, nil)
 
 		mockRepo.On("GetByName", ctx, "New Category").Return(nil, nil)
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Category")).Return(nil)
@@ -29,7 +30,7 @@ func TestCategoryUseCase_Create(t *testing.T) {
 		mockRedis.On("DeletePrefix", ctx, mock.Anything).Return(nil).Maybe()
 		mockRedis.On("Del", ctx, mock.Anything).Return(nil).Maybe()
 
-		res, err := uc.Create(ctx, "New Category", nil, nil)
+		res, err := uc.Create(ctx, "New Category", "", "", nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -41,28 +42,28 @@ func TestCategoryUseCase_Create(t *testing.T) {
 		mockRepo := new(mocks.CategoryRepositoryMock)
 		mockRedis := new(mocks.RedisRepositoryMock)
 		
-		uc := usecase.NewCategoryUseCase(mockRepo, mockRedis, nil)
+		uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, mockRedis, nil, nil, nil)
 
 		existingCategory := &domain.Category{Name: "Existing"}
 		mockRepo.On("GetByName", ctx, "Existing").Return(existingCategory, nil)
 
-		res, err := uc.Create(ctx, "Existing", nil, nil)
+		res, err := uc.Create(ctx, "Existing", "", "", nil, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, res)
-		assert.Equal(t, "category name exists", err.Error())
+		assert.ErrorIs(t, err, domain.ErrConflict)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo := new(mocks.CategoryRepositoryMock)
 		mockRedis := new(mocks.RedisRepositoryMock)
 		
-		uc := usecase.NewCategoryUseCase(mockRepo, mockRedis, nil)
+		uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, mockRedis, nil, nil, nil)
 
 		mockRepo.On("GetByName", ctx, "Error Cat").Return(nil, nil)
 		mockRepo.On("Create", ctx, mock.Anything).Return(errors.New("db error"))
 
-		res, err := uc.Create(ctx, "Error Cat", nil, nil)
+		res, err := uc.Create(ctx, "Error Cat", "", "", nil, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, res)
@@ -76,7 +77,7 @@ func TestCategoryUseCase_GetAll(t *testing.T) {
 		mockRepo := new(mocks.CategoryRepositoryMock)
 		mockRedis := new(mocks.RedisRepositoryMock)
 		
-		uc := usecase.NewCategoryUseCase(mockRepo, mockRedis, nil)
+		uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, mockRedis, nil, nil, nil)
 
 		categories := []domain.Category{
 			{Name: "Cat 1"},
@@ -85,12 +86,113 @@ func TestCategoryUseCase_GetAll(t *testing.T) {
 
 		mockRedis.On("Get", ctx, mock.Anything).Return("", errors.New("redis nil")).Maybe()
 		mockRedis.On("Set", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
-		mockRepo.On("GetAll", ctx).Return(categories, nil)
+		q := domain.ListQuery{Limit: 50, Sort: []domain.SortField{{Field: "created_at"}}}
+		mockRepo.On("GetAll", ctx, q).Return(categories, &domain.PageInfo{NextCursor: "next", HasMore: true}, nil)
 
-		res, err := uc.GetAll(ctx)
+		res, page, err := uc.GetAll(ctx, q)
 
 		assert.NoError(t, err)
 		assert.Len(t, res, 2)
+		assert.True(t, page.HasMore)
 		mockRepo.AssertExpectations(t)
 	})
+}
+
+func TestCategoryUseCase_CreateSubCategory(t *testing.T) {
+	ctx := context.TODO()
+	parentID := uint(1)
+
+	tests := []struct {
+		name        string
+		parent      *domain.Category
+		expectedErr error
+	}{
+		{"same section", &domain.Category{ID: 1, UUID: "p-1", Type: domain.CategoryTypeDakwah}, nil},
+		{"other section", &domain.Category{ID: 1, UUID: "p-1", Type: domain.CategoryTypeStory}, domain.ErrBadParamInput},
+		{"parent is a sub-category", &domain.Category{ID: 1, UUID: "p-1", Type: domain.CategoryTypeDakwah, ParentID: &parentID}, domain.ErrBadParamInput},
+		{"unknown parent", nil, domain.ErrBadParamInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CategoryRepositoryMock)
+			uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+			mockRepo.On("GetByName", ctx, "Fiqih").Return(nil, nil)
+			mockRepo.On("GetByUUID", ctx, "p-1").Return(tt.parent, nil)
+			mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Category")).Return(nil)
+
+			res, err := uc.Create(ctx, "Fiqih", domain.CategoryTypeDakwah, "p-1", nil, nil)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.CategoryTypeDakwah, res.Type)
+			assert.Equal(t, uint(1), *res.ParentID)
+		})
+	}
+}
+
+func TestCategoryUseCase_UpdateType(t *testing.T) {
+	ctx := context.TODO()
+
+	tests := []struct {
+		name        string
+		refs        int64
+		expectedErr error
+	}{
+		{"unused category", 0, nil},
+		{"used by stories or user choices", 2, domain.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CategoryRepositoryMock)
+			uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+			mockRepo.On("GetByUUID", ctx, "c-1").Return(&domain.Category{ID: 4, UUID: "c-1", Name: "Fiqih", Type: domain.CategoryTypeStory}, nil)
+			mockRepo.On("CountChildren", ctx, uint(4)).Return(int64(0), nil)
+			mockRepo.On("CountReferences", ctx, uint(4)).Return(tt.refs, nil)
+			mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Category")).Return(nil)
+
+			res, err := uc.Update(ctx, "c-1", "", domain.CategoryTypeDakwah, nil, nil, nil)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.ErrorIs(t, err, domain.ErrCategoryInUse)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.CategoryTypeDakwah, res.Type)
+		})
+	}
+}
+
+func TestCategoryUseCase_Tree(t *testing.T) {
+	ctx := context.TODO()
+	mockRepo := new(mocks.CategoryRepositoryMock)
+	uc := usecase.NewCategoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+	akhlak, fiqih := uint(1), uint(2)
+	mockRepo.On("ListByType", ctx, domain.CategoryTypeDakwah).Return([]domain.Category{
+		{ID: 1, Name: "Akhlak"},
+		{ID: 3, Name: "Adab Makan", ParentID: &akhlak},
+		{ID: 2, Name: "Fiqih"},
+		{ID: 4, Name: "Shalat", ParentID: &fiqih},
+		{ID: 5, Name: "Zakat", ParentID: &fiqih},
+	}, nil)
+
+	tree, err := uc.Tree(ctx, domain.CategoryTypeDakwah)
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Akhlak", tree[0].Name)
+	assert.Len(t, tree[0].Children, 1)
+	assert.Equal(t, "Fiqih", tree[1].Name)
+	assert.Len(t, tree[1].Children, 2)
+	assert.Equal(t, "Shalat", tree[1].Children[0].Name)
 }
\ No newline at end of file
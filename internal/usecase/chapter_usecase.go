@@ -2,9 +2,8 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"mime/multipart"
-	"os"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -15,35 +14,201 @@ import (
 )
 
 type ChapterUC struct {
-	cfg         *config.Config
-	repo        domain.ChapterRepository
-	storyRepo   domain.StoryRepository
-	uploader    *utils.AzureUploader
+	cfg          *config.Config
+	repo         domain.ChapterRepository
+	storyRepo    domain.StoryRepository
+	uploader     domain.StorageRepository
+	revisionRepo domain.RevisionRepository
+	jobRepo      domain.JobRepository
+	tx           domain.Transactor
 }
 
-func NewChapterUseCase(cfg *config.Config, repo domain.ChapterRepository, storyRepo domain.StoryRepository, uploader *utils.AzureUploader) *ChapterUC {
-	return &ChapterUC{cfg: cfg, repo: repo, storyRepo: storyRepo, uploader: uploader}
+func NewChapterUseCase(cfg *config.Config, repo domain.ChapterRepository, storyRepo domain.StoryRepository, uploader domain.StorageRepository, revisionRepo domain.RevisionRepository, jobRepo domain.JobRepository, tx domain.Transactor) *ChapterUC {
+	return &ChapterUC{cfg: cfg, repo: repo, storyRepo: storyRepo, uploader: uploader, revisionRepo: revisionRepo, jobRepo: jobRepo, tx: tx}
 }
 
-func (u *ChapterUC) Create(ctx context.Context, storyUUID string) (*domain.Chapter, error) {
+// chapterCoverPath adalah folder cover chapter di container gambar chapter.
+const chapterCoverPath = "covers/"
+
+// Create membuat chapter Draft di posisi terakhir story.
+func (u *ChapterUC) Create(ctx context.Context, storyUUID, title, synopsis string, durationSeconds int, cover multipart.File, coverHeader *multipart.FileHeader) (*domain.Chapter, error) {
 	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
-	if err != nil || story == nil {
-		return nil, errors.New("story not found")
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
+	if durationSeconds < 0 {
+		return nil, domain.ErrBadParamInput
 	}
 
 	chapter := &domain.Chapter{
-		UUID:    uuid.New().String(),
-		StoryID: story.ID,
+		UUID:            uuid.New().String(),
+		StoryID:         story.ID,
+		Title:           title,
+		Synopsis:        synopsis,
+		DurationSeconds: durationSeconds,
+		Status:          domain.StatusDraft,
 	}
 
+	coverURL, err := utils.UploadFile(ctx, u.uploader, cover, coverHeader, u.cfg.AzureContainerChapterImages, chapterCoverPath, chapter.UUID)
+	if err != nil {
+		return nil, err
+	}
+	chapter.CoverURL = coverURL
+
 	if err := u.repo.Create(ctx, chapter); err != nil {
+		u.deleteMedia(ctx, coverURL, "")
+		return nil, err
+	}
+
+	return chapter, nil
+}
+
+// Update mengubah metadata chapter, nilai kosong berarti tidak diubah. Cover lama tetap
+// disimpan karena masih dirujuk revisi.
+func (u *ChapterUC) Update(ctx context.Context, chapterUUID, title, synopsis string, durationSeconds *int, cover multipart.File, coverHeader *multipart.FileHeader) (*domain.Chapter, error) {
+	chapter, err := u.AdminGetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return nil, err
+	}
+	if durationSeconds != nil && *durationSeconds < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	before := snapshotChapter(chapter)
+	if title != "" {
+		chapter.Title = title
+	}
+	if synopsis != "" {
+		chapter.Synopsis = synopsis
+	}
+	if durationSeconds != nil {
+		chapter.DurationSeconds = *durationSeconds
+	}
+
+	coverURL, err := utils.UploadFile(ctx, u.uploader, cover, coverHeader, u.cfg.AzureContainerChapterImages, chapterCoverPath, uuid.New().String())
+	if err != nil {
 		return nil, err
 	}
+	if coverURL != "" {
+		chapter.CoverURL = coverURL
+	}
 
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.Update(ctx, chapter); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, chapter.StoryID, domain.RevisionEntityChapter, chapter.UUID, domain.RevisionActionUpdate, before, snapshotChapter(chapter))
+	})
+	if err != nil {
+		u.deleteMedia(ctx, coverURL, "")
+		return nil, err
+	}
 	return chapter, nil
 }
 
+// SetStatus menerbitkan atau menarik satu chapter. Chapter tetap tersembunyi selama
+// story-nya belum Published.
+func (u *ChapterUC) SetStatus(ctx context.Context, uuid, status string) (*domain.Chapter, error) {
+	if status != domain.StatusDraft && status != domain.StatusPublished {
+		return nil, domain.ErrBadParamInput
+	}
+
+	chapter, err := u.AdminGetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if chapter.Status == status {
+		return chapter, nil
+	}
+
+	before := snapshotChapter(chapter)
+	chapter.Status = status
+	if status == domain.StatusPublished {
+		now := time.Now()
+		chapter.PublishedAt = &now
+	}
+
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.Update(ctx, chapter); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, chapter.StoryID, domain.RevisionEntityChapter, chapter.UUID, domain.RevisionActionUpdate, before, snapshotChapter(chapter))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chapter, nil
+}
+
+// Reorder menyusun ulang semua chapter story, chapterUUIDs harus berisi tepat semua chapter story.
+func (u *ChapterUC) Reorder(ctx context.Context, storyUUID string, chapterUUIDs []string) ([]domain.Chapter, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	chapters, err := u.repo.GetAllByStoryID(ctx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]*domain.Chapter, len(chapters))
+	for i := range chapters {
+		byUUID[chapters[i].UUID] = &chapters[i]
+	}
+	ordered := make([]domain.Chapter, 0, len(chapterUUIDs))
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.ReorderChapters(ctx, story.ID, chapterUUIDs); err != nil {
+			return err
+		}
+		for i, id := range chapterUUIDs {
+			chapter, ok := byUUID[id]
+			if !ok {
+				return domain.ErrBadParamInput
+			}
+
+			before := snapshotChapter(chapter)
+			chapter.Position = i + 1
+			if err := recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntityChapter, chapter.UUID, domain.RevisionActionUpdate, before, snapshotChapter(chapter)); err != nil {
+				return err
+			}
+			ordered = append(ordered, *chapter)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// GetByUUID dipakai endpoint publik: chapter dan story-nya harus sama-sama Published.
 func (u *ChapterUC) GetByUUID(ctx context.Context, uuid string) (*domain.Chapter, error) {
+	chapter, err := u.AdminGetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if chapter.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	// chapter dari story yang belum terbit tidak boleh terlihat publik
+	story, err := u.storyRepo.GetByID(ctx, chapter.StoryID)
+	if err != nil {
+		return nil, err
+	}
+	if story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	return chapter, nil
+}
+
+func (u *ChapterUC) AdminGetByUUID(ctx context.Context, uuid string) (*domain.Chapter, error) {
 	chapter, err := u.repo.GetByUUID(ctx, uuid)
 	if err != nil {
 		return nil, err
@@ -54,21 +219,40 @@ func (u *ChapterUC) GetByUUID(ctx context.Context, uuid string) (*domain.Chapter
 	return chapter, nil
 }
 
+// ListByStory dipakai endpoint publik, handler membatasi q ke chapter Published.
+func (u *ChapterUC) ListByStory(ctx context.Context, storyUUID string, q domain.ListQuery) ([]domain.Chapter, *domain.PageInfo, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, nil, domain.ErrNotFound
+	}
+	return u.repo.ListByStoryID(ctx, story.ID, q)
+}
+
+func (u *ChapterUC) AdminListByStory(ctx context.Context, storyUUID string, q domain.ListQuery) ([]domain.Chapter, *domain.PageInfo, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if story == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	return u.repo.ListByStoryID(ctx, story.ID, q)
+}
+
 func (u *ChapterUC) Delete(ctx context.Context, uuid string) error {
 	chapter, err := u.repo.GetByUUID(ctx, uuid)
 	if err != nil {
 		return err
 	}
-
-	for _, slide := range chapter.Slides {
-		if slide.ImageURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterImages, slide.ImageURL)
-		}
-		if slide.SoundURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterSounds, slide.SoundURL)
-		}
+	if chapter == nil {
+		return domain.ErrNotFound
 	}
 
+	// media hasil impor paket dinamai checksum dan bisa dipakai bersama konten lain,
+	// jadi cover dan media slide dibersihkan lewat perintah blobs gc
 	return u.repo.Delete(ctx, uuid)
 }
 
@@ -77,67 +261,145 @@ func (u *ChapterUC) AddSlide(ctx context.Context, chapterUUID string, content st
 	if err != nil {
 		return nil, err
 	}
+	if chapter == nil {
+		return nil, domain.ErrNotFound
+	}
 
 	count, _ := u.repo.CountSlides(ctx, chapter.ID)
-	if count >= 20 {
-		return nil, errors.New("maximum 20 slides per chapter reached")
+	if count >= int64(u.cfg.ChapterSlideLimit) {
+		return nil, domain.ErrSlideLimitReached
 	}
 
-	var imageURL string
-	if imageFile != nil {
-		folderPath := ""
-		url, _, err := utils.UploadAndAnalyzeImage(ctx, u.uploader, imageFile, imageHeader, u.cfg.AzureContainerChapterImages, folderPath, uuid.New().String())
-		if err != nil {
-			return nil, err
-		}
-		imageURL = url
+	image, sound, err := u.stageMedia(ctx, imageFile, imageHeader, soundFile, soundHeader)
+	if err != nil {
+		return nil, err
 	}
 
-	var soundURL string
-	if soundFile != nil {
-		convertedFile, tempPath, err := utils.ConvertToAAC(soundFile, soundHeader.Filename)
-		if err != nil {
-			if imageURL != "" {
-				u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterImages, imageURL)
-			}
-			return nil, errors.New("failed to convert audio: " + err.Error())
-		}
+	slide := &domain.Slide{
+		ChapterID: &chapter.ID,
+		Content:   content,
+		Sequence:  sequence,
+		Status:    slideStatus(image, sound),
+	}
 
-		defer func() {
-			convertedFile.Close()
-			os.Remove(tempPath)
-		}()
+	if err := u.repo.CreateSlide(ctx, slide, u.cfg.ChapterSlideLimit); err != nil {
+		discardStaged(ctx, u.uploader, image, sound)
+		return nil, err
+	}
+	if err := queueSlideMedia(ctx, u.jobRepo, u.storyRepo, u.cfg, slide, chapter.StoryID, image, sound); err != nil {
+		u.repo.DeleteSlide(ctx, slide)
+		discardStaged(ctx, u.uploader, image, sound)
+		return nil, err
+	}
 
-		newFilename := uuid.New().String() + ".m4a"
-		folderPath := ""
+	return slide, nil
+}
 
-		url, err := u.uploader.UploadToContainer(ctx, convertedFile, u.cfg.AzureContainerChapterSounds, folderPath+newFilename)
-		if err != nil {
-			if imageURL != "" {
-				u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterImages, imageURL)
-			}
-			return nil, err
-		}
-		soundURL = url
+// UpdateSlide mengubah teks, gambar dan/atau suara slide chapter. File lama tetap disimpan
+// untuk rollback revisi, pembersihannya lewat perintah blobs gc.
+func (u *ChapterUC) UpdateSlide(ctx context.Context, chapterUUID string, slideID uint, content *string, imageFile multipart.File, imageHeader *multipart.FileHeader, soundFile multipart.File, soundHeader *multipart.FileHeader) (*domain.Slide, error) {
+	chapter, err := u.repo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil {
+		return nil, domain.ErrNotFound
+	}
+	slide := findSlide(chapter.Slides, slideID)
+	if slide == nil {
+		return nil, domain.ErrNotFound
 	}
 
-	slide := &domain.Slide{
-		ChapterID: &chapter.ID,
-		Content:   content,
-		Sequence:  sequence,
-		ImageURL:  imageURL,
-		SoundURL:  soundURL,
+	before := snapshotSlide(slide)
+	if content != nil {
+		slide.Content = *content
+	}
+
+	image, sound, err := u.stageMedia(ctx, imageFile, imageHeader, soundFile, soundHeader)
+	if err != nil {
+		return nil, err
+	}
+	if image != nil || sound != nil {
+		slide.Status = domain.SlideStatusProcessing
 	}
 
-	if err := u.repo.CreateSlide(ctx, slide); err != nil {
-		if imageURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterImages, imageURL)
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.UpdateSlide(ctx, slide); err != nil {
+			return err
 		}
-		if soundURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterSounds, soundURL)
+		if err := queueSlideMedia(ctx, u.jobRepo, u.storyRepo, u.cfg, slide, chapter.StoryID, image, sound); err != nil {
+			return err
 		}
+		return recordRevision(ctx, u.revisionRepo, chapter.StoryID, domain.RevisionEntitySlide, slideRevisionKey(slide.ID), domain.RevisionActionUpdate, before, snapshotSlide(slide))
+	})
+	if err != nil {
+		discardStaged(ctx, u.uploader, image, sound)
 		return nil, err
 	}
-
 	return slide, nil
+}
+
+func (u *ChapterUC) DeleteSlide(ctx context.Context, chapterUUID string, slideID uint) error {
+	chapter, err := u.repo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return domain.ErrNotFound
+	}
+	slide := findSlide(chapter.Slides, slideID)
+	if slide == nil {
+		return domain.ErrNotFound
+	}
+
+	// media slide dibersihkan lewat blobs gc, lihat Delete
+	return u.repo.DeleteSlide(ctx, slide)
+}
+
+// ReorderSlides menyusun ulang semua slide chapter, slideIDs harus berisi tepat semua slide chapter.
+func (u *ChapterUC) ReorderSlides(ctx context.Context, chapterUUID string, slideIDs []uint) ([]domain.Slide, error) {
+	chapter, err := u.repo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	var slides []domain.Slide
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.ReorderSlides(ctx, chapter.ID, slideIDs); err != nil {
+			return err
+		}
+		slides, err = applySlideOrder(ctx, u.revisionRepo, chapter.StoryID, chapter.Slides, slideIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slides, nil
+}
+
+// stageMedia mengunggah gambar dan suara slide apa adanya ke staging, konversi audio ke
+// AAC dilakukan worker. Jika salah satu gagal, file yang sudah terunggah dihapus lagi.
+func (u *ChapterUC) stageMedia(ctx context.Context, imageFile multipart.File, imageHeader *multipart.FileHeader, soundFile multipart.File, soundHeader *multipart.FileHeader) (*domain.StagedMedia, *domain.StagedMedia, error) {
+	image, err := stageMedia(ctx, u.uploader, imageFile, imageHeader, u.cfg.AzureContainerChapterImages, "", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	sound, err := stageMedia(ctx, u.uploader, soundFile, soundHeader, u.cfg.AzureContainerChapterSounds, "", ".m4a")
+	if err != nil {
+		discardStaged(ctx, u.uploader, image)
+		return nil, nil, err
+	}
+	return image, sound, nil
+}
+
+func (u *ChapterUC) deleteMedia(ctx context.Context, imageURL, soundURL string) {
+	if imageURL != "" {
+		u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterImages, imageURL)
+	}
+	if soundURL != "" {
+		u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerChapterSounds, soundURL)
+	}
 }
\ No newline at end of file
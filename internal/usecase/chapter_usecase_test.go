@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestChapterUseCase_GetByUUID(t *testing.T) {
+	ctx := context.TODO()
+
+	tests := []struct {
+		name        string
+		chapter     string
+		story       string
+		expectedErr error
+	}{
+		{"published chapter of published story", domain.StatusPublished, domain.StatusPublished, nil},
+		{"draft chapter", domain.StatusDraft, domain.StatusPublished, domain.ErrNotFound},
+		{"published chapter of draft story", domain.StatusPublished, domain.StatusDraft, domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.ChapterRepositoryMock)
+			mockStoryRepo := new(mocks.StoryRepositoryMock)
+			uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, mockStoryRepo, nil, nil, nil, nil)
+
+			mockRepo.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 5, UUID: "c-1", StoryID: 1, Status: tt.chapter}, nil)
+			mockStoryRepo.On("GetByID", ctx, uint(1)).Return(&domain.Story{ID: 1, Status: tt.story}, nil)
+
+			res, err := uc.GetByUUID(ctx, "c-1")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, res)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "c-1", res.UUID)
+		})
+	}
+}
+
+func TestChapterUseCase_SetStatus(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("publish sets published_at and records revision", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, nil, nil, mockRevisions, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 5, UUID: "c-1", StoryID: 1, Status: domain.StatusDraft}, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Chapter")).Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		res, err := uc.SetStatus(ctx, "c-1", domain.StatusPublished)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPublished, res.Status)
+		assert.NotNil(t, res.PublishedAt)
+
+		rev := mockRevisions.Calls[0].Arguments.Get(1).(*domain.Revision)
+		assert.Equal(t, domain.RevisionEntityChapter, rev.EntityType)
+		assert.Equal(t, domain.FieldChange{Old: domain.StatusDraft, New: domain.StatusPublished}, rev.Changes["status"])
+	})
+
+	t.Run("revision failure rolls back the update", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		tx := new(mocks.TransactorMock)
+		uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, nil, nil, mockRevisions, nil, tx)
+
+		tx.On("WithinTransaction", ctx)
+		mockRepo.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 5, UUID: "c-1", StoryID: 1, Status: domain.StatusDraft}, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Chapter")).
+			Run(func(mock.Arguments) { assert.True(t, tx.InTx) }).Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).
+			Run(func(mock.Arguments) { assert.True(t, tx.InTx) }).Return(errors.New("disk full"))
+
+		res, err := uc.SetStatus(ctx, "c-1", domain.StatusPublished)
+
+		assert.EqualError(t, err, "disk full")
+		assert.Nil(t, res)
+		tx.AssertNumberOfCalls(t, "WithinTransaction", 1)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil)
+
+		res, err := uc.SetStatus(ctx, "c-1", domain.StatusArchived)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "GetByUUID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestChapterUseCase_Reorder(t *testing.T) {
+	ctx := context.TODO()
+	mockRepo := new(mocks.ChapterRepositoryMock)
+	mockStoryRepo := new(mocks.StoryRepositoryMock)
+	uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, mockStoryRepo, nil, nil, nil, nil)
+
+	order := []string{"c-2", "c-1"}
+	mockStoryRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1"}, nil)
+	mockRepo.On("GetAllByStoryID", ctx, uint(1)).Return([]domain.Chapter{
+		{ID: 5, UUID: "c-1", StoryID: 1, Position: 1},
+		{ID: 6, UUID: "c-2", StoryID: 1, Position: 2},
+	}, nil)
+	mockRepo.On("ReorderChapters", ctx, uint(1), order).Return(nil)
+
+	res, err := uc.Reorder(ctx, "s-1", order)
+
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "c-2", res[0].UUID)
+	assert.Equal(t, 1, res[0].Position)
+	assert.Equal(t, "c-1", res[1].UUID)
+	assert.Equal(t, 2, res[1].Position)
+}
+
+func TestChapterUseCase_Delete(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("keeps media when the row cannot be deleted", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepositoryMock)
+		mockStorage := new(mocks.StorageRepositoryMock)
+		uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, nil, mockStorage, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 5, UUID: "c-1", CoverURL: "/uploads/chapters/cover.png"}, nil)
+		mockRepo.On("Delete", ctx, "c-1").Return(errors.New("db down"))
+
+		err := uc.Delete(ctx, "c-1")
+
+		assert.Error(t, err)
+		mockStorage.AssertNotCalled(t, "DeleteFromContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("leaves shared media to blobs gc", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepositoryMock)
+		mockStorage := new(mocks.StorageRepositoryMock)
+		uc := usecase.NewChapterUseCase(&config.Config{}, mockRepo, nil, mockStorage, nil, nil, nil)
+
+		chapter := &domain.Chapter{
+			ID:       5,
+			UUID:     "c-1",
+			CoverURL: "/uploads/chapters/cover.png",
+			Slides:   []domain.Slide{{ID: 9, ImageURL: "/uploads/chapters/a.png", SoundURL: "/uploads/sounds/a.m4a"}},
+		}
+		mockRepo.On("GetByUUID", ctx, "c-1").Return(chapter, nil)
+		mockRepo.On("Delete", ctx, "c-1").Return(nil)
+		mockRepo.On("DeleteSlide", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+
+		assert.NoError(t, uc.DeleteSlide(ctx, "c-1", 9))
+		assert.NoError(t, uc.Delete(ctx, "c-1"))
+		mockRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "DeleteFromContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
\ No newline at end of file
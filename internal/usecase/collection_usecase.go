@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type CollectionUC struct {
+	repo      domain.CollectionRepository
+	redisRepo domain.RedisRepository
+	uploader  domain.StorageRepository
+	jobRepo   domain.JobRepository
+	tx        domain.Transactor
+	cfg       *config.Config
+}
+
+func NewCollectionUseCase(cfg *config.Config, repo domain.CollectionRepository, redis domain.RedisRepository, uploader domain.StorageRepository, jobRepo domain.JobRepository, tx domain.Transactor) *CollectionUC {
+	return &CollectionUC{
+		repo:      repo,
+		redisRepo: redis,
+		uploader:  uploader,
+		jobRepo:   jobRepo,
+		tx:        tx,
+		cfg:       cfg,
+	}
+}
+
+func validCollectionType(t string) bool {
+	return t == domain.CollectionTypeSeries || t == domain.CollectionTypeCollection
+}
+
+func (uc *CollectionUC) Create(ctx context.Context, title, description, collectionType string, file multipart.File, header *multipart.FileHeader) (*domain.Collection, error) {
+	title = strings.TrimSpace(title)
+	if collectionType == "" {
+		collectionType = domain.CollectionTypeSeries
+	}
+	if title == "" || !validCollectionType(collectionType) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	collection := &domain.Collection{
+		UUID:        uuid.New().String(),
+		Title:       title,
+		Description: description,
+		Type:        collectionType,
+	}
+
+	// warna dominan diambil worker, collection tetap bisa dipakai sebelum warnanya ada
+	imageURL, err := utils.UploadFile(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "collections/", collection.UUID)
+	if err != nil {
+		return nil, err
+	}
+	collection.ImageURL = imageURL
+
+	err = inTransaction(ctx, uc.tx, func(ctx context.Context) error {
+		if err := uc.repo.Create(ctx, collection); err != nil {
+			return err
+		}
+		return uc.queuePalette(ctx, collection)
+	})
+	if err != nil {
+		if imageURL != "" {
+			uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, imageURL)
+		}
+		return nil, err
+	}
+
+	uc.invalidate(ctx)
+	return collection, nil
+}
+
+func (uc *CollectionUC) Update(ctx context.Context, id, title, description, collectionType string, file multipart.File, header *multipart.FileHeader) (*domain.Collection, error) {
+	if collectionType != "" && !validCollectionType(collectionType) {
+		return nil, domain.ErrBadParamInput
+	}
+
+	collection, err := uc.repo.GetByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if title = strings.TrimSpace(title); title != "" {
+		collection.Title = title
+	}
+	if description != "" {
+		collection.Description = description
+	}
+	if collectionType != "" {
+		collection.Type = collectionType
+	}
+
+	oldImageURL := collection.ImageURL
+	newImageURL, err := utils.UploadFile(ctx, uc.uploader, file, header, uc.cfg.AzureContainer, "collections/", uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+	if newImageURL != "" {
+		collection.ImageURL = newImageURL
+	}
+
+	// warna dominan lama dipakai sampai worker selesai mengambil warna gambar baru
+	err = inTransaction(ctx, uc.tx, func(ctx context.Context) error {
+		if err := uc.repo.Update(ctx, collection); err != nil {
+			return err
+		}
+		if newImageURL == "" {
+			return nil
+		}
+		return uc.queuePalette(ctx, collection)
+	})
+	if err != nil {
+		if newImageURL != "" {
+			uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, newImageURL)
+		}
+		return nil, err
+	}
+
+	if newImageURL != "" && oldImageURL != "" {
+		uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, oldImageURL)
+	}
+
+	uc.invalidate(ctx)
+	return collection, nil
+}
+
+func (uc *CollectionUC) Delete(ctx context.Context, id string) error {
+	collection, err := uc.repo.GetByUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if collection == nil {
+		return domain.ErrNotFound
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if collection.ImageURL != "" && uc.uploader != nil {
+		_ = uc.uploader.DeleteFromContainer(ctx, uc.cfg.AzureContainer, collection.ImageURL)
+	}
+
+	uc.invalidate(ctx)
+	return nil
+}
+
+func (uc *CollectionUC) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Collection, *domain.PageInfo, error) {
+	cacheKey := listCacheKey(domain.CacheKeyCollectionAll+":", q)
+
+	if uc.redisRepo != nil {
+		cachedData, err := uc.redisRepo.Get(ctx, cacheKey)
+		if err == nil && cachedData != "" {
+			var page listPage[domain.Collection]
+			if err := json.Unmarshal([]byte(cachedData), &page); err == nil && page.Page != nil {
+				return page.Items, page.Page, nil
+			}
+		}
+	}
+
+	collections, info, err := uc.repo.GetAll(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uc.redisRepo != nil {
+		if data, err := json.Marshal(listPage[domain.Collection]{Items: collections, Page: info}); err == nil {
+			_ = uc.redisRepo.Set(ctx, cacheKey, data, 30*time.Minute)
+		}
+	}
+
+	return collections, info, nil
+}
+
+// Get dipakai endpoint publik, hanya story Published yang ikut ditampilkan.
+func (uc *CollectionUC) Get(ctx context.Context, id string) (*domain.Collection, error) {
+	return uc.get(ctx, id, true)
+}
+
+func (uc *CollectionUC) AdminGet(ctx context.Context, id string) (*domain.Collection, error) {
+	return uc.get(ctx, id, false)
+}
+
+func (uc *CollectionUC) get(ctx context.Context, id string, publishedOnly bool) (*domain.Collection, error) {
+	collection, err := uc.repo.GetByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	stories, err := uc.repo.ListStories(ctx, collection.ID, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+	collection.Stories = stories
+	return collection, nil
+}
+
+// SetStories mengganti isi dan urutan story collection. Story boleh berada di banyak
+// collection, tetapi tidak boleh muncul dua kali dalam satu collection.
+func (uc *CollectionUC) SetStories(ctx context.Context, id string, storyUUIDs []string) (*domain.Collection, error) {
+	seen := make(map[string]bool, len(storyUUIDs))
+	for _, storyUUID := range storyUUIDs {
+		if _, err := uuid.Parse(storyUUID); err != nil || seen[storyUUID] {
+			return nil, domain.ErrBadParamInput
+		}
+		seen[storyUUID] = true
+	}
+
+	collection, err := uc.repo.GetByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := uc.repo.SetStories(ctx, collection.ID, storyUUIDs); err != nil {
+		return nil, err
+	}
+	return uc.AdminGet(ctx, id)
+}
+
+// queuePalette mengantrekan pengambilan warna dominan, collection tanpa gambar dilewati.
+func (uc *CollectionUC) queuePalette(ctx context.Context, collection *domain.Collection) error {
+	if collection.ImageURL == "" {
+		return nil
+	}
+	return queueImagePalette(ctx, uc.jobRepo, uc.cfg, domain.PaletteEntityCollection, collection.UUID, collection.ImageURL)
+}
+
+func (uc *CollectionUC) invalidate(ctx context.Context) {
+	if uc.redisRepo != nil {
+		_ = uc.redisRepo.DeletePrefix(ctx, domain.CacheKeyCollectionAll)
+	}
+}
\ No newline at end of file
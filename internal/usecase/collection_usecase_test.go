@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
+
+)
+
+func TestCollectionUseCase_Create(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("defaults to series and invalidates cache", func(t *testing.T) {
+		mockRepo := new(mocks.CollectionRepositoryMock)
+		mockRedis := new(mocks.RedisRepositoryMock)
+		uc := usecase.NewCollectionUseCase(&config.Config{}, mockRepo, mockRedis, nil, nil, nil)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Collection")).Return(nil)
+		mockRedis.On("DeletePrefix", ctx, domain.CacheKeyCollectionAll).Return(nil)
+
+		res, err := uc.Create(ctx, "25 Nabi", "Kisah para nabi", "", nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CollectionTypeSeries, res.Type)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("image color is left to the palette job", func(t *testing.T) {
+		mockRepo := new(mocks.CollectionRepositoryMock)
+		mockJobs := new(mocks.JobRepositoryMock)
+		storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+		require.NoError(t, err)
+		uc := usecase.NewCollectionUseCase(&config.Config{AzureContainer: "media"}, mockRepo, nil, storage, mockJobs, nil)
+
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Collection")).Return(nil)
+		mockJobs.On("Enqueue", ctx, mock.AnythingOfType("*domain.Job")).Return(nil)
+
+		res, err := uc.Create(ctx, "25 Nabi", "", "", testFile("image-bytes"), &multipart.FileHeader{Filename: "cover.png"})
+
+		require.NoError(t, err)
+		assert.Empty(t, res.DominantColor)
+		job := mockJobs.Calls[0].Arguments.Get(1).(*domain.Job)
+		assert.Equal(t, domain.JobTypeImagePalette, job.Type)
+		var payload domain.ImagePaletteJob
+		require.NoError(t, json.Unmarshal(job.Payload, &payload))
+		assert.Equal(t, domain.ImagePaletteJob{Entity: domain.PaletteEntityCollection, UUID: res.UUID, ImageURL: res.ImageURL}, payload)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		mockRepo := new(mocks.CollectionRepositoryMock)
+		uc := usecase.NewCollectionUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+		res, err := uc.Create(ctx, "25 Nabi", "", "playlist", nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCollectionUseCase_SetStories(t *testing.T) {
+	ctx := context.TODO()
+	storyA := "6f1c7a52-3a8e-4c39-9d7e-0b7f2f0d1a11"
+	storyB := "0d6a2f44-9b1e-4d3c-8a0f-5e2c7b9d4e22"
+
+	t.Run("replaces stories in order", func(t *testing.T) {
+		mockRepo := new(mocks.CollectionRepositoryMock)
+		uc := usecase.NewCollectionUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+		order := []string{storyB, storyA}
+		mockRepo.On("GetByUUID", ctx, "col-1").Return(&domain.Collection{ID: 3, UUID: "col-1"}, nil)
+		mockRepo.On("SetStories", ctx, uint(3), order).Return(nil)
+		mockRepo.On("ListStories", ctx, uint(3), false).Return([]domain.Story{{UUID: storyB}, {UUID: storyA}}, nil)
+
+		res, err := uc.SetStories(ctx, "col-1", order)
+
+		assert.NoError(t, err)
+		assert.Len(t, res.Stories, 2)
+		assert.Equal(t, storyB, res.Stories[0].UUID)
+	})
+
+	t.Run("duplicate story", func(t *testing.T) {
+		mockRepo := new(mocks.CollectionRepositoryMock)
+		uc := usecase.NewCollectionUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil)
+
+		res, err := uc.SetStories(ctx, "col-1", []string{storyA, storyA})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "SetStories", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoryUseCase_GetPublishedWithSeries(t *testing.T) {
+	ctx := context.TODO()
+	mockRepo := new(mocks.StoryRepositoryMock)
+	mockCollections := new(mocks.CollectionRepositoryMock)
+	uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, mockCollections, nil, nil)
+
+	series := []domain.SeriesNavigation{{
+		CollectionUUID: "col-1", Title: "25 Nabi", Position: 2, Total: 25,
+		Previous: &domain.StoryRef{UUID: "adam", Title: "Nabi Adam"},
+		Next:     &domain.StoryRef{UUID: "nuh", Title: "Nabi Nuh"},
+	}}
+	mockRepo.On("GetByUUID", ctx, "idris").Return(&domain.Story{ID: 2, UUID: "idris", Status: domain.StatusPublished}, nil)
+	mockCollections.On("SeriesForStory", ctx, uint(2)).Return(series, nil)
+
+	res, err := uc.GetPublished(ctx, "idris")
+
+	assert.NoError(t, err)
+	assert.Equal(t, series, res.Series)
+}
\ No newline at end of file
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/domain"
+
+)
+
+const maxFolderNameLength = 50
+
+type FavouriteUC struct {
+	repo        domain.FavouriteRepository
+	storyRepo   domain.StoryRepository
+	chapterRepo domain.ChapterRepository
+}
+
+func NewFavouriteUseCase(repo domain.FavouriteRepository, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository) *FavouriteUC {
+	return &FavouriteUC{repo: repo, storyRepo: storyRepo, chapterRepo: chapterRepo}
+}
+
+// AddStory memfavoritkan story yang sudah terbit. Memanggil ulang dengan folder lain
+// memindahkan favorit ke folder tersebut, folderUUID kosong mengeluarkannya dari folder.
+func (u *FavouriteUC) AddStory(ctx context.Context, userID, storyUUID, folderUUID string) (*domain.Favourite, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	fav := &domain.Favourite{UserID: userID, StoryID: story.ID}
+	if err := u.save(ctx, fav, folderUUID); err != nil {
+		return nil, err
+	}
+	fav.Story = story
+	return fav, nil
+}
+
+// AddChapter memfavoritkan chapter yang terbit dari story yang juga terbit.
+func (u *FavouriteUC) AddChapter(ctx context.Context, userID, chapterUUID, folderUUID string) (*domain.Favourite, error) {
+	chapter, err := u.chapterRepo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil || chapter.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	story, err := u.storyRepo.GetByID(ctx, chapter.StoryID)
+	if err != nil {
+		return nil, err
+	}
+	if story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	fav := &domain.Favourite{UserID: userID, StoryID: story.ID, ChapterID: &chapter.ID}
+	if err := u.save(ctx, fav, folderUUID); err != nil {
+		return nil, err
+	}
+	fav.Story = story
+	fav.Chapter = chapter
+	return fav, nil
+}
+
+func (u *FavouriteUC) save(ctx context.Context, fav *domain.Favourite, folderUUID string) error {
+	if folderUUID != "" {
+		folder, err := u.getFolder(ctx, fav.UserID, folderUUID)
+		if err != nil {
+			return err
+		}
+		fav.FolderID = &folder.ID
+		fav.Folder = folder
+	}
+	fav.UUID = uuid.New().String()
+	return u.repo.Save(ctx, fav)
+}
+
+// RemoveStory tidak mengembalikan error jika story memang belum difavoritkan, sehingga
+// client bisa memanggilnya berulang tanpa memeriksa status lebih dulu.
+func (u *FavouriteUC) RemoveStory(ctx context.Context, userID, storyUUID string) error {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return err
+	}
+	if story == nil {
+		return domain.ErrNotFound
+	}
+	return u.repo.Remove(ctx, userID, story.ID, nil)
+}
+
+func (u *FavouriteUC) RemoveChapter(ctx context.Context, userID, chapterUUID string) error {
+	chapter, err := u.chapterRepo.GetByUUID(ctx, chapterUUID)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return domain.ErrNotFound
+	}
+	return u.repo.Remove(ctx, userID, chapter.StoryID, &chapter.ID)
+}
+
+func (u *FavouriteUC) List(ctx context.Context, userID string, q domain.ListQuery) ([]domain.Favourite, *domain.PageInfo, error) {
+	if folderUUID, ok := q.Filters[domain.FilterFolder]; ok {
+		if _, err := u.getFolder(ctx, userID, folderUUID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return u.repo.List(ctx, userID, q)
+}
+
+// MarkFavourited mengisi flag Favourited pada story milik listing atau detail. Dipanggil
+// setelah data diambil dari cache, karena cache dipakai bersama oleh semua user.
+func (u *FavouriteUC) MarkFavourited(ctx context.Context, userID string, stories []domain.Story) error {
+	if userID == "" || len(stories) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(stories))
+	for i, s := range stories {
+		ids[i] = s.ID
+	}
+	favourited, err := u.repo.FavouritedStoryIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range stories {
+		flag := favourited[stories[i].ID]
+		stories[i].Favourited = &flag
+	}
+	return nil
+}
+
+func normalizeFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderNameLength {
+		return "", domain.ErrBadParamInput
+	}
+	return name, nil
+}
+
+func (u *FavouriteUC) getFolder(ctx context.Context, userID, folderUUID string) (*domain.FavouriteFolder, error) {
+	if _, err := uuid.Parse(folderUUID); err != nil {
+		return nil, domain.ErrNotFound
+	}
+	folder, err := u.repo.GetFolder(ctx, userID, folderUUID)
+	if err != nil {
+		return nil, err
+	}
+	if folder == nil {
+		return nil, domain.ErrNotFound
+	}
+	return folder, nil
+}
+
+func (u *FavouriteUC) CreateFolder(ctx context.Context, userID, name string) (*domain.FavouriteFolder, error) {
+	name, err := normalizeFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &domain.FavouriteFolder{UUID: uuid.New().String(), UserID: userID, Name: name}
+	if err := u.repo.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (u *FavouriteUC) RenameFolder(ctx context.Context, userID, folderUUID, name string) (*domain.FavouriteFolder, error) {
+	name, err := normalizeFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := u.getFolder(ctx, userID, folderUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Name = name
+	if err := u.repo.UpdateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (u *FavouriteUC) DeleteFolder(ctx context.Context, userID, folderUUID string) error {
+	folder, err := u.getFolder(ctx, userID, folderUUID)
+	if err != nil {
+		return err
+	}
+	return u.repo.DeleteFolder(ctx, folder.ID)
+}
+
+func (u *FavouriteUC) ListFolders(ctx context.Context, userID string) ([]domain.FavouriteFolder, error) {
+	return u.repo.ListFolders(ctx, userID)
+}
+
+func (u *FavouriteUC) Popular(ctx context.Context, since time.Time, limit int) ([]domain.StoryPopularity, error) {
+	return u.repo.MostFavourited(ctx, since, limit)
+}
\ No newline at end of file
@@ -0,0 +1,111 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestFavouriteUseCase_AddStory(t *testing.T) {
+	ctx := context.TODO()
+	folderUUID := "5b0c7e1a-2f4d-4a8b-9c3e-6d1f0a2b3c44"
+
+	t.Run("unpublished story", func(t *testing.T) {
+		mockRepo := new(mocks.FavouriteRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewFavouriteUseCase(mockRepo, mockStories, nil)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusDraft}, nil)
+
+		_, err := uc.AddStory(ctx, "user-1", "s-1", "")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("folder of another user", func(t *testing.T) {
+		mockRepo := new(mocks.FavouriteRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewFavouriteUseCase(mockRepo, mockStories, nil)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusPublished}, nil)
+		mockRepo.On("GetFolder", ctx, "user-1", folderUUID).Return(nil, nil)
+
+		_, err := uc.AddStory(ctx, "user-1", "s-1", folderUUID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("into folder", func(t *testing.T) {
+		mockRepo := new(mocks.FavouriteRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewFavouriteUseCase(mockRepo, mockStories, nil)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusPublished}, nil)
+		mockRepo.On("GetFolder", ctx, "user-1", folderUUID).Return(&domain.FavouriteFolder{ID: 4, UUID: folderUUID, Name: "Sebelum tidur"}, nil)
+		mockRepo.On("Save", ctx, mock.AnythingOfType("*domain.Favourite")).Return(nil)
+
+		fav, err := uc.AddStory(ctx, "user-1", "s-1", folderUUID)
+
+		assert.NoError(t, err)
+		saved := mockRepo.Calls[1].Arguments.Get(1).(*domain.Favourite)
+		assert.Equal(t, uint(1), saved.StoryID)
+		assert.Nil(t, saved.ChapterID)
+		assert.Equal(t, uint(4), *saved.FolderID)
+		assert.Equal(t, "s-1", fav.Story.UUID)
+	})
+}
+
+func TestFavouriteUseCase_MarkFavourited(t *testing.T) {
+	ctx := context.TODO()
+	mockRepo := new(mocks.FavouriteRepositoryMock)
+	uc := usecase.NewFavouriteUseCase(mockRepo, nil, nil)
+
+	stories := []domain.Story{{ID: 1}, {ID: 2}}
+	mockRepo.On("FavouritedStoryIDs", ctx, "user-1", []uint{1, 2}).Return(map[uint]bool{2: true}, nil)
+
+	err := uc.MarkFavourited(ctx, "user-1", stories)
+
+	assert.NoError(t, err)
+	assert.False(t, *stories[0].Favourited)
+	assert.True(t, *stories[1].Favourited)
+
+	anonymous := []domain.Story{{ID: 1}}
+	assert.NoError(t, uc.MarkFavourited(ctx, "", anonymous))
+	assert.Nil(t, anonymous[0].Favourited)
+}
+
+func TestFavouriteUseCase_CreateFolder(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("blank name", func(t *testing.T) {
+		mockRepo := new(mocks.FavouriteRepositoryMock)
+		uc := usecase.NewFavouriteUseCase(mockRepo, nil, nil)
+
+		_, err := uc.CreateFolder(ctx, "user-1", "   ")
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockRepo.AssertNotCalled(t, "CreateFolder", mock.Anything, mock.Anything)
+	})
+
+	t.Run("trims name", func(t *testing.T) {
+		mockRepo := new(mocks.FavouriteRepositoryMock)
+		uc := usecase.NewFavouriteUseCase(mockRepo, nil, nil)
+
+		mockRepo.On("CreateFolder", ctx, mock.AnythingOfType("*domain.FavouriteFolder")).Return(nil)
+
+		folder, err := uc.CreateFolder(ctx, "user-1", "  Sebelum tidur ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Sebelum tidur", folder.Name)
+		assert.Equal(t, "user-1", folder.UserID)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"math"
+
+	"khalif-stories/internal/domain"
+
+)
+
+type HistoryUC struct {
+	repo        domain.HistoryRepository
+	storyRepo   domain.StoryRepository
+	chapterRepo domain.ChapterRepository
+}
+
+func NewHistoryUseCase(repo domain.HistoryRepository, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository) *HistoryUC {
+	return &HistoryUC{repo: repo, storyRepo: storyRepo, chapterRepo: chapterRepo}
+}
+
+func (u *HistoryUC) RecordProgress(ctx context.Context, userID, storyUUID, chapterUUID string, slideSequence, position, duration int) (*domain.ListeningHistory, error) {
+	if slideSequence < 0 || position < 0 || duration < 0 {
+		return nil, domain.ErrBadParamInput
+	}
+
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil || story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	history := &domain.ListeningHistory{
+		UserID:        userID,
+		StoryID:       story.ID,
+		SlideSequence: slideSequence,
+		Position:      position,
+		Duration:      duration,
+	}
+
+	if chapterUUID != "" {
+		chapter, err := u.chapterRepo.GetByUUID(ctx, chapterUUID)
+		if err != nil || chapter == nil || chapter.Status != domain.StatusPublished {
+			return nil, domain.ErrNotFound
+		}
+		if chapter.StoryID != story.ID {
+			return nil, domain.ErrBadParamInput
+		}
+		history.ChapterID = &chapter.ID
+	}
+
+	if err := u.repo.Create(ctx, history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (u *HistoryUC) GetContinueListening(ctx context.Context, userID string, limit int) ([]domain.ListeningProgress, error) {
+	histories, err := u.repo.GetLatestPerStory(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	shelf := make([]domain.ListeningProgress, 0, len(histories))
+	for i := range histories {
+		h := &histories[i]
+		if h.Story == nil {
+			continue
+		}
+
+		progress, err := u.buildProgress(ctx, *h.Story, h)
+		if err != nil {
+			return nil, err
+		}
+		shelf = append(shelf, *progress)
+	}
+
+	return shelf, nil
+}
+
+func (u *HistoryUC) GetStoryProgress(ctx context.Context, userID, storyUUID string) (*domain.ListeningProgress, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	// story yang belum atau tidak lagi terbit tidak terlihat pendengar
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	history, err := u.repo.GetLatestForStory(ctx, userID, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.buildProgress(ctx, *story, history)
+}
+
+// buildProgress menghitung persentase dari jumlah slide: slide milik story dihitung
+// lebih dulu, lalu slide setiap chapter sesuai urutannya.
+func (u *HistoryUC) buildProgress(ctx context.Context, story domain.Story, h *domain.ListeningHistory) (*domain.ListeningProgress, error) {
+	all, err := u.chapterRepo.GetAllByStoryID(ctx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// chapter Draft tidak terlihat pendengar sehingga tidak ikut dihitung
+	chapters := make([]domain.Chapter, 0, len(all))
+	for _, c := range all {
+		if c.Status == domain.StatusPublished {
+			chapters = append(chapters, c)
+		}
+	}
+
+	storySlides := story.SlideCount
+	total := storySlides
+	for _, c := range chapters {
+		total += c.SlideCount
+	}
+
+	progress := &domain.ListeningProgress{
+		Story:       story,
+		TotalSlides: total,
+	}
+	if h == nil {
+		return progress, nil
+	}
+
+	progress.SlideSequence = h.SlideSequence
+	progress.Position = h.Position
+	progress.LastListenedAt = h.CreatedAt
+
+	listened := 0
+	if h.ChapterID == nil {
+		listened = min(h.SlideSequence, storySlides)
+	} else {
+		listened = storySlides
+		for _, c := range chapters {
+			if c.ID == *h.ChapterID {
+				progress.ChapterUUID = c.UUID
+				listened += min(h.SlideSequence, c.SlideCount)
+				break
+			}
+			listened += c.SlideCount
+		}
+	}
+	progress.SlidesListened = listened
+
+	if total > 0 {
+		progress.Percentage = math.Round(float64(listened)/float64(total)*10000) / 100
+	}
+
+	return progress, nil
+}
\ No newline at end of file
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestHistoryUseCase_GetStoryProgress(t *testing.T) {
+	ctx := context.TODO()
+
+	story := &domain.Story{ID: 1, UUID: "story-uuid", SlideCount: 2, Status: domain.StatusPublished}
+	chapters := []domain.Chapter{
+		{ID: 10, UUID: "chapter-1", StoryID: 1, SlideCount: 4, Status: domain.StatusPublished},
+		{ID: 12, UUID: "chapter-draft", StoryID: 1, SlideCount: 6, Status: domain.StatusDraft},
+		{ID: 11, UUID: "chapter-2", StoryID: 1, SlideCount: 4, Status: domain.StatusPublished},
+	}
+
+	t.Run("counts story slides and previous published chapters", func(t *testing.T) {
+		mockRepo := new(mocks.HistoryRepositoryMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockChapterRepo := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewHistoryUseCase(mockRepo, mockStoryRepo, mockChapterRepo)
+
+		chapterID := uint(11)
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(story, nil)
+		mockChapterRepo.On("GetAllByStoryID", ctx, uint(1)).Return(chapters, nil)
+		mockRepo.On("GetLatestForStory", ctx, "user-1", uint(1)).
+			Return(&domain.ListeningHistory{StoryID: 1, ChapterID: &chapterID, SlideSequence: 2, Position: 30}, nil)
+
+		res, err := uc.GetStoryProgress(ctx, "user-1", "story-uuid")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 10, res.TotalSlides)
+		assert.Equal(t, 8, res.SlidesListened)
+		assert.Equal(t, 80.0, res.Percentage)
+		assert.Equal(t, "chapter-2", res.ChapterUUID)
+		assert.Equal(t, 30, res.Position)
+	})
+
+	t.Run("never listened", func(t *testing.T) {
+		mockRepo := new(mocks.HistoryRepositoryMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockChapterRepo := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewHistoryUseCase(mockRepo, mockStoryRepo, mockChapterRepo)
+
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(story, nil)
+		mockChapterRepo.On("GetAllByStoryID", ctx, uint(1)).Return(chapters, nil)
+		mockRepo.On("GetLatestForStory", ctx, "user-1", uint(1)).Return(nil, nil)
+
+		res, err := uc.GetStoryProgress(ctx, "user-1", "story-uuid")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, res.Percentage)
+		assert.Equal(t, 10, res.TotalSlides)
+	})
+
+	t.Run("unpublished story is not found", func(t *testing.T) {
+		mockRepo := new(mocks.HistoryRepositoryMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewHistoryUseCase(mockRepo, mockStoryRepo, nil)
+
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(&domain.Story{ID: 1, UUID: "story-uuid", Status: domain.StatusDraft}, nil)
+
+		res, err := uc.GetStoryProgress(ctx, "user-1", "story-uuid")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "GetLatestForStory", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error is returned as is", func(t *testing.T) {
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewHistoryUseCase(nil, mockStoryRepo, nil)
+
+		dbErr := errors.New("db down")
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(nil, dbErr)
+
+		_, err := uc.GetStoryProgress(ctx, "user-1", "story-uuid")
+
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestHistoryUseCase_RecordProgress(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("chapter from another story", func(t *testing.T) {
+		mockRepo := new(mocks.HistoryRepositoryMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockChapterRepo := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewHistoryUseCase(mockRepo, mockStoryRepo, mockChapterRepo)
+
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(&domain.Story{ID: 1, Status: domain.StatusPublished}, nil)
+		mockChapterRepo.On("GetByUUID", ctx, "chapter-x").Return(&domain.Chapter{ID: 99, StoryID: 2, Status: domain.StatusPublished}, nil)
+
+		res, err := uc.RecordProgress(ctx, "user-1", "story-uuid", "chapter-x", 1, 0, 10)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("draft chapter", func(t *testing.T) {
+		mockRepo := new(mocks.HistoryRepositoryMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockChapterRepo := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewHistoryUseCase(mockRepo, mockStoryRepo, mockChapterRepo)
+
+		mockStoryRepo.On("GetByUUID", ctx, "story-uuid").Return(&domain.Story{ID: 1, Status: domain.StatusPublished}, nil)
+		mockChapterRepo.On("GetByUUID", ctx, "chapter-d").Return(&domain.Chapter{ID: 12, StoryID: 1, Status: domain.StatusDraft}, nil)
+
+		res, err := uc.RecordProgress(ctx, "user-1", "story-uuid", "chapter-d", 1, 0, 10)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
\ No newline at end of file
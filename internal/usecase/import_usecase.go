@@ -0,0 +1,619 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+type ImportUC struct {
+	cfg          *config.Config
+	repo         domain.ImportJobRepository
+	stories      domain.StoryUseCase
+	chapters     domain.ChapterUseCase
+	storyRepo    domain.StoryRepository
+	chapterRepo  domain.ChapterRepository
+	categoryRepo domain.CategoryRepository
+}
+
+func NewImportUseCase(cfg *config.Config, repo domain.ImportJobRepository, stories domain.StoryUseCase, chapters domain.ChapterUseCase, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository, categoryRepo domain.CategoryRepository) *ImportUC {
+	return &ImportUC{cfg: cfg, repo: repo, stories: stories, chapters: chapters, storyRepo: storyRepo, chapterRepo: chapterRepo, categoryRepo: categoryRepo}
+}
+
+// Submit memeriksa struktur file lalu menyimpannya sebagai job pending. Kesalahan per
+// baris tidak menolak file, melainkan dilaporkan di Report setelah job diproses.
+func (u *ImportUC) Submit(ctx context.Context, format string, data, archive []byte, dryRun bool) (*domain.ImportJob, error) {
+	// file dan arsip disimpan utuh di import_jobs sampai job selesai
+	if len(data)+len(archive) > u.cfg.ImportMaxSizeMB<<20 {
+		return nil, &domain.InvalidImportError{Reason: "file and archive are larger than " + strconv.Itoa(u.cfg.ImportMaxSizeMB) + " MB"}
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	lines, err := parseImportRows(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, &domain.InvalidImportError{Reason: "file has no rows"}
+	}
+	if len(lines) > u.cfg.ImportMaxRows {
+		return nil, &domain.InvalidImportError{Reason: "file has more than " + strconv.Itoa(u.cfg.ImportMaxRows) + " rows"}
+	}
+	if len(archive) == 0 {
+		archive = nil
+	} else {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, &domain.InvalidImportError{Reason: "archive is not a valid ZIP"}
+		}
+		// setiap file dibaca utuh ke memori saat job berjalan
+		for _, f := range zr.File {
+			if f.UncompressedSize64 > uint64(u.cfg.ImportMaxFileMB)<<20 {
+				return nil, &domain.InvalidImportError{Reason: f.Name + " is larger than " + strconv.Itoa(u.cfg.ImportMaxFileMB) + " MB"}
+			}
+		}
+	}
+
+	job := &domain.ImportJob{
+		UUID:      uuid.New().String(),
+		UserID:    domain.ActorFromContext(ctx),
+		Format:    format,
+		DryRun:    dryRun,
+		Status:    domain.ImportStatusPending,
+		Payload:   data,
+		Archive:   archive,
+		TotalRows: len(lines),
+		Report:    domain.ImportReport{},
+	}
+	if err := u.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (u *ImportUC) Get(ctx context.Context, uuid string) (*domain.ImportJob, error) {
+	job, err := u.repo.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+// RunPending memproses job pending satu per satu sampai habis dan mengembalikan jumlah
+// job yang selesai. Job running yang tidak mengirim heartbeat melewati batas waktu
+// dianggap terputus.
+func (u *ImportUC) RunPending(ctx context.Context) (int, error) {
+	timeout := time.Duration(u.cfg.ImportJobTimeoutMin) * time.Minute
+	if _, err := u.repo.FailStale(ctx, time.Now().Add(-timeout)); err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for ctx.Err() == nil {
+		job, err := u.repo.ClaimNext(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if job == nil {
+			break
+		}
+		u.run(ctx, job)
+		if err := u.repo.Finish(ctx, job); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+func (u *ImportUC) run(ctx context.Context, job *domain.ImportJob) {
+	defer func() {
+		now := time.Now()
+		job.FinishedAt = &now
+	}()
+	job.Report = domain.ImportReport{}
+	job.SucceededRows, job.FailedRows = 0, 0
+
+	lines, err := parseImportRows(job.Format, job.Payload)
+	if err != nil {
+		job.Status, job.Error = domain.ImportStatusFailed, err.Error()
+		return
+	}
+	files := map[string]*zip.File{}
+	if len(job.Archive) > 0 {
+		zr, err := zip.NewReader(bytes.NewReader(job.Archive), int64(len(job.Archive)))
+		if err != nil {
+			job.Status, job.Error = domain.ImportStatusFailed, err.Error()
+			return
+		}
+		for _, f := range zr.File {
+			files[path.Clean(f.Name)] = f
+		}
+	}
+
+	// baris dijalankan dengan identitas pengirim job, sama seperti request biasa
+	ctx = domain.WithActor(ctx, job.UserID)
+	batch := &importBatch{
+		uc:         u,
+		dryRun:     job.DryRun,
+		files:      files,
+		refs:       map[string]*importTarget{},
+		existing:   map[string]*importTarget{},
+		categories: map[string]*domain.Category{},
+		titles:     map[string]int{},
+	}
+	// heartbeat dikirim beberapa kali dalam satu batas waktu agar replika lain tidak
+	// menggagalkan job yang masih berjalan
+	interval := time.Duration(u.cfg.ImportJobTimeoutMin) * time.Minute / 4
+	heartbeat := time.Now()
+	for i, line := range lines {
+		if interval > 0 && time.Since(heartbeat) > interval {
+			_ = u.repo.Heartbeat(ctx, job.ID)
+			heartbeat = time.Now()
+		}
+		res := batch.apply(ctx, i+1, line)
+		if res.Status == domain.ImportResultOK {
+			job.SucceededRows++
+		} else {
+			job.FailedRows++
+		}
+		job.Report = append(job.Report, res)
+	}
+	job.TotalRows = len(lines)
+	job.Status = domain.ImportStatusCompleted
+}
+
+// importLine adalah satu baris hasil parsing. err berisi kesalahan format baris itu
+// sendiri agar dilaporkan per baris, bukan menolak seluruh file.
+type importLine struct {
+	row domain.ImportRow
+	err error
+}
+
+var importColumns = []string{
+	"type", "ref", "story", "chapter", "title", "description", "category",
+	"synopsis", "duration_seconds", "sequence", "content", "image", "sound",
+}
+
+func parseImportRows(format string, data []byte) ([]importLine, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return parseImportCSV(data)
+	case domain.ImportFormatJSON:
+		return parseImportJSON(data)
+	}
+	return nil, &domain.InvalidImportError{Reason: "format must be csv or json"}
+}
+
+func parseImportCSV(data []byte) ([]importLine, error) {
+	// spreadsheet sering menyimpan CSV dengan BOM UTF-8
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &domain.InvalidImportError{Reason: err.Error()}
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(importColumns, name) {
+			return nil, &domain.InvalidImportError{Reason: "unknown column " + strconv.Quote(name)}
+		}
+		if _, ok := index[name]; ok {
+			return nil, &domain.InvalidImportError{Reason: "duplicate column " + strconv.Quote(name)}
+		}
+		index[name] = i
+	}
+	if _, ok := index["type"]; !ok {
+		return nil, &domain.InvalidImportError{Reason: "column \"type\" is required"}
+	}
+
+	var lines []importLine
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, &domain.InvalidImportError{Reason: err.Error()}
+		}
+		lines = append(lines, csvImportLine(index, record, len(header)))
+	}
+}
+
+func csvImportLine(index map[string]int, record []string, columns int) importLine {
+	get := func(name string) string {
+		if i, ok := index[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	line := importLine{row: domain.ImportRow{
+		Type:        get("type"),
+		Ref:         get("ref"),
+		Story:       get("story"),
+		Chapter:     get("chapter"),
+		Title:       get("title"),
+		Description: get("description"),
+		Category:    get("category"),
+		Synopsis:    get("synopsis"),
+		Content:     get("content"),
+		Image:       get("image"),
+		Sound:       get("sound"),
+	}}
+	if len(record) != columns {
+		line.err = fmt.Errorf("row has %d fields, header has %d", len(record), columns)
+		return line
+	}
+	for name, dst := range map[string]*int{"duration_seconds": &line.row.DurationSeconds, "sequence": &line.row.Sequence} {
+		value := get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			line.err = fmt.Errorf("%s must be a number", name)
+			return line
+		}
+		*dst = n
+	}
+	return line
+}
+
+func parseImportJSON(data []byte) ([]importLine, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, &domain.InvalidImportError{Reason: "file must be a JSON array of rows"}
+	}
+	lines := make([]importLine, len(items))
+	for i, item := range items {
+		dec := json.NewDecoder(bytes.NewReader(item))
+		dec.DisallowUnknownFields()
+		lines[i].err = dec.Decode(&lines[i].row)
+	}
+	return lines, nil
+}
+
+// importTarget adalah story atau chapter yang bisa menjadi induk baris berikutnya, baik
+// yang sudah ada di database maupun yang dibuat file ini. Saat dry run uuid baris baru
+// kosong.
+type importTarget struct {
+	kind      string
+	row       int
+	uuid      string
+	failed    bool
+	slides    int
+	sequences map[int]bool
+}
+
+func newImportTarget(kind, uuid string, row int, slides []domain.Slide) *importTarget {
+	t := &importTarget{kind: kind, uuid: uuid, row: row, slides: len(slides), sequences: map[int]bool{}}
+	for _, s := range slides {
+		t.sequences[s.Sequence] = true
+	}
+	return t
+}
+
+// skippedRowError menandai baris yang tidak diproses karena baris induknya gagal.
+type skippedRowError struct {
+	row int
+}
+
+func (e *skippedRowError) Error() string {
+	return "parent row " + strconv.Itoa(e.row) + " failed"
+}
+
+// importBatch menyimpan state antar baris selama satu job. Baris valid saat dry run
+// dicatat seolah sudah dibuat agar baris turunannya ikut divalidasi.
+type importBatch struct {
+	uc         *ImportUC
+	dryRun     bool
+	files      map[string]*zip.File
+	refs       map[string]*importTarget
+	existing   map[string]*importTarget
+	categories map[string]*domain.Category
+	titles     map[string]int
+}
+
+func (b *importBatch) apply(ctx context.Context, n int, line importLine) domain.ImportRowResult {
+	row := line.row
+	row.Type = strings.ToLower(strings.TrimSpace(row.Type))
+	row.Ref = strings.TrimSpace(row.Ref)
+	res := domain.ImportRowResult{Row: n, Type: row.Type, Ref: row.Ref, Status: domain.ImportResultOK}
+
+	var target *importTarget
+	err := line.err
+	if err == nil && row.Ref != "" {
+		if prev, ok := b.refs[row.Ref]; ok {
+			err = fmt.Errorf("ref %q already used in row %d", row.Ref, prev.row)
+		}
+	}
+	if err == nil {
+		switch row.Type {
+		case domain.ImportRowStory:
+			target, err = b.story(ctx, n, row)
+		case domain.ImportRowChapter:
+			target, err = b.chapter(ctx, n, row)
+		case domain.ImportRowSlide:
+			res.ID, err = b.slide(ctx, row)
+		default:
+			err = errors.New("type must be story, chapter or slide")
+		}
+	}
+
+	if err != nil {
+		var skipped *skippedRowError
+		if errors.As(err, &skipped) {
+			res.Status = domain.ImportResultSkipped
+		} else {
+			res.Status = domain.ImportResultFailed
+		}
+		res.Error = err.Error()
+	}
+	if target != nil {
+		res.ID = target.uuid
+	}
+
+	if row.Ref != "" && row.Type != domain.ImportRowSlide {
+		if _, ok := b.refs[row.Ref]; !ok {
+			if target == nil {
+				target = &importTarget{kind: row.Type, row: n, failed: true}
+			}
+			b.refs[row.Ref] = target
+		}
+	}
+	return res
+}
+
+func (b *importBatch) story(ctx context.Context, n int, row domain.ImportRow) (*importTarget, error) {
+	title := strings.TrimSpace(row.Title)
+	switch {
+	case title == "":
+		return nil, errors.New("title is required")
+	case row.Description == "":
+		return nil, errors.New("description is required")
+	case row.Category == "":
+		return nil, errors.New("category is required")
+	}
+	if prev, ok := b.titles[title]; ok {
+		return nil, fmt.Errorf("title already used in row %d", prev)
+	}
+
+	cat, err := b.category(ctx, strings.TrimSpace(row.Category))
+	if err != nil {
+		return nil, err
+	}
+	isDup, err := b.uc.storyRepo.CheckDuplicate(ctx, title, row.Description)
+	if err != nil {
+		return nil, err
+	}
+	if isDup {
+		return nil, errors.New("story with same title already exists")
+	}
+	thumb, thumbHeader, err := b.open(row.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	target := newImportTarget(domain.ImportRowStory, "", n, nil)
+	if !b.dryRun {
+		story, err := b.uc.stories.Create(ctx, title, row.Description, cat.UUID, domain.ActorFromContext(ctx), thumb, thumbHeader)
+		if err != nil {
+			return nil, err
+		}
+		target.uuid = story.UUID
+	}
+	b.titles[title] = n
+	return target, nil
+}
+
+func (b *importBatch) chapter(ctx context.Context, n int, row domain.ImportRow) (*importTarget, error) {
+	if strings.TrimSpace(row.Title) == "" {
+		return nil, errors.New("title is required")
+	}
+	if row.DurationSeconds < 0 {
+		return nil, errors.New("duration_seconds must not be negative")
+	}
+	story, err := b.parent(ctx, domain.ImportRowStory, row.Story)
+	if err != nil {
+		return nil, err
+	}
+	cover, coverHeader, err := b.open(row.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	target := newImportTarget(domain.ImportRowChapter, "", n, nil)
+	if !b.dryRun {
+		chapter, err := b.uc.chapters.Create(ctx, story.uuid, strings.TrimSpace(row.Title), row.Synopsis, row.DurationSeconds, cover, coverHeader)
+		if err != nil {
+			return nil, err
+		}
+		target.uuid = chapter.UUID
+	}
+	return target, nil
+}
+
+func (b *importBatch) slide(ctx context.Context, row domain.ImportRow) (string, error) {
+	if row.Content == "" {
+		return "", errors.New("content is required")
+	}
+	if row.Sequence <= 0 {
+		return "", errors.New("sequence must be greater than 0")
+	}
+
+	kind, ref, limit := domain.ImportRowChapter, row.Chapter, b.uc.cfg.ChapterSlideLimit
+	switch {
+	case row.Story != "" && row.Chapter != "":
+		return "", errors.New("set either story or chapter, not both")
+	case row.Story != "":
+		if row.Sound != "" {
+			return "", errors.New("sound is only allowed on chapter slides")
+		}
+		kind, ref, limit = domain.ImportRowStory, row.Story, b.uc.cfg.SlideLimit
+	case row.Chapter == "":
+		return "", errors.New("story or chapter is required")
+	}
+
+	parent, err := b.parent(ctx, kind, ref)
+	if err != nil {
+		return "", err
+	}
+	if parent.slides >= limit {
+		return "", domain.ErrSlideLimitReached
+	}
+	if parent.sequences[row.Sequence] {
+		return "", fmt.Errorf("sequence %d already used", row.Sequence)
+	}
+	image, imageHeader, err := b.open(row.Image)
+	if err != nil {
+		return "", err
+	}
+	sound, soundHeader, err := b.open(row.Sound)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	if !b.dryRun {
+		var slide *domain.Slide
+		if kind == domain.ImportRowStory {
+			slide, err = b.uc.stories.AddSlide(ctx, parent.uuid, row.Content, row.Sequence, image, imageHeader)
+		} else {
+			slide, err = b.uc.chapters.AddSlide(ctx, parent.uuid, row.Content, row.Sequence, image, imageHeader, sound, soundHeader)
+		}
+		if err != nil {
+			return "", err
+		}
+		id = strconv.FormatUint(uint64(slide.ID), 10)
+	}
+	parent.slides++
+	parent.sequences[row.Sequence] = true
+	return id, nil
+}
+
+// parent mencari induk lewat ref baris sebelumnya, lalu lewat UUID di database.
+func (b *importBatch) parent(ctx context.Context, kind, ref string) (*importTarget, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, errors.New(kind + " is required")
+	}
+	if t, ok := b.refs[ref]; ok {
+		if t.kind != kind {
+			return nil, fmt.Errorf("ref %q is not a %s", ref, kind)
+		}
+		if t.failed {
+			return nil, &skippedRowError{row: t.row}
+		}
+		return t, nil
+	}
+	if t, ok := b.existing[kind+":"+ref]; ok {
+		return t, nil
+	}
+
+	notFound := fmt.Errorf("%s %q not found", kind, ref)
+	if _, err := uuid.Parse(ref); err != nil {
+		return nil, notFound
+	}
+	var t *importTarget
+	if kind == domain.ImportRowStory {
+		story, err := b.uc.storyRepo.GetByUUID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if story == nil {
+			return nil, notFound
+		}
+		t = newImportTarget(kind, story.UUID, 0, story.Slides)
+	} else {
+		chapter, err := b.uc.chapterRepo.GetByUUID(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		if chapter == nil {
+			return nil, notFound
+		}
+		t = newImportTarget(kind, chapter.UUID, 0, chapter.Slides)
+	}
+	b.existing[kind+":"+ref] = t
+	return t, nil
+}
+
+// category menerima UUID atau nama kategori.
+func (b *importBatch) category(ctx context.Context, value string) (*domain.Category, error) {
+	if cat, ok := b.categories[value]; ok {
+		return cat, nil
+	}
+	var cat *domain.Category
+	var err error
+	if _, parseErr := uuid.Parse(value); parseErr == nil {
+		cat, err = b.uc.categoryRepo.GetByUUID(ctx, value)
+	} else {
+		cat, err = b.uc.categoryRepo.GetByName(ctx, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cat == nil {
+		return nil, fmt.Errorf("category %q not found", value)
+	}
+	b.categories[value] = cat
+	return cat, nil
+}
+
+// open membaca file dari arsip. Saat dry run cukup dipastikan file ada.
+func (b *importBatch) open(name string) (multipart.File, *multipart.FileHeader, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil, nil
+	}
+	f, ok := b.files[path.Clean(name)]
+	if !ok {
+		return nil, nil, fmt.Errorf("file %q not found in archive", name)
+	}
+	// batas dicek lagi karena job bisa dijalankan dengan konfigurasi yang berbeda
+	limit := int64(b.uc.cfg.ImportMaxFileMB) << 20
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, nil, fmt.Errorf("file %q is larger than %d MB", name, b.uc.cfg.ImportMaxFileMB)
+	}
+	if b.dryRun {
+		return nil, nil, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("file %q is larger than %d MB", name, b.uc.cfg.ImportMaxFileMB)
+	}
+	return memoryFile{bytes.NewReader(data)}, &multipart.FileHeader{Filename: path.Base(name), Size: int64(len(data))}, nil
+}
\ No newline at end of file
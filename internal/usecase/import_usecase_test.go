@@ -0,0 +1,173 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestImportUseCase_Submit(t *testing.T) {
+	ctx := domain.WithActor(context.TODO(), "admin-1")
+	cfg := &config.Config{ImportMaxRows: 2, ImportMaxSizeMB: 1, ImportMaxFileMB: 1}
+
+	t.Run("unknown column", func(t *testing.T) {
+		uc := usecase.NewImportUseCase(cfg, new(mocks.ImportJobRepositoryMock), nil, nil, nil, nil, nil)
+
+		_, err := uc.Submit(ctx, "csv", []byte("type,colour\nstory,red\n"), nil, false)
+
+		var invalid *domain.InvalidImportError
+		assert.ErrorAs(t, err, &invalid)
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		uc := usecase.NewImportUseCase(cfg, new(mocks.ImportJobRepositoryMock), nil, nil, nil, nil, nil)
+
+		_, err := uc.Submit(ctx, "json", []byte(`[{"type":"story"},{"type":"story"},{"type":"story"}]`), nil, false)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		uc := usecase.NewImportUseCase(cfg, new(mocks.ImportJobRepositoryMock), nil, nil, nil, nil, nil)
+
+		_, err := uc.Submit(ctx, "csv", []byte("type\nstory\n"), make([]byte, 1<<20), false)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("archive file too large", func(t *testing.T) {
+		uc := usecase.NewImportUseCase(cfg, new(mocks.ImportJobRepositoryMock), nil, nil, nil, nil, nil)
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.Create("yunus/1.jpg")
+		require.NoError(t, err)
+		_, err = w.Write(make([]byte, 2<<20))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		_, err = uc.Submit(ctx, "csv", []byte("type\nstory\n"), buf.Bytes(), false)
+
+		var invalid *domain.InvalidImportError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, "yunus/1.jpg is larger than 1 MB", invalid.Reason)
+	})
+
+	t.Run("queued", func(t *testing.T) {
+		mockRepo := new(mocks.ImportJobRepositoryMock)
+		uc := usecase.NewImportUseCase(cfg, mockRepo, nil, nil, nil, nil, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.ImportJob")).Return(nil)
+
+		job, err := uc.Submit(ctx, " CSV ", []byte("\xef\xbb\xbfType,Title\nstory,Kisah\n"), nil, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ImportStatusPending, job.Status)
+		assert.Equal(t, domain.ImportFormatCSV, job.Format)
+		assert.Equal(t, "admin-1", job.UserID)
+		assert.Equal(t, 1, job.TotalRows)
+		assert.True(t, job.DryRun)
+	})
+}
+
+func TestImportUseCase_RunPending(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{ImportMaxRows: 100, ImportJobTimeoutMin: 60, SlideLimit: 20, ChapterSlideLimit: 20}
+	category := &domain.Category{ID: 3, UUID: "c-1", Name: "Nabi"}
+
+	run := func(t *testing.T, job *domain.ImportJob, stories domain.StoryUseCase, storyRepo *mocks.StoryRepositoryMock, categoryRepo *mocks.CategoryRepositoryMock) *domain.ImportJob {
+		mockRepo := new(mocks.ImportJobRepositoryMock)
+		mockRepo.On("FailStale", ctx, mock.Anything).Return(int64(0), nil)
+		mockRepo.On("ClaimNext", ctx).Return(job, nil).Once()
+		mockRepo.On("ClaimNext", ctx).Return(nil, nil)
+		mockRepo.On("Finish", ctx, job).Return(nil)
+		uc := usecase.NewImportUseCase(cfg, mockRepo, stories, nil, storyRepo, nil, categoryRepo)
+
+		processed, err := uc.RunPending(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		mockRepo.AssertExpectations(t)
+		return job
+	}
+
+	t.Run("dry run reports every row", func(t *testing.T) {
+		mockStories := new(mocks.StoryUseCaseMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockCategories := new(mocks.CategoryRepositoryMock)
+		mockCategories.On("GetByName", mock.Anything, "Nabi").Return(category, nil)
+		mockCategories.On("GetByName", mock.Anything, "Hilang").Return(nil, nil)
+		mockStoryRepo.On("CheckDuplicate", mock.Anything, "Kisah Nabi Yunus", "Ditelan ikan").Return(false, nil)
+		mockStoryRepo.On("CheckDuplicate", mock.Anything, "Kisah Nabi Nuh", "Bahtera").Return(false, nil)
+
+		csv := "type,ref,story,title,description,category,sequence,content,image\n" +
+			"story,yunus,,Kisah Nabi Yunus,Ditelan ikan,Nabi,,,\n" +
+			"slide,,yunus,,,,1,Di dalam perut ikan,\n" +
+			"slide,,yunus,,,,1,Sequence ganda,\n" +
+			"slide,,yunus,,,,2,Gambar hilang,yunus/2.jpg\n" +
+			"story,nuh,,Kisah Nabi Nuh,Bahtera,Hilang,,,\n" +
+			"slide,,nuh,,,,1,Banjir besar,\n" +
+			"slide,,nabi-lain,,,,x,Bukan angka,\n" +
+			"story,yunus,,Kisah Nabi Nuh,Bahtera,Nabi,,,\n"
+		job := run(t, &domain.ImportJob{ID: 1, Format: domain.ImportFormatCSV, DryRun: true, Payload: []byte(csv)}, mockStories, mockStoryRepo, mockCategories)
+
+		assert.Equal(t, domain.ImportStatusCompleted, job.Status)
+		assert.Equal(t, 8, job.TotalRows)
+		assert.Equal(t, 2, job.SucceededRows)
+		assert.Equal(t, 6, job.FailedRows)
+		require.Len(t, job.Report, 8)
+
+		statuses := make([]string, len(job.Report))
+		for i, res := range job.Report {
+			statuses[i] = res.Status
+		}
+		assert.Equal(t, []string{"ok", "ok", "failed", "failed", "failed", "skipped", "failed", "failed"}, statuses)
+		assert.Equal(t, "sequence 1 already used", job.Report[2].Error)
+		assert.Equal(t, `file "yunus/2.jpg" not found in archive`, job.Report[3].Error)
+		assert.Equal(t, `category "Hilang" not found`, job.Report[4].Error)
+		assert.Equal(t, "parent row 5 failed", job.Report[5].Error)
+		assert.Equal(t, "sequence must be a number", job.Report[6].Error)
+		assert.Equal(t, `ref "yunus" already used in row 1`, job.Report[7].Error)
+		mockStories.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.NotNil(t, job.FinishedAt)
+	})
+
+	t.Run("creates through the story use case", func(t *testing.T) {
+		mockStories := new(mocks.StoryUseCaseMock)
+		mockStoryRepo := new(mocks.StoryRepositoryMock)
+		mockCategories := new(mocks.CategoryRepositoryMock)
+		mockCategories.On("GetByUUID", mock.Anything, "8f14e45f-ceea-467f-a0e6-1f4c0c0f3c2d").Return(category, nil)
+		mockStoryRepo.On("CheckDuplicate", mock.Anything, "Kisah Nabi Yunus", "Ditelan ikan").Return(false, nil)
+		mockStories.On("Create", mock.Anything, "Kisah Nabi Yunus", "Ditelan ikan", "c-1", "admin-1", nil, (*multipart.FileHeader)(nil)).
+			Return(&domain.Story{UUID: "s-1"}, nil)
+		mockStories.On("AddSlide", mock.Anything, "s-1", "Di dalam perut ikan", 1, nil, (*multipart.FileHeader)(nil)).
+			Return(&domain.Slide{ID: 42}, nil)
+
+		payload := `[
+			{"type": "story", "ref": "yunus", "title": "Kisah Nabi Yunus", "description": "Ditelan ikan", "category": "8f14e45f-ceea-467f-a0e6-1f4c0c0f3c2d"},
+			{"type": "slide", "story": "yunus", "sequence": 1, "content": "Di dalam perut ikan"},
+			{"type": "slide", "story": "yunus", "sequence": 2, "content": "Suara", "sound": "a.mp3"},
+			{"type": "slide", "story": "yunus", "sequence": "3"}
+		]`
+		job := run(t, &domain.ImportJob{ID: 2, UserID: "admin-1", Format: domain.ImportFormatJSON, Payload: []byte(payload)}, mockStories, mockStoryRepo, mockCategories)
+
+		require.Len(t, job.Report, 4)
+		assert.Equal(t, "s-1", job.Report[0].ID)
+		assert.Equal(t, "42", job.Report[1].ID)
+		assert.Equal(t, "sound is only allowed on chapter slides", job.Report[2].Error)
+		assert.Equal(t, domain.ImportResultFailed, job.Report[3].Status)
+		mockStories.AssertExpectations(t)
+	})
+}
\ No newline at end of file
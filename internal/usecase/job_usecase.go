@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+// maxJobBackoff membatasi jeda retry agar job tidak tertunda terlalu lama.
+const maxJobBackoff = time.Hour
+
+type JobUC struct {
+	cfg      *config.Config
+	repo     domain.JobRepository
+	handlers map[string]domain.JobHandler
+}
+
+func NewJobUseCase(cfg *config.Config, repo domain.JobRepository, media *SlideMediaUC, palette *StoryPaletteUC, images *ImagePaletteUC, recommender *RecommendationUC) *JobUC {
+	return &JobUC{cfg: cfg, repo: repo, handlers: map[string]domain.JobHandler{
+		domain.JobTypeSlideMedia:      media,
+		domain.JobTypeStoryPalette:    palette,
+		domain.JobTypeImagePalette:    images,
+		domain.JobTypeRecommendations: recommender,
+	}}
+}
+
+// RunNext mengambil dan menjalankan satu job, false berarti antrean sedang kosong. Job
+// yang gagal dijadwalkan ulang atau dikubur, errornya tetap dikembalikan agar dicatat
+// oleh worker.
+func (u *JobUC) RunNext(ctx context.Context) (bool, error) {
+	job, err := u.repo.Claim(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	handler, known := u.handlers[job.Type]
+	var cause error
+	if known {
+		stop := u.heartbeat(ctx, job)
+		cause = runJobHandler(ctx, handler, job.Payload)
+		stop()
+	} else {
+		cause = fmt.Errorf("unknown job type %q", job.Type)
+	}
+	now := time.Now()
+	if cause == nil {
+		job.Status, job.FinishedAt = domain.JobStatusCompleted, &now
+		return true, lockError(job, u.repo.Complete(ctx, job))
+	}
+
+	job.LastError = cause.Error()
+	failure := fmt.Errorf("job %s (%s) attempt %d/%d: %w", job.UUID, job.Type, job.Attempts, job.MaxAttempts, cause)
+	if known && job.Attempts < job.MaxAttempts {
+		job.Status, job.RunAt = domain.JobStatusPending, now.Add(u.backoff(job.Attempts))
+		if err := u.repo.Retry(ctx, job); err != nil {
+			return true, lockError(job, err)
+		}
+		return true, failure
+	}
+
+	job.Status, job.FinishedAt = domain.JobStatusDead, &now
+	if err := u.repo.Bury(ctx, job); err != nil {
+		return true, lockError(job, err)
+	}
+	if known {
+		if err := handler.Dead(ctx, job.Payload, cause); err != nil {
+			return true, errors.Join(failure, err)
+		}
+	}
+	return true, failure
+}
+
+// heartbeat memperbarui locked_at beberapa kali dalam satu JobLockTimeoutMin selama
+// handler berjalan, fungsi yang dikembalikan menghentikannya.
+func (u *JobUC) heartbeat(ctx context.Context, job *domain.Job) func() {
+	interval := time.Duration(u.cfg.JobLockTimeoutMin) * time.Minute / 4
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = u.repo.Heartbeat(ctx, job)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// lockError memberi konteks jika job sudah diambil alih RequeueStale sebelum hasil
+// percobaan ini disimpan.
+func lockError(job *domain.Job, err error) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return fmt.Errorf("job %s (%s) attempt %d lost its lock: %w", job.UUID, job.Type, job.Attempts, err)
+	}
+	return err
+}
+
+// backoff menggandakan jeda setiap percobaan mulai dari JobRetryBaseSec.
+func (u *JobUC) backoff(attempt int) time.Duration {
+	delay := time.Duration(u.cfg.JobRetryBaseSec) * time.Second
+	for i := 1; i < attempt && delay < maxJobBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxJobBackoff {
+		delay = maxJobBackoff
+	}
+	return delay
+}
+
+// runJobHandler mengubah panic di handler menjadi error agar worker tetap hidup.
+func runJobHandler(ctx context.Context, handler domain.JobHandler, payload domain.JobPayload) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler.Handle(ctx, payload)
+}
+
+// RequeueStale mengembalikan job yang worker-nya hilang lebih dari JobLockTimeoutMin.
+func (u *JobUC) RequeueStale(ctx context.Context) (int64, error) {
+	lockedBefore := time.Now().Add(-time.Duration(u.cfg.JobLockTimeoutMin) * time.Minute)
+	requeued, buried, err := u.repo.RequeueStale(ctx, lockedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, job := range buried {
+		if handler, ok := u.handlers[job.Type]; ok {
+			if err := handler.Dead(ctx, job.Payload, errors.New(job.LastError)); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return requeued + int64(len(buried)), errors.Join(errs...)
+}
+
+func (u *JobUC) Get(ctx context.Context, uuid string) (*domain.Job, error) {
+	job, err := u.repo.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+func (u *JobUC) ListDead(ctx context.Context, limit int) ([]domain.DeadJob, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return u.repo.ListDead(ctx, limit)
+}
+
+// Retry memindahkan job dari dead_jobs kembali ke antrean dengan jatah percobaan baru.
+func (u *JobUC) Retry(ctx context.Context, deadID uint) (*domain.Job, error) {
+	job, err := u.repo.Revive(ctx, deadID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrNotFound
+	}
+	return job, nil
+}
+
+// enqueueJob menyimpan job baru yang langsung bisa diambil worker.
+func enqueueJob(ctx context.Context, repo domain.JobRepository, cfg *config.Config, jobType string, payload interface{}) (*domain.Job, error) {
+	return enqueueJobWithUUID(ctx, repo, cfg, uuid.New().String(), jobType, payload)
+}
+
+// enqueueJobWithUUID dipakai jika payload perlu memuat UUID job-nya sendiri.
+func enqueueJobWithUUID(ctx context.Context, repo domain.JobRepository, cfg *config.Config, jobUUID, jobType string, payload interface{}) (*domain.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job := &domain.Job{
+		UUID:        jobUUID,
+		Type:        jobType,
+		Payload:     data,
+		Status:      domain.JobStatusPending,
+		MaxAttempts: max(cfg.JobMaxAttempts, 1),
+		RunAt:       time.Now(),
+	}
+	if err := repo.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
\ No newline at end of file
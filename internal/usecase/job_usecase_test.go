@@ -0,0 +1,274 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
+
+)
+
+type memFile struct {
+	*bytes.Reader
+}
+
+func (memFile) Close() error {
+	return nil
+}
+
+func testFile(content string) multipart.File {
+	return memFile{bytes.NewReader([]byte(content))}
+}
+
+func TestJobUseCase_RunNext(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{JobRetryBaseSec: 10}
+
+	newJob := func(t *testing.T, attempts int, payload domain.SlideMediaJob) *domain.Job {
+		data, err := json.Marshal(payload)
+		require.NoError(t, err)
+		return &domain.Job{ID: 1, UUID: "j-1", Type: domain.JobTypeSlideMedia, Payload: data, Attempts: attempts, MaxAttempts: 3}
+	}
+
+	t.Run("empty queue", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		uc := usecase.NewJobUseCase(cfg, jobs, nil, nil, nil, nil)
+		jobs.On("Claim", ctx).Return(nil, nil)
+
+		found, err := uc.RunNext(ctx)
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("image moved to its target and slide ready", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		stories := new(mocks.StoryRepositoryMock)
+		storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+		require.NoError(t, err)
+		stagedURL, err := storage.UploadToContainer(ctx, strings.NewReader("image-bytes"), "media", "processing/x.png")
+		require.NoError(t, err)
+
+		job := newJob(t, 1, domain.SlideMediaJob{JobID: "job-1", SlideID: 7, StoryID: 1, Image: &domain.StagedMedia{
+			Container: "media", URL: stagedURL, Filename: "slide.png", Target: "stories/slides/x.png",
+		}})
+		slide := &domain.Slide{ID: 7, Content: "Isi", Status: domain.SlideStatusProcessing, JobID: "job-1"}
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Complete", ctx, job).Return(nil)
+		stories.On("GetSlideByID", ctx, uint(7)).Return(slide, nil)
+		stories.On("UpdateSlideMedia", mock.Anything, slide).Return(nil)
+
+		media := usecase.NewSlideMediaUseCase(cfg, stories, storage, nil, nil, nil)
+		uc := usecase.NewJobUseCase(cfg, jobs, media, nil, nil, nil)
+
+		found, err := uc.RunNext(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, domain.JobStatusCompleted, job.Status)
+		assert.Equal(t, domain.SlideStatusReady, slide.Status)
+		assert.Equal(t, "/uploads/media/stories/slides/x.png", slide.ImageURL)
+
+		_, err = storage.OpenFromContainer(ctx, "media", stagedURL)
+		assert.Error(t, err, "staged file should be removed")
+	})
+
+	t.Run("superseded job leaves the slide alone", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		stories := new(mocks.StoryRepositoryMock)
+		storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+		require.NoError(t, err)
+		stagedURL, err := storage.UploadToContainer(ctx, strings.NewReader("old-image"), "media", "processing/old.png")
+		require.NoError(t, err)
+
+		job := newJob(t, 4, domain.SlideMediaJob{JobID: "job-old", SlideID: 7, StoryID: 1, Image: &domain.StagedMedia{
+			Container: "media", URL: stagedURL, Filename: "old.png", Target: "stories/slides/old.png",
+		}})
+		slide := &domain.Slide{ID: 7, Status: domain.SlideStatusProcessing, JobID: "job-new"}
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Complete", ctx, job).Return(nil)
+		stories.On("GetSlideByID", ctx, uint(7)).Return(slide, nil)
+
+		uc := usecase.NewJobUseCase(cfg, jobs, usecase.NewSlideMediaUseCase(cfg, stories, storage, nil, nil, nil), nil, nil, nil)
+
+		found, err := uc.RunNext(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, domain.JobStatusCompleted, job.Status)
+		assert.Equal(t, domain.SlideStatusProcessing, slide.Status)
+		stories.AssertNotCalled(t, "UpdateSlideMedia", mock.Anything, mock.Anything)
+
+		_, err = storage.OpenFromContainer(ctx, "media", stagedURL)
+		assert.Error(t, err, "staged file should be removed")
+	})
+
+	t.Run("failure is retried with backoff", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		stories := new(mocks.StoryRepositoryMock)
+		job := newJob(t, 2, domain.SlideMediaJob{SlideID: 7})
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Retry", ctx, job).Return(nil)
+		stories.On("GetSlideByID", ctx, uint(7)).Return(nil, errors.New("connection reset"))
+
+		uc := usecase.NewJobUseCase(cfg, jobs, usecase.NewSlideMediaUseCase(cfg, stories, nil, nil, nil, nil), nil, nil, nil)
+		start := time.Now()
+
+		found, err := uc.RunNext(ctx)
+
+		assert.True(t, found)
+		assert.ErrorContains(t, err, "connection reset")
+		assert.Equal(t, domain.JobStatusPending, job.Status)
+		assert.Equal(t, "connection reset", job.LastError)
+		// percobaan kedua menunggu dua kali jeda dasar
+		assert.WithinDuration(t, start.Add(20*time.Second), job.RunAt, time.Second)
+		jobs.AssertNotCalled(t, "Bury", mock.Anything, mock.Anything)
+	})
+
+	t.Run("last attempt goes to the dead-letter queue", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		stories := new(mocks.StoryRepositoryMock)
+		job := newJob(t, 3, domain.SlideMediaJob{SlideID: 7})
+		slide := &domain.Slide{ID: 7, Status: domain.SlideStatusProcessing}
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Bury", ctx, job).Return(nil)
+		stories.On("GetSlideByID", ctx, uint(7)).Return(nil, errors.New("connection reset")).Once()
+		stories.On("GetSlideByID", ctx, uint(7)).Return(slide, nil)
+		stories.On("UpdateSlideMedia", mock.Anything, slide).Return(nil)
+
+		uc := usecase.NewJobUseCase(cfg, jobs, usecase.NewSlideMediaUseCase(cfg, stories, nil, nil, nil, nil), nil, nil, nil)
+
+		found, err := uc.RunNext(ctx)
+
+		assert.True(t, found)
+		assert.Error(t, err)
+		assert.Equal(t, domain.JobStatusDead, job.Status)
+		assert.NotNil(t, job.FinishedAt)
+		assert.Equal(t, domain.SlideStatusFailed, slide.Status)
+		jobs.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reclaimed job is not buried twice", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		stories := new(mocks.StoryRepositoryMock)
+		job := newJob(t, 3, domain.SlideMediaJob{SlideID: 7})
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Bury", ctx, job).Return(domain.ErrConflict)
+		stories.On("GetSlideByID", ctx, uint(7)).Return(nil, errors.New("connection reset"))
+
+		uc := usecase.NewJobUseCase(cfg, jobs, usecase.NewSlideMediaUseCase(cfg, stories, nil, nil, nil, nil), nil, nil, nil)
+
+		_, err := uc.RunNext(ctx)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.ErrorContains(t, err, "lost its lock")
+		stories.AssertNumberOfCalls(t, "GetSlideByID", 1)
+		stories.AssertNotCalled(t, "UpdateSlideMedia", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown type is buried at once", func(t *testing.T) {
+		jobs := new(mocks.JobRepositoryMock)
+		job := &domain.Job{ID: 2, UUID: "j-2", Type: "resize", Attempts: 1, MaxAttempts: 3}
+		jobs.On("Claim", ctx).Return(job, nil)
+		jobs.On("Bury", ctx, job).Return(nil)
+
+		uc := usecase.NewJobUseCase(cfg, jobs, nil, nil, nil, nil)
+
+		_, err := uc.RunNext(ctx)
+
+		assert.ErrorContains(t, err, `unknown job type "resize"`)
+		assert.Equal(t, domain.JobStatusDead, job.Status)
+	})
+}
+
+func TestStoryPaletteUseCase_Handle(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{AzureContainerStoriesName: "stories"}
+	storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+	require.NoError(t, err)
+	thumbURL, err := storage.UploadToContainer(ctx, strings.NewReader("not-an-image"), "stories", "thumbnails/x.jpg")
+	require.NoError(t, err)
+
+	payload := func(t *testing.T) domain.JobPayload {
+		data, err := json.Marshal(domain.StoryPaletteJob{StoryUUID: "abc", ThumbnailURL: thumbURL})
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("color saved", func(t *testing.T) {
+		stories := new(mocks.StoryRepositoryMock)
+		story := &domain.Story{ID: 1, UUID: "abc", ThumbnailURL: thumbURL}
+		stories.On("GetByUUID", ctx, "abc").Return(story, nil)
+		stories.On("UpdateDominantColor", mock.Anything, story).Return(nil)
+
+		uc := usecase.NewStoryPaletteUseCase(cfg, stories, storage, nil, nil, nil)
+
+		require.NoError(t, uc.Handle(ctx, payload(t)))
+		// gambar yang tidak bisa dibaca tetap diberi warna bawaan
+		assert.Equal(t, "#000000", story.DominantColor)
+	})
+
+	t.Run("thumbnail replaced again", func(t *testing.T) {
+		stories := new(mocks.StoryRepositoryMock)
+		stories.On("GetByUUID", ctx, "abc").Return(&domain.Story{ID: 1, UUID: "abc", ThumbnailURL: "/uploads/stories/newer.jpg"}, nil)
+
+		uc := usecase.NewStoryPaletteUseCase(cfg, stories, storage, nil, nil, nil)
+
+		require.NoError(t, uc.Handle(ctx, payload(t)))
+		stories.AssertNotCalled(t, "UpdateDominantColor", mock.Anything, mock.Anything)
+	})
+}
+
+func TestImagePaletteUseCase_Handle(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{AzureContainer: "media"}
+	storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+	require.NoError(t, err)
+	imageURL, err := storage.UploadToContainer(ctx, strings.NewReader("not-an-image"), "media", "categories/x.jpg")
+	require.NoError(t, err)
+
+	payload := func(t *testing.T, entity string) domain.JobPayload {
+		data, err := json.Marshal(domain.ImagePaletteJob{Entity: entity, UUID: "abc", ImageURL: imageURL})
+		require.NoError(t, err)
+		return data
+	}
+
+	t.Run("category color saved", func(t *testing.T) {
+		categories := new(mocks.CategoryRepositoryMock)
+		redis := new(mocks.RedisRepositoryMock)
+		category := &domain.Category{ID: 1, UUID: "abc", ImageURL: imageURL}
+		categories.On("GetByUUID", ctx, "abc").Return(category, nil)
+		categories.On("UpdateDominantColor", ctx, category).Return(nil)
+		redis.On("DeletePrefix", ctx, domain.CacheKeyCategoryAll).Return(nil)
+
+		uc := usecase.NewImagePaletteUseCase(cfg, categories, nil, storage, redis)
+
+		require.NoError(t, uc.Handle(ctx, payload(t, domain.PaletteEntityCategory)))
+		assert.Equal(t, "#000000", category.DominantColor)
+		redis.AssertExpectations(t)
+	})
+
+	t.Run("collection image replaced again", func(t *testing.T) {
+		collections := new(mocks.CollectionRepositoryMock)
+		collections.On("GetByUUID", ctx, "abc").Return(&domain.Collection{ID: 1, UUID: "abc", ImageURL: "/uploads/media/collections/newer.jpg"}, nil)
+
+		uc := usecase.NewImagePaletteUseCase(cfg, nil, collections, storage, nil)
+
+		require.NoError(t, uc.Handle(ctx, payload(t, domain.PaletteEntityCollection)))
+		collections.AssertNotCalled(t, "UpdateDominantColor", mock.Anything, mock.Anything)
+	})
+}
\ No newline at end of file
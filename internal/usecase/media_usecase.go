@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+// mediaStagingPath adalah folder file mentah yang menunggu diproses worker.
+const mediaStagingPath = "processing/"
+
+// SlideMediaUC adalah handler job JobTypeSlideMedia: mengonversi audio ke AAC,
+// memindahkan gambar ke lokasi akhirnya lalu menandai slide ready.
+type SlideMediaUC struct {
+	cfg          *config.Config
+	storyRepo    domain.StoryRepository
+	uploader     domain.StorageRepository
+	revisionRepo domain.RevisionRepository
+	redisRepo    domain.RedisRepository
+	tx           domain.Transactor
+}
+
+func NewSlideMediaUseCase(cfg *config.Config, storyRepo domain.StoryRepository, uploader domain.StorageRepository, revisionRepo domain.RevisionRepository, redisRepo domain.RedisRepository, tx domain.Transactor) *SlideMediaUC {
+	return &SlideMediaUC{cfg: cfg, storyRepo: storyRepo, uploader: uploader, revisionRepo: revisionRepo, redisRepo: redisRepo, tx: tx}
+}
+
+// Handle idempoten karena nama file tujuan ditentukan saat job dibuat, retry hanya
+// menimpa file yang sama. File staging dihapus setelah slide tersimpan. Job yang sudah
+// digantikan job lain untuk slide yang sama diselesaikan tanpa mengubah slide.
+func (u *SlideMediaUC) Handle(ctx context.Context, payload domain.JobPayload) error {
+	var job domain.SlideMediaJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	slide, err := u.storyRepo.GetSlideByID(ctx, job.SlideID)
+	if err != nil {
+		return err
+	}
+	if slide == nil || slide.JobID != job.JobID {
+		// slide sudah dihapus atau media-nya diganti lagi sebelum job ini diproses
+		discardStaged(ctx, u.uploader, job.Image, job.Sound)
+		return nil
+	}
+
+	before := snapshotSlide(slide)
+	if job.Image != nil {
+		if slide.ImageURL, err = u.processImage(ctx, job.Image); err != nil {
+			return err
+		}
+	}
+	if job.Sound != nil {
+		if slide.SoundURL, err = u.processSound(ctx, job.Sound); err != nil {
+			return err
+		}
+	}
+	slide.Status = domain.SlideStatusReady
+
+	ctx = domain.WithActor(ctx, job.UserID)
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.storyRepo.UpdateSlideMedia(ctx, slide); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, job.StoryID, domain.RevisionEntitySlide, slideRevisionKey(slide.ID), domain.RevisionActionUpdate, before, snapshotSlide(slide))
+	})
+	if err != nil && !errors.Is(err, domain.ErrConflict) {
+		return err
+	}
+	u.invalidate(ctx)
+	discardStaged(ctx, u.uploader, job.Image, job.Sound)
+	return nil
+}
+
+// Dead menandai slide failed. File staging dipertahankan agar job bisa diulang dari
+// dead_jobs, kecuali job sudah digantikan job lain.
+func (u *SlideMediaUC) Dead(ctx context.Context, payload domain.JobPayload, cause error) error {
+	var job domain.SlideMediaJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	slide, err := u.storyRepo.GetSlideByID(ctx, job.SlideID)
+	if err != nil || slide == nil {
+		return err
+	}
+	if slide.JobID != job.JobID {
+		discardStaged(ctx, u.uploader, job.Image, job.Sound)
+		return nil
+	}
+	slide.Status = domain.SlideStatusFailed
+	if err := u.storyRepo.UpdateSlideMedia(ctx, slide); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			discardStaged(ctx, u.uploader, job.Image, job.Sound)
+			return nil
+		}
+		return err
+	}
+	u.invalidate(ctx)
+	return nil
+}
+
+func (u *SlideMediaUC) processImage(ctx context.Context, m *domain.StagedMedia) (string, error) {
+	data, err := u.readStaged(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	return u.uploader.UploadToContainer(ctx, bytes.NewReader(data), m.Container, m.Target)
+}
+
+func (u *SlideMediaUC) processSound(ctx context.Context, m *domain.StagedMedia) (string, error) {
+	data, err := u.readStaged(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	converted, tempPath, err := utils.ConvertToAAC(memoryFile{bytes.NewReader(data)}, m.Filename)
+	if err != nil {
+		return "", errors.New("failed to convert audio: " + err.Error())
+	}
+	defer func() {
+		converted.Close()
+		os.Remove(tempPath)
+	}()
+	return u.uploader.UploadToContainer(ctx, converted, m.Container, m.Target)
+}
+
+func (u *SlideMediaUC) readStaged(ctx context.Context, m *domain.StagedMedia) ([]byte, error) {
+	rc, err := u.uploader.OpenFromContainer(ctx, m.Container, m.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func (u *SlideMediaUC) invalidate(ctx context.Context) {
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+}
+
+// defaultDominantColor dipakai jika warna thumbnail tidak bisa diambil.
+const defaultDominantColor = "#000000"
+
+// StoryPaletteUC adalah handler job JobTypeStoryPalette: mengambil warna dominan
+// thumbnail story yang sudah terunggah.
+type StoryPaletteUC struct {
+	cfg          *config.Config
+	storyRepo    domain.StoryRepository
+	uploader     domain.StorageRepository
+	revisionRepo domain.RevisionRepository
+	redisRepo    domain.RedisRepository
+	tx           domain.Transactor
+}
+
+func NewStoryPaletteUseCase(cfg *config.Config, storyRepo domain.StoryRepository, uploader domain.StorageRepository, revisionRepo domain.RevisionRepository, redisRepo domain.RedisRepository, tx domain.Transactor) *StoryPaletteUC {
+	return &StoryPaletteUC{cfg: cfg, storyRepo: storyRepo, uploader: uploader, revisionRepo: revisionRepo, redisRepo: redisRepo, tx: tx}
+}
+
+// Handle tidak mengubah story yang sudah dihapus atau thumbnail-nya sudah diganti lagi.
+func (u *StoryPaletteUC) Handle(ctx context.Context, payload domain.JobPayload) error {
+	var job domain.StoryPaletteJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	story, err := u.storyRepo.GetByUUID(ctx, job.StoryUUID)
+	if err != nil {
+		return err
+	}
+	if story == nil || story.ThumbnailURL != job.ThumbnailURL {
+		return nil
+	}
+
+	rc, err := u.uploader.OpenFromContainer(ctx, u.cfg.AzureContainerStoriesName, job.ThumbnailURL)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	before := snapshotStory(story)
+	story.DominantColor = defaultDominantColor
+	if color, err := utils.ExtractDominantColor(rc); err == nil {
+		story.DominantColor = color
+	}
+
+	ctx = domain.WithActor(ctx, job.UserID)
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.storyRepo.UpdateDominantColor(ctx, story); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntityStory, story.UUID, domain.RevisionActionUpdate, before, snapshotStory(story))
+	})
+	if err != nil && !errors.Is(err, domain.ErrConflict) {
+		return err
+	}
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+	return nil
+}
+
+// Dead membiarkan warna dominan story apa adanya.
+func (u *StoryPaletteUC) Dead(ctx context.Context, payload domain.JobPayload, cause error) error {
+	return nil
+}
+
+// ImagePaletteUC adalah handler job JobTypeImagePalette: mengambil warna dominan gambar
+// kategori atau collection yang sudah terunggah.
+type ImagePaletteUC struct {
+	cfg            *config.Config
+	categoryRepo   domain.CategoryRepository
+	collectionRepo domain.CollectionRepository
+	uploader       domain.StorageRepository
+	redisRepo      domain.RedisRepository
+}
+
+func NewImagePaletteUseCase(cfg *config.Config, categoryRepo domain.CategoryRepository, collectionRepo domain.CollectionRepository, uploader domain.StorageRepository, redisRepo domain.RedisRepository) *ImagePaletteUC {
+	return &ImagePaletteUC{cfg: cfg, categoryRepo: categoryRepo, collectionRepo: collectionRepo, uploader: uploader, redisRepo: redisRepo}
+}
+
+// Handle tidak mengubah kategori atau collection yang sudah dihapus atau gambarnya sudah
+// diganti lagi.
+func (u *ImagePaletteUC) Handle(ctx context.Context, payload domain.JobPayload) error {
+	var job domain.ImagePaletteJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+
+	var save func(color string) error
+	var cacheKey string
+	switch job.Entity {
+	case domain.PaletteEntityCategory:
+		category, err := u.categoryRepo.GetByUUID(ctx, job.UUID)
+		if err != nil {
+			return err
+		}
+		if category == nil || category.ImageURL != job.ImageURL {
+			return nil
+		}
+		save = func(color string) error {
+			category.DominantColor = color
+			return u.categoryRepo.UpdateDominantColor(ctx, category)
+		}
+		cacheKey = domain.CacheKeyCategoryAll
+	case domain.PaletteEntityCollection:
+		collection, err := u.collectionRepo.GetByUUID(ctx, job.UUID)
+		if err != nil {
+			return err
+		}
+		if collection == nil || collection.ImageURL != job.ImageURL {
+			return nil
+		}
+		save = func(color string) error {
+			collection.DominantColor = color
+			return u.collectionRepo.UpdateDominantColor(ctx, collection)
+		}
+		cacheKey = domain.CacheKeyCollectionAll
+	default:
+		return fmt.Errorf("unknown palette entity %q", job.Entity)
+	}
+
+	rc, err := u.uploader.OpenFromContainer(ctx, u.cfg.AzureContainer, job.ImageURL)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	color := defaultDominantColor
+	if c, err := utils.ExtractDominantColor(rc); err == nil {
+		color = c
+	}
+	if err := save(color); err != nil && !errors.Is(err, domain.ErrConflict) {
+		return err
+	}
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, cacheKey)
+	}
+	return nil
+}
+
+// Dead membiarkan warna dominan apa adanya.
+func (u *ImagePaletteUC) Dead(ctx context.Context, payload domain.JobPayload, cause error) error {
+	return nil
+}
+
+// stageMedia mengunggah file apa adanya ke folder staging. Target adalah nama akhir file
+// di folder, ext kosong berarti ekstensi file asli dipakai.
+func stageMedia(ctx context.Context, uploader domain.StorageRepository, file multipart.File, header *multipart.FileHeader, container, folder, ext string) (*domain.StagedMedia, error) {
+	if file == nil {
+		return nil, nil
+	}
+	id := uuid.New().String()
+	if ext == "" {
+		ext = filepath.Ext(header.Filename)
+	}
+	url, err := uploader.UploadToContainer(ctx, file, container, mediaStagingPath+id+filepath.Ext(header.Filename))
+	if err != nil {
+		return nil, err
+	}
+	return &domain.StagedMedia{Container: container, URL: url, Filename: header.Filename, Target: folder + id + ext}, nil
+}
+
+func discardStaged(ctx context.Context, uploader domain.StorageRepository, media ...*domain.StagedMedia) {
+	for _, m := range media {
+		if m != nil {
+			uploader.DeleteFromContainer(ctx, m.Container, m.URL)
+		}
+	}
+}
+
+// slideStatus adalah status awal slide: processing bila ada media yang menunggu worker.
+func slideStatus(media ...*domain.StagedMedia) string {
+	for _, m := range media {
+		if m != nil {
+			return domain.SlideStatusProcessing
+		}
+	}
+	return domain.SlideStatusReady
+}
+
+// queueSlideMedia mengantrekan pemrosesan media slide yang sudah tersimpan dan mencatat
+// job tersebut di slide.JobID, sehingga job sebelumnya untuk slide yang sama tidak lagi
+// berlaku. Tanpa media tidak ada job yang dibuat.
+func queueSlideMedia(ctx context.Context, repo domain.JobRepository, slides domain.StoryRepository, cfg *config.Config, slide *domain.Slide, storyID uint, image, sound *domain.StagedMedia) error {
+	if image == nil && sound == nil {
+		return nil
+	}
+	jobUUID := uuid.New().String()
+	job, err := enqueueJobWithUUID(ctx, repo, cfg, jobUUID, domain.JobTypeSlideMedia, domain.SlideMediaJob{
+		JobID:   jobUUID,
+		SlideID: slide.ID,
+		StoryID: storyID,
+		UserID:  domain.ActorFromContext(ctx),
+		Image:   image,
+		Sound:   sound,
+	})
+	if err != nil {
+		return err
+	}
+	slide.JobID = job.UUID
+	slide.Status = domain.SlideStatusProcessing
+	return slides.SetSlideJob(ctx, slide)
+}
+
+// queueStoryPalette mengantrekan pengambilan warna dominan thumbnail story.
+func queueStoryPalette(ctx context.Context, repo domain.JobRepository, cfg *config.Config, story *domain.Story) error {
+	_, err := enqueueJob(ctx, repo, cfg, domain.JobTypeStoryPalette, domain.StoryPaletteJob{
+		StoryUUID:    story.UUID,
+		UserID:       domain.ActorFromContext(ctx),
+		ThumbnailURL: story.ThumbnailURL,
+	})
+	return err
+}
+
+// queueImagePalette mengantrekan pengambilan warna dominan gambar kategori atau collection.
+func queueImagePalette(ctx context.Context, repo domain.JobRepository, cfg *config.Config, entity, entityUUID, imageURL string) error {
+	_, err := enqueueJob(ctx, repo, cfg, domain.JobTypeImagePalette, domain.ImagePaletteJob{
+		Entity:   entity,
+		UUID:     entityUUID,
+		ImageURL: imageURL,
+	})
+	return err
+}
+
+// memoryFile membungkus isi file di memori agar bisa dipakai sebagai multipart.File.
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error {
+	return nil
+}
\ No newline at end of file
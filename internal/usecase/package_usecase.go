@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+const packageManifestName = "manifest.json"
+
+type PackageUC struct {
+	cfg          *config.Config
+	storyRepo    domain.StoryRepository
+	chapterRepo  domain.ChapterRepository
+	categoryRepo domain.CategoryRepository
+	uploader     domain.StorageRepository
+	revisionRepo domain.RevisionRepository
+	redisRepo    domain.RedisRepository
+	tx           domain.Transactor
+}
+
+func NewPackageUseCase(cfg *config.Config, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository, categoryRepo domain.CategoryRepository, uploader domain.StorageRepository, revisionRepo domain.RevisionRepository, redisRepo domain.RedisRepository, tx domain.Transactor) *PackageUC {
+	return &PackageUC{cfg: cfg, storyRepo: storyRepo, chapterRepo: chapterRepo, categoryRepo: categoryRepo, uploader: uploader, revisionRepo: revisionRepo, redisRepo: redisRepo, tx: tx}
+}
+
+// packageTarget adalah story beserta semua chapter dan slide-nya.
+type packageTarget struct {
+	story    *domain.Story
+	chapters []*domain.Chapter
+}
+
+// loadPackageTarget mengembalikan nil jika story belum ada.
+func (u *PackageUC) loadPackageTarget(ctx context.Context, storyUUID string) (*packageTarget, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil || story == nil {
+		return nil, err
+	}
+	chapters, err := u.chapterRepo.GetAllByStoryID(ctx, story.ID)
+	if err != nil {
+		return nil, err
+	}
+	target := &packageTarget{story: story}
+	for _, c := range chapters {
+		// GetAllByStoryID tidak memuat slide
+		chapter, err := u.chapterRepo.GetByUUID(ctx, c.UUID)
+		if err != nil {
+			return nil, err
+		}
+		if chapter != nil {
+			target.chapters = append(target.chapters, chapter)
+		}
+	}
+	return target, nil
+}
+
+// Export menulis story apa pun statusnya, termasuk chapter Draft, sebagai ZIP berisi
+// manifest.json dan semua file medianya.
+func (u *PackageUC) Export(ctx context.Context, storyUUID string, w io.Writer) error {
+	target, err := u.loadPackageTarget(ctx, storyUUID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return domain.ErrNotFound
+	}
+	story := target.story
+
+	media := newMediaSet()
+	pkg := domain.StoryPackage{
+		SchemaVersion: domain.PackageSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Story: domain.PackageStory{
+			ID:            story.UUID,
+			Title:         story.Title,
+			Description:   story.Description,
+			DominantColor: story.DominantColor,
+			Category:      domain.PackageCategory{ID: story.Category.UUID, Name: story.Category.Name, Type: story.Category.Type},
+			Thumbnail:     media.add(story.ThumbnailURL, u.cfg.AzureContainerStoriesName, "images"),
+			Slides:        u.exportSlides(media, story.Slides, u.cfg.AzureContainer),
+			Chapters:      []domain.PackageChapter{},
+		},
+	}
+	for _, chapter := range target.chapters {
+		pkg.Story.Chapters = append(pkg.Story.Chapters, domain.PackageChapter{
+			ID:              chapter.UUID,
+			Title:           chapter.Title,
+			Synopsis:        chapter.Synopsis,
+			Position:        chapter.Position,
+			DurationSeconds: chapter.DurationSeconds,
+			Cover:           media.add(chapter.CoverURL, u.cfg.AzureContainerChapterImages, "images"),
+			Slides:          u.exportSlides(media, chapter.Slides, u.cfg.AzureContainerChapterImages),
+		})
+	}
+
+	manifest, err := json.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	fw, err := zw.Create(packageManifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(manifest); err != nil {
+		return err
+	}
+	for _, f := range media.files {
+		if err := copyMediaToZip(ctx, u.uploader, zw, f); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (u *PackageUC) exportSlides(media *mediaSet, slides []domain.Slide, imageContainer string) []domain.PackageSlide {
+	out := make([]domain.PackageSlide, 0, len(slides))
+	for _, s := range slides {
+		out = append(out, domain.PackageSlide{
+			Sequence: s.Sequence,
+			Content:  s.Content,
+			Image:    media.add(s.ImageURL, imageContainer, "images"),
+			Sound:    media.add(s.SoundURL, u.cfg.AzureContainerChapterSounds, "audio"),
+		})
+	}
+	return out
+}
+
+// Import membaca paket hasil Export dan menyimpannya lewat StoryRepository.Import. Import
+// ulang paket yang sama tidak membuat data maupun file ganda: nama file media diambil
+// dari checksum isinya. Story yang Published atau InReview harus dikembalikan ke Draft
+// dulu, perubahan pada story, chapter dan slide yang sudah ada dicatat sebagai revisi.
+// File yang sudah terunggah tidak dihapus saat import gagal karena bisa jadi masih
+// dipakai, sisanya dibersihkan perintah blobs gc.
+func (u *PackageUC) Import(ctx context.Context, archive io.ReaderAt, size int64) (*domain.PackageImportResult, error) {
+	if size > int64(u.cfg.PackageMaxSizeMB)<<20 {
+		return nil, &domain.InvalidPackageError{Reason: "package is larger than " + strconv.Itoa(u.cfg.PackageMaxSizeMB) + " MB"}
+	}
+	zr, err := zip.NewReader(archive, size)
+	if err != nil {
+		return nil, &domain.InvalidPackageError{Reason: "not a zip archive"}
+	}
+	// ukuran di header ZIP bisa dipercaya: archive/zip menolak isi yang lebih besar darinya
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		if f.UncompressedSize64 > uint64(u.cfg.PackageMaxFileMB)<<20 {
+			return nil, &domain.InvalidPackageError{Reason: f.Name + " is larger than " + strconv.Itoa(u.cfg.PackageMaxFileMB) + " MB"}
+		}
+		files[f.Name] = f
+	}
+
+	pkg, err := readPackageManifest(files[packageManifestName])
+	if err != nil {
+		return nil, err
+	}
+	if err := u.validatePackage(pkg, files); err != nil {
+		return nil, err
+	}
+
+	// dicek lagi oleh StoryRepository.Import di dalam transaksi
+	before, err := u.loadPackageTarget(ctx, pkg.Story.ID)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil && (before.story.Status == domain.StatusPublished || before.story.Status == domain.StatusInReview) {
+		return nil, domain.ErrInvalidTransition
+	}
+
+	category, err := u.resolveCategory(ctx, pkg.Story.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	up := &packageUploader{uc: u, files: files, urls: map[string]string{}}
+	story := &domain.Story{
+		UUID:          pkg.Story.ID,
+		Title:         pkg.Story.Title,
+		Description:   pkg.Story.Description,
+		DominantColor: pkg.Story.DominantColor,
+		CategoryID:    category.ID,
+		UserID:        domain.ActorFromContext(ctx),
+	}
+	if story.ThumbnailURL, err = up.upload(ctx, pkg.Story.Thumbnail, u.cfg.AzureContainerStoriesName, u.cfg.StoriesThumbPath); err != nil {
+		return nil, err
+	}
+	if story.Slides, err = up.slides(ctx, pkg.Story.Slides, u.cfg.AzureContainer, u.cfg.StoriesSlidePath); err != nil {
+		return nil, err
+	}
+	for _, pc := range pkg.Story.Chapters {
+		chapter := domain.Chapter{
+			UUID:            pc.ID,
+			Title:           pc.Title,
+			Synopsis:        pc.Synopsis,
+			Position:        pc.Position,
+			DurationSeconds: pc.DurationSeconds,
+		}
+		if chapter.CoverURL, err = up.upload(ctx, pc.Cover, u.cfg.AzureContainerChapterImages, chapterCoverPath); err != nil {
+			return nil, err
+		}
+		if chapter.Slides, err = up.slides(ctx, pc.Slides, u.cfg.AzureContainerChapterImages, ""); err != nil {
+			return nil, err
+		}
+		story.Chapters = append(story.Chapters, chapter)
+	}
+
+	var created bool
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		var err error
+		if created, err = u.storyRepo.Import(ctx, story); err != nil {
+			return err
+		}
+		return u.recordImportRevisions(ctx, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeySuggestPrefix)
+	}
+
+	saved, err := u.storyRepo.GetByUUID(ctx, story.UUID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PackageImportResult{Story: saved, Created: created}, nil
+}
+
+// recordImportRevisions mencatat perubahan story, chapter dan slide yang sudah ada
+// sebelum import. Chapter dan slide baru tidak dicatat, sama seperti saat dibuat lewat
+// endpoint biasa.
+func (u *PackageUC) recordImportRevisions(ctx context.Context, before *packageTarget) error {
+	if before == nil || u.revisionRepo == nil {
+		return nil
+	}
+	after, err := u.loadPackageTarget(ctx, before.story.UUID)
+	if err != nil || after == nil {
+		return err
+	}
+	storyID := after.story.ID
+	if err := recordRevision(ctx, u.revisionRepo, storyID, domain.RevisionEntityStory, after.story.UUID, domain.RevisionActionImport, snapshotStory(before.story), snapshotStory(after.story)); err != nil {
+		return err
+	}
+
+	chapters := make(map[string]*domain.Chapter, len(before.chapters))
+	slides := make(map[uint]*domain.Slide)
+	for i := range before.story.Slides {
+		slides[before.story.Slides[i].ID] = &before.story.Slides[i]
+	}
+	for _, c := range before.chapters {
+		chapters[c.UUID] = c
+		for i := range c.Slides {
+			slides[c.Slides[i].ID] = &c.Slides[i]
+		}
+	}
+	recordSlides := func(current []domain.Slide) error {
+		for i := range current {
+			old, ok := slides[current[i].ID]
+			if !ok {
+				continue
+			}
+			if err := recordRevision(ctx, u.revisionRepo, storyID, domain.RevisionEntitySlide, slideRevisionKey(current[i].ID), domain.RevisionActionImport, snapshotSlide(old), snapshotSlide(&current[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := recordSlides(after.story.Slides); err != nil {
+		return err
+	}
+	for _, c := range after.chapters {
+		if old, ok := chapters[c.UUID]; ok {
+			if err := recordRevision(ctx, u.revisionRepo, storyID, domain.RevisionEntityChapter, c.UUID, domain.RevisionActionImport, snapshotChapter(old), snapshotChapter(c)); err != nil {
+				return err
+			}
+		}
+		if err := recordSlides(c.Slides); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readPackageManifest(f *zip.File) (*domain.StoryPackage, error) {
+	if f == nil {
+		return nil, &domain.InvalidPackageError{Reason: packageManifestName + " is missing"}
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, &domain.InvalidPackageError{Reason: err.Error()}
+	}
+	defer rc.Close()
+
+	var pkg domain.StoryPackage
+	if err := json.NewDecoder(rc).Decode(&pkg); err != nil {
+		return nil, &domain.InvalidPackageError{Reason: packageManifestName + ": " + err.Error()}
+	}
+	if pkg.SchemaVersion != domain.PackageSchemaVersion {
+		return nil, &domain.InvalidPackageError{Reason: "unsupported schema_version " + strconv.Itoa(pkg.SchemaVersion)}
+	}
+	return &pkg, nil
+}
+
+// validatePackage memeriksa seluruh isi manifest sebelum ada file yang diunggah.
+func (u *PackageUC) validatePackage(pkg *domain.StoryPackage, files map[string]*zip.File) error {
+	s := pkg.Story
+	if _, err := uuid.Parse(s.ID); err != nil {
+		return &domain.InvalidPackageError{Reason: "story id must be a UUID"}
+	}
+	if strings.TrimSpace(s.Title) == "" {
+		return &domain.InvalidPackageError{Reason: "story title is required"}
+	}
+	if err := validatePackageSlides("story", s.Slides, u.cfg.SlideLimit, files); err != nil {
+		return err
+	}
+	if err := requirePackageFile(s.Thumbnail, files); err != nil {
+		return err
+	}
+
+	ids := map[string]bool{}
+	positions := map[int]bool{}
+	for _, c := range s.Chapters {
+		if _, err := uuid.Parse(c.ID); err != nil {
+			return &domain.InvalidPackageError{Reason: "chapter id must be a UUID"}
+		}
+		if ids[c.ID] {
+			return &domain.InvalidPackageError{Reason: "duplicate chapter " + c.ID}
+		}
+		ids[c.ID] = true
+		if c.Position < 1 || positions[c.Position] {
+			return &domain.InvalidPackageError{Reason: "chapter " + c.ID + " has an invalid or duplicate position"}
+		}
+		positions[c.Position] = true
+		if strings.TrimSpace(c.Title) == "" || c.DurationSeconds < 0 {
+			return &domain.InvalidPackageError{Reason: "chapter " + c.ID + " needs a title and a non-negative duration"}
+		}
+		if err := requirePackageFile(c.Cover, files); err != nil {
+			return err
+		}
+		if err := validatePackageSlides("chapter "+c.ID, c.Slides, u.cfg.ChapterSlideLimit, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePackageSlides(owner string, slides []domain.PackageSlide, limit int, files map[string]*zip.File) error {
+	if len(slides) > limit {
+		return &domain.InvalidPackageError{Reason: owner + " has more than " + strconv.Itoa(limit) + " slides"}
+	}
+	seen := map[int]bool{}
+	for _, s := range slides {
+		if seen[s.Sequence] {
+			return &domain.InvalidPackageError{Reason: owner + " has duplicate slide sequence " + strconv.Itoa(s.Sequence)}
+		}
+		seen[s.Sequence] = true
+		if err := requirePackageFile(s.Image, files); err != nil {
+			return err
+		}
+		if err := requirePackageFile(s.Sound, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func requirePackageFile(name string, files map[string]*zip.File) error {
+	if name != "" && files[name] == nil {
+		return &domain.InvalidPackageError{Reason: "missing file " + name}
+	}
+	return nil
+}
+
+// resolveCategory mencari kategori lewat UUID, lalu lewat nama dan type karena UUID
+// kategori hasil seed bisa berbeda antar environment.
+func (u *PackageUC) resolveCategory(ctx context.Context, ref domain.PackageCategory) (*domain.Category, error) {
+	if ref.ID != "" {
+		cat, err := u.categoryRepo.GetByUUID(ctx, ref.ID)
+		if err != nil {
+			return nil, err
+		}
+		if cat != nil {
+			return cat, nil
+		}
+	}
+	if ref.Name != "" {
+		cat, err := u.categoryRepo.GetByName(ctx, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if cat != nil && (ref.Type == "" || cat.Type == ref.Type) {
+			return cat, nil
+		}
+	}
+	return nil, &domain.InvalidPackageError{Reason: "category " + ref.Name + " does not exist"}
+}
+
+// packageUploader mengunggah file dari ZIP paket ke storage. Satu file yang dipakai
+// beberapa kali di container yang sama hanya diunggah sekali.
+type packageUploader struct {
+	uc    *PackageUC
+	files map[string]*zip.File
+	urls  map[string]string
+}
+
+func (p *packageUploader) slides(ctx context.Context, slides []domain.PackageSlide, imageContainer, imageFolder string) ([]domain.Slide, error) {
+	out := make([]domain.Slide, 0, len(slides))
+	for _, s := range slides {
+		image, err := p.upload(ctx, s.Image, imageContainer, imageFolder)
+		if err != nil {
+			return nil, err
+		}
+		sound, err := p.upload(ctx, s.Sound, p.uc.cfg.AzureContainerChapterSounds, "")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, domain.Slide{Sequence: s.Sequence, Content: s.Content, ImageURL: image, SoundURL: sound})
+	}
+	return out, nil
+}
+
+func (p *packageUploader) upload(ctx context.Context, name, container, folder string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	key := container + "/" + name
+	if url, ok := p.urls[key]; ok {
+		return url, nil
+	}
+
+	f := p.files[name]
+	sum, err := zipChecksum(f)
+	if err != nil {
+		return "", err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	url, err := p.uc.uploader.UploadToContainer(ctx, rc, container, folder+sum[:32]+path.Ext(name))
+	if err != nil {
+		return "", err
+	}
+	p.urls[key] = url
+	return url, nil
+}
+
+func zipChecksum(f *zip.File) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", &domain.InvalidPackageError{Reason: f.Name + ": " + err.Error()}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+const packageStoryUUID = "0b9d3c52-6f1e-4a7b-8c2d-1e3f5a7b9c01"
+const packageChapterUUID = "7c4e2a18-3b5d-4f6a-9e8c-2d1f0b3a5c77"
+
+func TestPackageUseCase_ExportImport(t *testing.T) {
+	ctx := context.TODO()
+	cfg, storage, urls := newBundleFixture(t)
+	cfg.SlideLimit = 20
+	cfg.ChapterSlideLimit = 20
+	cfg.StoriesThumbPath = "stories/thumbnails/"
+	cfg.StoriesSlidePath = "stories/slides/"
+	cfg.PackageMaxSizeMB = 512
+	cfg.PackageMaxFileMB = 100
+
+	category := &domain.Category{ID: 3, UUID: "cat-staging", Name: "Kisah Nabi", Type: domain.CategoryTypeStory}
+	story := &domain.Story{
+		ID: 1, UUID: packageStoryUUID, Title: "Nabi Yunus", Status: domain.StatusPublished,
+		ThumbnailURL: urls["stories/thumb.jpg"], Category: *category,
+	}
+	chapter := &domain.Chapter{
+		ID: 2, UUID: packageChapterUUID, StoryID: 1, Title: "Di perut ikan", Position: 1, Status: domain.StatusDraft,
+		Slides: []domain.Slide{
+			{Sequence: 1, Content: "Ditelan ikan", ImageURL: urls["chapter-images/a.jpg"], SoundURL: urls["chapter-sounds/a.m4a"]},
+			{Sequence: 2, Content: "Berdoa", ImageURL: urls["chapter-images/a.jpg"]},
+		},
+	}
+
+	mockStories := new(mocks.StoryRepositoryMock)
+	mockChapters := new(mocks.ChapterRepositoryMock)
+	mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(story, nil)
+	mockChapters.On("GetAllByStoryID", ctx, uint(1)).Return([]domain.Chapter{*chapter}, nil)
+	mockChapters.On("GetByUUID", ctx, packageChapterUUID).Return(chapter, nil)
+
+	var archive bytes.Buffer
+	exporter := usecase.NewPackageUseCase(cfg, mockStories, mockChapters, nil, storage, nil, nil, nil)
+	require.NoError(t, exporter.Export(ctx, packageStoryUUID, &archive))
+
+	t.Run("import into another environment", func(t *testing.T) {
+		mockStories := new(mocks.StoryRepositoryMock)
+		mockCategories := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPackageUseCase(cfg, mockStories, nil, mockCategories, storage, nil, nil, nil)
+
+		// UUID kategori berbeda di environment tujuan, dicocokkan lewat nama
+		mockCategories.On("GetByUUID", ctx, "cat-staging").Return(nil, nil)
+		mockCategories.On("GetByName", ctx, "Kisah Nabi").Return(&domain.Category{ID: 9, Name: "Kisah Nabi", Type: domain.CategoryTypeStory}, nil)
+		mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(nil, nil).Once()
+		mockStories.On("Import", ctx, mock.AnythingOfType("*domain.Story")).Return(true, nil)
+		mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(&domain.Story{UUID: packageStoryUUID}, nil)
+
+		res, err := uc.Import(ctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+
+		require.NoError(t, err)
+		assert.True(t, res.Created)
+		imported := mockStories.Calls[1].Arguments.Get(1).(*domain.Story)
+		assert.Equal(t, uint(9), imported.CategoryID)
+		assert.Equal(t, "Nabi Yunus", imported.Title)
+		assert.Contains(t, imported.ThumbnailURL, "/stories/stories/thumbnails/")
+		require.Len(t, imported.Chapters, 1)
+		slides := imported.Chapters[0].Slides
+		require.Len(t, slides, 2)
+		assert.Equal(t, slides[0].ImageURL, slides[1].ImageURL)
+		assert.True(t, strings.HasSuffix(slides[0].SoundURL, ".m4a"))
+		assert.Empty(t, slides[1].SoundURL)
+	})
+
+	t.Run("reimport records revisions", func(t *testing.T) {
+		mockStories := new(mocks.StoryRepositoryMock)
+		mockChapters := new(mocks.ChapterRepositoryMock)
+		mockCategories := new(mocks.CategoryRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		tx := new(mocks.TransactorMock)
+		uc := usecase.NewPackageUseCase(cfg, mockStories, mockChapters, mockCategories, storage, mockRevisions, nil, tx)
+
+		before := &domain.Story{ID: 1, UUID: packageStoryUUID, Title: "Nabi Yunus (draf)", Status: domain.StatusDraft, CategoryID: 3}
+		after := &domain.Story{ID: 1, UUID: packageStoryUUID, Title: "Nabi Yunus", Status: domain.StatusDraft, CategoryID: 3}
+		chapterBefore := &domain.Chapter{ID: 2, UUID: packageChapterUUID, StoryID: 1, Title: "Di perut ikan", Position: 1, Slides: []domain.Slide{
+			{ID: 21, Sequence: 1, Content: "Ditelan"},
+			{ID: 22, Sequence: 2, Content: "Berdoa"},
+		}}
+		chapterAfter := &domain.Chapter{ID: 2, UUID: packageChapterUUID, StoryID: 1, Title: "Di perut ikan", Position: 1, Slides: []domain.Slide{
+			{ID: 21, Sequence: 1, Content: "Ditelan ikan"},
+			{ID: 22, Sequence: 2, Content: "Berdoa"},
+		}}
+
+		tx.On("WithinTransaction", ctx).Return(nil)
+		mockCategories.On("GetByUUID", ctx, "cat-staging").Return(category, nil)
+		mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(before, nil).Once()
+		mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(after, nil)
+		mockChapters.On("GetAllByStoryID", ctx, uint(1)).Return([]domain.Chapter{{ID: 2, UUID: packageChapterUUID}}, nil)
+		mockChapters.On("GetByUUID", ctx, packageChapterUUID).Return(chapterBefore, nil).Once()
+		mockChapters.On("GetByUUID", ctx, packageChapterUUID).Return(chapterAfter, nil)
+		mockStories.On("Import", ctx, mock.AnythingOfType("*domain.Story")).Return(false, nil).Run(func(mock.Arguments) {
+			assert.True(t, tx.InTx)
+		})
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		res, err := uc.Import(ctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+
+		require.NoError(t, err)
+		assert.False(t, res.Created)
+		require.Len(t, mockRevisions.Calls, 2)
+		storyRev := mockRevisions.Calls[0].Arguments.Get(1).(*domain.Revision)
+		assert.Equal(t, domain.RevisionEntityStory, storyRev.EntityType)
+		assert.Equal(t, domain.RevisionActionImport, storyRev.Action)
+		slideRev := mockRevisions.Calls[1].Arguments.Get(1).(*domain.Revision)
+		assert.Equal(t, domain.RevisionEntitySlide, slideRev.EntityType)
+		assert.Equal(t, "21", slideRev.EntityKey)
+	})
+
+	t.Run("published story is not overwritten", func(t *testing.T) {
+		mockStories := new(mocks.StoryRepositoryMock)
+		mockChapters := new(mocks.ChapterRepositoryMock)
+		uc := usecase.NewPackageUseCase(cfg, mockStories, mockChapters, nil, storage, nil, nil, nil)
+
+		mockStories.On("GetByUUID", ctx, packageStoryUUID).Return(story, nil)
+		mockChapters.On("GetAllByStoryID", ctx, uint(1)).Return([]domain.Chapter{}, nil)
+
+		_, err := uc.Import(ctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+		mockStories.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("referenced file missing", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.NoError(t, err)
+		var broken bytes.Buffer
+		zw := zip.NewWriter(&broken)
+		for _, f := range zr.File {
+			if f.Name == "audio/a.m4a" {
+				continue
+			}
+			require.NoError(t, zw.Copy(f))
+		}
+		require.NoError(t, zw.Close())
+
+		uc := usecase.NewPackageUseCase(cfg, nil, nil, nil, storage, nil, nil, nil)
+		_, err = uc.Import(ctx, bytes.NewReader(broken.Bytes()), int64(broken.Len()))
+
+		var pkgErr *domain.InvalidPackageError
+		require.ErrorAs(t, err, &pkgErr)
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		assert.Contains(t, pkgErr.Reason, "audio/a.m4a")
+	})
+
+	t.Run("size limits", func(t *testing.T) {
+		zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+		require.NoError(t, err)
+		var large bytes.Buffer
+		zw := zip.NewWriter(&large)
+		for _, f := range zr.File {
+			require.NoError(t, zw.Copy(f))
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "images/large.jpg", Method: zip.Store})
+		require.NoError(t, err)
+		_, err = fw.Write(make([]byte, 2<<20))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		limited := *cfg
+		limited.PackageMaxFileMB = 1
+		uc := usecase.NewPackageUseCase(&limited, nil, nil, nil, storage, nil, nil, nil)
+		_, err = uc.Import(ctx, bytes.NewReader(large.Bytes()), int64(large.Len()))
+
+		var pkgErr *domain.InvalidPackageError
+		require.ErrorAs(t, err, &pkgErr)
+		assert.Contains(t, pkgErr.Reason, "images/large.jpg")
+
+		limited = *cfg
+		limited.PackageMaxSizeMB = 1
+		uc = usecase.NewPackageUseCase(&limited, nil, nil, nil, storage, nil, nil, nil)
+		_, err = uc.Import(ctx, bytes.NewReader(large.Bytes()), int64(large.Len()))
+
+		require.ErrorAs(t, err, &pkgErr)
+		assert.Contains(t, pkgErr.Reason, "package is larger than 1 MB")
+	})
+}
\ No newline at end of file
@@ -2,55 +2,159 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
 	"khalif-stories/internal/domain"
 
 )
 
 type PreferenceUC struct {
-	repo     domain.PreferenceRepository
-	catRepo  domain.CategoryRepository
+	repo        domain.PreferenceRepository
+	catRepo     domain.CategoryRepository
+	recommender domain.RecommendationUseCase
+	cfg         *config.Config
+	jobRepo     domain.JobRepository
+}
+
+func NewPreferenceUseCase(repo domain.PreferenceRepository, catRepo domain.CategoryRepository, recommender domain.RecommendationUseCase, cfg *config.Config, jobRepo domain.JobRepository) *PreferenceUC {
+	return &PreferenceUC{repo: repo, catRepo: catRepo, recommender: recommender, cfg: cfg, jobRepo: jobRepo}
 }
 
-func NewPreferenceUseCase(repo domain.PreferenceRepository, catRepo domain.CategoryRepository) *PreferenceUC {
-	return &PreferenceUC{repo: repo, catRepo: catRepo}
+func (u *PreferenceUC) GetPreferences(ctx context.Context, userID string) (*domain.UserPreferences, error) {
+	return u.repo.GetChoices(ctx, userID)
 }
 
+// SavePreferences mengganti pilihan kategori user. Setiap UUID harus kategori dari
+// section yang sesuai; satu saja yang salah membuat seluruh permintaan ditolak dengan
+// InvalidCategoriesError yang memuat semua UUID yang salah.
 func (u *PreferenceUC) SavePreferences(ctx context.Context, userID string, storyCatUUIDs, dakwahCatUUIDs, hadistCatUUIDs []string) error {
-	if err := u.repo.ClearChoices(ctx, userID); err != nil {
+	invalid := &domain.InvalidCategoriesError{}
+	choices, err := u.resolve(ctx, domain.PreferenceSelection{Story: storyCatUUIDs, Dakwah: dakwahCatUUIDs, Hadist: hadistCatUUIDs}, invalid)
+	if err != nil {
+		return err
+	}
+	if err := invalid.OrNil(); err != nil {
 		return err
 	}
 
-	var storyChoices []domain.UserChoiceStory
-	for _, uuid := range storyCatUUIDs {
-		if cat, _ := u.catRepo.GetByUUID(ctx, uuid); cat != nil {
-			storyChoices = append(storyChoices, domain.UserChoiceStory{UserID: userID, CategoryID: cat.ID})
-		}
+	if err := u.repo.ReplaceChoices(ctx, userID, choices); err != nil {
+		return err
 	}
 
-	var dakwahChoices []domain.UserChoiceDakwah
-	for _, uuid := range dakwahCatUUIDs {
-		if cat, _ := u.catRepo.GetByUUID(ctx, uuid); cat != nil {
-			dakwahChoices = append(dakwahChoices, domain.UserChoiceDakwah{UserID: userID, CategoryID: cat.ID})
-		}
+	return u.refreshRecommendations(ctx, userID)
+}
+
+// UpdatePreferences menambah dan menghapus kategori tertentu tanpa menyentuh pilihan
+// lain. Penghapusan diproses lebih dulu, sehingga kategori bisa ditukar dalam satu
+// permintaan meski section sudah penuh.
+func (u *PreferenceUC) UpdatePreferences(ctx context.Context, userID string, add, remove domain.PreferenceSelection) (*domain.UserPreferences, error) {
+	invalid := &domain.InvalidCategoriesError{}
+	addIDs, err := u.resolve(ctx, add, invalid)
+	if err != nil {
+		return nil, err
+	}
+	removeIDs, err := u.resolve(ctx, remove, invalid)
+	if err != nil {
+		return nil, err
+	}
+	if err := invalid.OrNil(); err != nil {
+		return nil, err
 	}
 
-	var hadistChoices []domain.UserChoiceHadist
-	for _, uuid := range hadistCatUUIDs {
-		if cat, _ := u.catRepo.GetByUUID(ctx, uuid); cat != nil {
-			hadistChoices = append(hadistChoices, domain.UserChoiceHadist{UserID: userID, CategoryID: cat.ID})
-		}
+	if err := u.repo.UpdateChoices(ctx, userID, addIDs, removeIDs, domain.MaxPreferenceChoices); err != nil {
+		return nil, err
 	}
 
-	if err := u.repo.SaveStoryChoices(ctx, storyChoices); err != nil {
-		return err
+	if err := u.refreshRecommendations(ctx, userID); err != nil {
+		return nil, err
 	}
-	if err := u.repo.SaveDakwahChoices(ctx, dakwahChoices); err != nil {
-		return err
+	return u.repo.GetChoices(ctx, userID)
+}
+
+// Rekomendasi dihitung ulang agar langsung mengikuti preferensi baru. Jika gagal,
+// perhitungan diserahkan ke antrean job supaya dicoba lagi oleh worker.
+func (u *PreferenceUC) refreshRecommendations(ctx context.Context, userID string) error {
+	if u.recommender == nil {
+		return nil
 	}
-	if err := u.repo.SaveHadistChoices(ctx, hadistChoices); err != nil {
+	err := u.recommender.GenerateForUser(ctx, userID)
+	if err == nil || u.jobRepo == nil {
 		return err
 	}
-
+	if _, qerr := enqueueJob(ctx, u.jobRepo, u.cfg, domain.JobTypeRecommendations, domain.RecommendationJob{UserID: userID}); qerr != nil {
+		return errors.Join(err, qerr)
+	}
 	return nil
+}
+
+// resolve memvalidasi ketiga section sekaligus; UUID yang salah dikumpulkan ke invalid
+// agar semuanya bisa dilaporkan dalam satu respons.
+func (u *PreferenceUC) resolve(ctx context.Context, sel domain.PreferenceSelection, invalid *domain.InvalidCategoriesError) (domain.PreferenceChoices, error) {
+	var choices domain.PreferenceChoices
+	var bad []string
+	var err error
+
+	if choices.Story, bad, err = u.resolveSection(ctx, sel.Story, domain.CategoryTypeStory); err != nil {
+		return choices, err
+	}
+	invalid.Story = append(invalid.Story, bad...)
+
+	if choices.Dakwah, bad, err = u.resolveSection(ctx, sel.Dakwah, domain.CategoryTypeDakwah); err != nil {
+		return choices, err
+	}
+	invalid.Dakwah = append(invalid.Dakwah, bad...)
+
+	if choices.Hadist, bad, err = u.resolveSection(ctx, sel.Hadist, domain.CategoryTypeHadist); err != nil {
+		return choices, err
+	}
+	invalid.Hadist = append(invalid.Hadist, bad...)
+
+	return choices, nil
+}
+
+// resolveSection mengubah UUID kategori menjadi ID, UUID ganda diabaikan. UUID yang
+// tidak valid, tidak ada, atau milik section lain dikembalikan apa adanya di invalid.
+func (u *PreferenceUC) resolveSection(ctx context.Context, categoryUUIDs []string, categoryType string) (ids []uint, invalid []string, err error) {
+	seen := make(map[string]bool, len(categoryUUIDs))
+	unique := make([]string, 0, len(categoryUUIDs))
+	original := make(map[string]string, len(categoryUUIDs))
+	for _, raw := range categoryUUIDs {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			invalid = append(invalid, raw)
+			continue
+		}
+		if id := parsed.String(); !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+			original[id] = raw
+		}
+	}
+	if len(unique) == 0 {
+		return nil, invalid, nil
+	}
+
+	categories, err := u.catRepo.GetByUUIDs(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byUUID := make(map[string]domain.Category, len(categories))
+	for _, c := range categories {
+		byUUID[c.UUID] = c
+	}
+
+	ids = make([]uint, 0, len(unique))
+	for _, id := range unique {
+		c, ok := byUUID[id]
+		if !ok || c.Type != categoryType {
+			invalid = append(invalid, original[id])
+			continue
+		}
+		ids = append(ids, c.ID)
+	}
+	return ids, invalid, nil
 }
\ No newline at end of file
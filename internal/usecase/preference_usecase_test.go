@@ -0,0 +1,174 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestPreferenceUseCase_SavePreferences(t *testing.T) {
+	ctx := context.TODO()
+	kisahNabi := "9a4f3c52-6b8e-4c39-9d7e-0b7f2f0d1a11"
+	akhlak := "1d6a2f44-9b1e-4d3c-8a0f-5e2c7b9d4e22"
+
+	t.Run("category from another section", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{kisahNabi}).
+			Return([]domain.Category{{ID: 1, UUID: kisahNabi, Type: domain.CategoryTypeStory}}, nil)
+		mockCatRepo.On("GetByUUIDs", ctx, []string{akhlak}).
+			Return([]domain.Category{{ID: 2, UUID: akhlak, Type: domain.CategoryTypeStory}}, nil)
+
+		err := uc.SavePreferences(ctx, "user-1", []string{kisahNabi}, []string{akhlak}, nil)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		var invalid *domain.InvalidCategoriesError
+		assert.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []string{akhlak}, invalid.Dakwah)
+		assert.Empty(t, invalid.Story)
+		mockRepo.AssertNotCalled(t, "ReplaceChoices", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{kisahNabi}).Return([]domain.Category{}, nil)
+
+		err := uc.SavePreferences(ctx, "user-1", []string{kisahNabi, "bukan-uuid"}, nil, nil)
+
+		var invalid *domain.InvalidCategoriesError
+		assert.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []string{"bukan-uuid", kisahNabi}, invalid.Story)
+		mockRepo.AssertNotCalled(t, "ReplaceChoices", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("valid choices with duplicates", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{akhlak}).
+			Return([]domain.Category{{ID: 2, UUID: akhlak, Type: domain.CategoryTypeDakwah}}, nil)
+		mockRepo.On("ReplaceChoices", ctx, "user-1", domain.PreferenceChoices{Dakwah: []uint{2}}).Return(nil)
+
+		err := uc.SavePreferences(ctx, "user-1", nil, []string{akhlak, akhlak}, nil)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPreferenceUseCase_UpdatePreferences(t *testing.T) {
+	ctx := context.TODO()
+	kisahNabi := "9a4f3c52-6b8e-4c39-9d7e-0b7f2f0d1a11"
+	sahabat := "3e8b1d27-4c6a-4f0e-b2d9-7a1c5e3f6b33"
+
+	t.Run("add and remove", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{sahabat}).
+			Return([]domain.Category{{ID: 3, UUID: sahabat, Type: domain.CategoryTypeStory}}, nil)
+		mockCatRepo.On("GetByUUIDs", ctx, []string{kisahNabi}).
+			Return([]domain.Category{{ID: 1, UUID: kisahNabi, Type: domain.CategoryTypeStory}}, nil)
+		mockRepo.On("UpdateChoices", ctx, "user-1",
+			domain.PreferenceChoices{Story: []uint{3}}, domain.PreferenceChoices{Story: []uint{1}}, domain.MaxPreferenceChoices).Return(nil)
+		mockRepo.On("GetChoices", ctx, "user-1").
+			Return(&domain.UserPreferences{StoryCategories: []domain.Category{{ID: 3, UUID: sahabat}}}, nil)
+
+		prefs, err := uc.UpdatePreferences(ctx, "user-1",
+			domain.PreferenceSelection{Story: []string{sahabat}}, domain.PreferenceSelection{Story: []string{kisahNabi}})
+
+		assert.NoError(t, err)
+		assert.Len(t, prefs.StoryCategories, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown category to remove", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{kisahNabi}).Return([]domain.Category{}, nil)
+
+		_, err := uc.UpdatePreferences(ctx, "user-1", domain.PreferenceSelection{}, domain.PreferenceSelection{Hadist: []string{kisahNabi}})
+
+		var invalid *domain.InvalidCategoriesError
+		assert.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []string{kisahNabi}, invalid.Hadist)
+		mockRepo.AssertNotCalled(t, "UpdateChoices", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("section full", func(t *testing.T) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		uc := usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, nil, nil, nil)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{sahabat}).
+			Return([]domain.Category{{ID: 3, UUID: sahabat, Type: domain.CategoryTypeStory}}, nil)
+		mockRepo.On("UpdateChoices", ctx, "user-1", mock.Anything, mock.Anything, domain.MaxPreferenceChoices).Return(domain.ErrTooManyChoices)
+
+		_, err := uc.UpdatePreferences(ctx, "user-1", domain.PreferenceSelection{Story: []string{sahabat}}, domain.PreferenceSelection{})
+
+		assert.ErrorIs(t, err, domain.ErrTooManyChoices)
+	})
+}
+
+func TestPreferenceUseCase_RefreshRecommendations(t *testing.T) {
+	ctx := context.TODO()
+	akhlak := "1d6a2f44-9b1e-4d3c-8a0f-5e2c7b9d4e22"
+	cfg := &config.Config{JobMaxAttempts: 3}
+
+	setup := func() (*mocks.PreferenceRepositoryMock, *mocks.RecommendationUseCaseMock, *mocks.JobRepositoryMock, *usecase.PreferenceUC) {
+		mockRepo := new(mocks.PreferenceRepositoryMock)
+		mockCatRepo := new(mocks.CategoryRepositoryMock)
+		recommender := new(mocks.RecommendationUseCaseMock)
+		jobs := new(mocks.JobRepositoryMock)
+
+		mockCatRepo.On("GetByUUIDs", ctx, []string{akhlak}).
+			Return([]domain.Category{{ID: 2, UUID: akhlak, Type: domain.CategoryTypeDakwah}}, nil)
+		mockRepo.On("ReplaceChoices", ctx, "user-1", domain.PreferenceChoices{Dakwah: []uint{2}}).Return(nil)
+		return mockRepo, recommender, jobs, usecase.NewPreferenceUseCase(mockRepo, mockCatRepo, recommender, cfg, jobs)
+	}
+
+	t.Run("failed refresh is queued", func(t *testing.T) {
+		_, recommender, jobs, uc := setup()
+		recommender.On("GenerateForUser", ctx, "user-1").Return(errors.New("timeout"))
+		jobs.On("Enqueue", ctx, mock.Anything).Return(nil)
+
+		err := uc.SavePreferences(ctx, "user-1", nil, []string{akhlak}, nil)
+
+		assert.NoError(t, err)
+		job := jobs.Calls[0].Arguments.Get(1).(*domain.Job)
+		assert.Equal(t, domain.JobTypeRecommendations, job.Type)
+		var payload domain.RecommendationJob
+		assert.NoError(t, json.Unmarshal(job.Payload, &payload))
+		assert.Equal(t, "user-1", payload.UserID)
+	})
+
+	t.Run("queue unavailable", func(t *testing.T) {
+		_, recommender, jobs, uc := setup()
+		recommender.On("GenerateForUser", ctx, "user-1").Return(errors.New("timeout"))
+		jobs.On("Enqueue", ctx, mock.Anything).Return(errors.New("connection refused"))
+
+		err := uc.SavePreferences(ctx, "user-1", nil, []string{akhlak}, nil)
+
+		assert.ErrorContains(t, err, "timeout")
+		assert.ErrorContains(t, err, "connection refused")
+	})
+}
\ No newline at end of file
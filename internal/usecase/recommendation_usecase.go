@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+const (
+	recommendationsPerUser = 20
+	recommendationsShown   = 10
+
+	// Bobot skor, total 1.0 untuk user yang punya data
+	weightPreference = 0.4
+	weightAffinity   = 0.3
+	weightRecency    = 0.15
+	weightPopularity = 0.1
+	weightFavourites = 0.05
+
+	// Story yang sudah pernah didengar tetap boleh muncul, tapi skornya diturunkan
+	listenedPenalty = 0.5
+
+	recencyHalfLifeDays = 30.0
+	popularityWindow    = 30 * 24 * time.Hour
+)
+
+type RecommendationUC struct {
+	cfg     *config.Config
+	repo    domain.RecommendationRepository
+	jobRepo domain.JobRepository
+	tx      domain.Transactor
+}
+
+func NewRecommendationUseCase(cfg *config.Config, repo domain.RecommendationRepository, jobRepo domain.JobRepository, tx domain.Transactor) *RecommendationUC {
+	return &RecommendationUC{cfg: cfg, repo: repo, jobRepo: jobRepo, tx: tx}
+}
+
+// GetForUser membaca rekomendasi yang tersimpan. Bila belum ada (user baru atau job
+// belum berjalan), perhitungannya diantrekan sekali per user dan daftar kosong
+// dikembalikan. User yang hasil hitungnya memang kosong tidak diantrekan lagi, job
+// berkala yang akan menghitungnya ulang.
+func (u *RecommendationUC) GetForUser(ctx context.Context, userID string) ([]domain.Recommendation, error) {
+	recs, err := u.repo.GetForUser(ctx, userID, recommendationsShown)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) > 0 {
+		return recs, nil
+	}
+
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		first, err := u.repo.MarkRequested(ctx, userID)
+		if err != nil || !first {
+			return err
+		}
+		_, err = enqueueJob(ctx, u.jobRepo, u.cfg, domain.JobTypeRecommendations, domain.RecommendationJob{UserID: userID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+func (u *RecommendationUC) GenerateForUser(ctx context.Context, userID string) error {
+	inputs, err := u.loadGlobalInputs(ctx)
+	if err != nil {
+		return err
+	}
+	return u.generate(ctx, userID, inputs, time.Now())
+}
+
+// GenerateAll menghitung ulang rekomendasi semua user aktif dan mengembalikan jumlah
+// user yang berhasil. Kegagalan satu user tidak menghentikan user lain, semuanya
+// digabung dengan errors.Join. Jika replika lain sedang menjalankannya, tidak ada
+// yang dikerjakan.
+func (u *RecommendationUC) GenerateAll(ctx context.Context) (int, error) {
+	var generated int
+	var failures []error
+	err := u.repo.WithLeaderLock(ctx, func() error {
+		userIDs, err := u.repo.GetActiveUserIDs(ctx)
+		if err != nil {
+			return err
+		}
+
+		inputs, err := u.loadGlobalInputs(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, userID := range userIDs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := u.generate(ctx, userID, inputs, now); err != nil {
+				failures = append(failures, fmt.Errorf("user %s: %w", userID, err))
+				continue
+			}
+			generated++
+		}
+		return nil
+	})
+	if err != nil {
+		return generated, err
+	}
+	return generated, errors.Join(failures...)
+}
+
+// Handle menjalankan job JobTypeRecommendations.
+func (u *RecommendationUC) Handle(ctx context.Context, payload domain.JobPayload) error {
+	var job domain.RecommendationJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	return u.GenerateForUser(ctx, job.UserID)
+}
+
+// Dead tidak perlu membereskan apa pun, job berkala akan menghitung ulang user ini.
+func (u *RecommendationUC) Dead(ctx context.Context, payload domain.JobPayload, cause error) error {
+	return nil
+}
+
+// globalInputs adalah data yang sama untuk semua user dalam satu kali perhitungan.
+type globalInputs struct {
+	candidates []domain.Story
+	popularity map[uint]int
+	favourites map[uint]int
+}
+
+func (u *RecommendationUC) loadGlobalInputs(ctx context.Context) (globalInputs, error) {
+	var inputs globalInputs
+	var err error
+
+	if inputs.candidates, err = u.repo.GetCandidateStories(ctx); err != nil {
+		return inputs, err
+	}
+	if inputs.popularity, err = u.repo.GetCategoryPopularity(ctx, time.Now().Add(-popularityWindow)); err != nil {
+		return inputs, err
+	}
+	if inputs.favourites, err = u.repo.GetStoryFavourites(ctx); err != nil {
+		return inputs, err
+	}
+	return inputs, nil
+}
+
+func (u *RecommendationUC) generate(ctx context.Context, userID string, inputs globalInputs, now time.Time) error {
+	signals, err := u.repo.GetUserSignals(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	recs := scoreStories(userID, *signals, inputs, now)
+	return u.repo.ReplaceForUser(ctx, userID, recs)
+}
+
+// scoreStories memberi skor setiap kandidat lalu mengambil yang tertinggi.
+// User tanpa preferensi dan riwayat (cold start) hanya dinilai dari popularitas
+// kategori, jumlah favorit dan kebaruan story.
+func scoreStories(userID string, signals domain.UserSignals, inputs globalInputs, now time.Time) []domain.Recommendation {
+	candidates, popularity := inputs.candidates, inputs.popularity
+
+	maxPopularity := 0
+	for _, n := range popularity {
+		maxPopularity = max(maxPopularity, n)
+	}
+
+	// Jumlah favorit diskalakan logaritmik agar beberapa story viral tidak menenggelamkan yang lain
+	maxFavourites := 0
+	for _, n := range inputs.favourites {
+		maxFavourites = max(maxFavourites, n)
+	}
+
+	totalListening := 0
+	for _, seconds := range signals.CategoryListening {
+		totalListening += seconds
+	}
+
+	coldStart := signals.IsEmpty()
+
+	recs := make([]domain.Recommendation, 0, len(candidates))
+	for _, story := range candidates {
+		ageDays := math.Max(now.Sub(story.CreatedAt).Hours()/24, 0)
+		recency := math.Exp(-ageDays * math.Ln2 / recencyHalfLifeDays)
+
+		pop := 0.0
+		if maxPopularity > 0 {
+			pop = float64(popularity[story.CategoryID]) / float64(maxPopularity)
+		}
+
+		fav := 0.0
+		if maxFavourites > 0 {
+			fav = math.Log1p(float64(inputs.favourites[story.ID])) / math.Log1p(float64(maxFavourites))
+		}
+
+		var score float64
+		if coldStart {
+			score = 0.5*pop + 0.3*recency + 0.2*fav
+		} else {
+			pref := 0.0
+			if signals.PreferredCategories[story.CategoryID] {
+				pref = 1
+			}
+
+			affinity := 0.0
+			if totalListening > 0 {
+				affinity = float64(signals.CategoryListening[story.CategoryID]) / float64(totalListening)
+			}
+
+			score = weightPreference*pref + weightAffinity*affinity + weightRecency*recency + weightPopularity*pop + weightFavourites*fav
+			if signals.ListenedStories[story.ID] {
+				score *= listenedPenalty
+			}
+		}
+
+		recs = append(recs, domain.Recommendation{
+			UserID:  userID,
+			StoryID: story.ID,
+			Score:   math.Round(score*10000) / 10000,
+		})
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		return recs[i].StoryID > recs[j].StoryID
+	})
+
+	if len(recs) > recommendationsPerUser {
+		recs = recs[:recommendationsPerUser]
+	}
+	return recs
+}
\ No newline at end of file
@@ -0,0 +1,176 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestRecommendationUseCase_GenerateForUser(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+
+	candidates := []domain.Story{
+		{ID: 1, CategoryID: 10, CreatedAt: now.Add(-90 * 24 * time.Hour)},
+		{ID: 2, CategoryID: 20, CreatedAt: now},
+		{ID: 3, CategoryID: 10, CreatedAt: now},
+	}
+
+	t.Run("preferred category ranks first", func(t *testing.T) {
+		mockRepo := new(mocks.RecommendationRepositoryMock)
+		uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, nil, nil)
+
+		signals := &domain.UserSignals{
+			PreferredCategories: map[uint]bool{10: true},
+			CategoryListening:   map[uint]int{},
+			ListenedStories:     map[uint]bool{3: true},
+		}
+
+		var saved []domain.Recommendation
+		mockRepo.On("GetCandidateStories", ctx).Return(candidates, nil)
+		mockRepo.On("GetCategoryPopularity", ctx, mock.Anything).Return(map[uint]int{20: 5}, nil)
+		mockRepo.On("GetStoryFavourites", ctx).Return(map[uint]int{}, nil)
+		mockRepo.On("GetUserSignals", ctx, "user-1").Return(signals, nil)
+		mockRepo.On("ReplaceForUser", ctx, "user-1", mock.Anything).
+			Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.Recommendation) }).
+			Return(nil)
+
+		err := uc.GenerateForUser(ctx, "user-1")
+
+		assert.NoError(t, err)
+		assert.Len(t, saved, 3)
+		assert.Equal(t, uint(1), saved[0].StoryID)
+	})
+
+	t.Run("cold start falls back to popularity and recency", func(t *testing.T) {
+		mockRepo := new(mocks.RecommendationRepositoryMock)
+		uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, nil, nil)
+
+		var saved []domain.Recommendation
+		mockRepo.On("GetCandidateStories", ctx).Return(candidates, nil)
+		mockRepo.On("GetCategoryPopularity", ctx, mock.Anything).Return(map[uint]int{20: 5}, nil)
+		mockRepo.On("GetStoryFavourites", ctx).Return(map[uint]int{}, nil)
+		mockRepo.On("GetUserSignals", ctx, "new-user").Return(&domain.UserSignals{}, nil)
+		mockRepo.On("ReplaceForUser", ctx, "new-user", mock.Anything).
+			Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.Recommendation) }).
+			Return(nil)
+
+		err := uc.GenerateForUser(ctx, "new-user")
+
+		assert.NoError(t, err)
+		assert.Len(t, saved, 3)
+		assert.Equal(t, uint(2), saved[0].StoryID)
+		assert.Equal(t, uint(1), saved[2].StoryID)
+	})
+}
+
+func TestRecommendationUseCase_FavouritesBreakTies(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+	mockRepo := new(mocks.RecommendationRepositoryMock)
+	uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, nil, nil)
+
+	var saved []domain.Recommendation
+	mockRepo.On("GetCandidateStories", ctx).Return([]domain.Story{
+		{ID: 1, CategoryID: 10, CreatedAt: now},
+		{ID: 2, CategoryID: 10, CreatedAt: now},
+	}, nil)
+	mockRepo.On("GetCategoryPopularity", ctx, mock.Anything).Return(map[uint]int{10: 3}, nil)
+	mockRepo.On("GetStoryFavourites", ctx).Return(map[uint]int{1: 12}, nil)
+	mockRepo.On("GetUserSignals", ctx, "new-user").Return(&domain.UserSignals{}, nil)
+	mockRepo.On("ReplaceForUser", ctx, "new-user", mock.Anything).
+		Run(func(args mock.Arguments) { saved = args.Get(2).([]domain.Recommendation) }).
+		Return(nil)
+
+	err := uc.GenerateForUser(ctx, "new-user")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), saved[0].StoryID)
+	assert.Greater(t, saved[0].Score, saved[1].Score)
+}
+
+func TestRecommendationUseCase_GenerateAll(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("failing user does not stop the others", func(t *testing.T) {
+		mockRepo := new(mocks.RecommendationRepositoryMock)
+		uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, nil, nil)
+
+		mockRepo.On("WithLeaderLock", ctx).Return(true, nil)
+		mockRepo.On("GetActiveUserIDs", ctx).Return([]string{"user-1", "user-2", "user-3"}, nil)
+		mockRepo.On("GetCandidateStories", ctx).Return([]domain.Story{{ID: 1, CategoryID: 10}}, nil)
+		mockRepo.On("GetCategoryPopularity", ctx, mock.Anything).Return(map[uint]int{}, nil)
+		mockRepo.On("GetStoryFavourites", ctx).Return(map[uint]int{}, nil)
+		mockRepo.On("GetUserSignals", ctx, "user-1").Return(&domain.UserSignals{}, nil)
+		mockRepo.On("GetUserSignals", ctx, "user-2").Return(nil, errors.New("timeout"))
+		mockRepo.On("GetUserSignals", ctx, "user-3").Return(&domain.UserSignals{}, nil)
+		mockRepo.On("ReplaceForUser", ctx, mock.Anything, mock.Anything).Return(nil)
+
+		users, err := uc.GenerateAll(ctx)
+
+		assert.Equal(t, 2, users)
+		assert.ErrorContains(t, err, "user user-2: timeout")
+		mockRepo.AssertCalled(t, "ReplaceForUser", ctx, "user-3", mock.Anything)
+	})
+
+	t.Run("another replica holds the lock", func(t *testing.T) {
+		mockRepo := new(mocks.RecommendationRepositoryMock)
+		uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, nil, nil)
+
+		mockRepo.On("WithLeaderLock", ctx).Return(false, nil)
+
+		users, err := uc.GenerateAll(ctx)
+
+		assert.NoError(t, err)
+		assert.Zero(t, users)
+		mockRepo.AssertNotCalled(t, "GetActiveUserIDs", mock.Anything)
+	})
+}
+
+func TestRecommendationUseCase_GetForUser(t *testing.T) {
+	ctx := context.TODO()
+
+	tests := []struct {
+		name   string
+		first  bool
+		queued bool
+	}{
+		{"first request queues a job", true, true},
+		{"already requested", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.RecommendationRepositoryMock)
+			mockJobs := new(mocks.JobRepositoryMock)
+			uc := usecase.NewRecommendationUseCase(&config.Config{}, mockRepo, mockJobs, nil)
+
+			mockRepo.On("GetForUser", ctx, "user-1", 10).Return([]domain.Recommendation{}, nil)
+			mockRepo.On("MarkRequested", ctx, "user-1").Return(tt.first, nil)
+			mockJobs.On("Enqueue", ctx, mock.AnythingOfType("*domain.Job")).Return(nil)
+
+			recs, err := uc.GetForUser(ctx, "user-1")
+
+			assert.NoError(t, err)
+			assert.Empty(t, recs)
+			mockRepo.AssertNotCalled(t, "GetUserSignals", mock.Anything, mock.Anything)
+			if tt.queued {
+				mockJobs.AssertCalled(t, "Enqueue", ctx, mock.MatchedBy(func(j *domain.Job) bool {
+					return j.Type == domain.JobTypeRecommendations
+				}))
+			} else {
+				mockJobs.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+
+)
+
+const maxReviewLength = 2000
+
+type ReviewUC struct {
+	cfg       *config.Config
+	repo      domain.ReviewRepository
+	storyRepo domain.StoryRepository
+	redisRepo domain.RedisRepository
+	filter    domain.ContentFilter
+}
+
+func NewReviewUseCase(cfg *config.Config, repo domain.ReviewRepository, storyRepo domain.StoryRepository, redisRepo domain.RedisRepository, filter domain.ContentFilter) *ReviewUC {
+	return &ReviewUC{cfg: cfg, repo: repo, storyRepo: storyRepo, redisRepo: redisRepo, filter: filter}
+}
+
+func (u *ReviewUC) publishedStory(ctx context.Context, storyUUID string) (*domain.Story, error) {
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil || story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+	return story, nil
+}
+
+// Submit membuat atau mengganti ulasan user. Rating tanpa teks langsung approved;
+// ulasan berteks menunggu moderasi kecuali REVIEW_AUTO_APPROVE aktif dan teksnya lolos
+// filter. Mengubah rating saja tanpa mengubah teks tidak mengembalikan ulasan ke antrean.
+func (u *ReviewUC) Submit(ctx context.Context, userID, storyUUID string, rating int, body string) (*domain.Review, error) {
+	body = strings.TrimSpace(body)
+	if rating < 1 || rating > 5 || utf8.RuneCountInString(body) > maxReviewLength {
+		return nil, domain.ErrBadParamInput
+	}
+
+	story, err := u.publishedStory(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := u.repo.GetByUser(ctx, story.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Body == body {
+		existing.Rating = rating
+		if err := u.repo.Update(ctx, existing); err != nil {
+			return nil, err
+		}
+		u.invalidate(ctx)
+		return existing, nil
+	}
+
+	review := &domain.Review{
+		UUID:    uuid.New().String(),
+		StoryID: story.ID,
+		UserID:  userID,
+		Rating:  rating,
+		Body:    body,
+		Status:  domain.ReviewStatusPending,
+	}
+	if body == "" {
+		review.Status = domain.ReviewStatusApproved
+	} else {
+		var flagged []string
+		if u.filter != nil {
+			flagged = u.filter.Match(body)
+		}
+		review.FlaggedTerms = strings.Join(flagged, ",")
+		if len(flagged) == 0 && u.cfg.ReviewAutoApprove {
+			review.Status = domain.ReviewStatusApproved
+		}
+	}
+
+	if err := u.repo.Upsert(ctx, review); err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx)
+	return review, nil
+}
+
+func (u *ReviewUC) GetMine(ctx context.Context, userID, storyUUID string) (*domain.Review, error) {
+	story, err := u.publishedStory(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	review, err := u.repo.GetByUser(ctx, story.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, domain.ErrNotFound
+	}
+	return review, nil
+}
+
+func (u *ReviewUC) Delete(ctx context.Context, userID, storyUUID string) error {
+	review, err := u.GetMine(ctx, userID, storyUUID)
+	if err != nil {
+		return err
+	}
+	if err := u.repo.Delete(ctx, review.ID); err != nil {
+		return err
+	}
+	u.invalidate(ctx)
+	return nil
+}
+
+func (u *ReviewUC) ListForStory(ctx context.Context, storyUUID string, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	story, err := u.publishedStory(ctx, storyUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u.repo.ListByStory(ctx, story.ID, q)
+}
+
+func (u *ReviewUC) List(ctx context.Context, q domain.ListQuery) ([]domain.Review, *domain.PageInfo, error) {
+	return u.repo.List(ctx, q)
+}
+
+// Moderate memindahkan status ulasan sesuai ReviewTransitions. Alasan wajib diisi saat
+// menolak atau menyembunyikan ulasan.
+func (u *ReviewUC) Moderate(ctx context.Context, reviewUUID, status, reason string) (*domain.Review, error) {
+	reason = strings.TrimSpace(reason)
+	if (status == domain.ReviewStatusRejected || status == domain.ReviewStatusHidden) && reason == "" {
+		return nil, domain.ErrBadParamInput
+	}
+
+	review, err := u.repo.GetByUUID(ctx, reviewUUID)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, domain.ErrNotFound
+	}
+	if err := domain.CheckReviewTransition(review.Status, status); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Status = status
+	review.ModerationReason = reason
+	review.ModeratedBy = domain.ActorFromContext(ctx)
+	review.ModeratedAt = &now
+	if err := u.repo.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx)
+	return review, nil
+}
+
+// invalidate membuang cache listing story karena rating_average ikut berubah.
+func (u *ReviewUC) invalidate(ctx context.Context) {
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+}
\ No newline at end of file
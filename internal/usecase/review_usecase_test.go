@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
+
+)
+
+func TestReviewUseCase_Submit(t *testing.T) {
+	ctx := context.TODO()
+	story := &domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusPublished}
+	filter := utils.NewKeywordFilter([]string{"bodoh"})
+
+	t.Run("rating out of range", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, nil, nil, filter)
+
+		_, err := uc.Submit(ctx, "user-1", "s-1", 6, "")
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("rating only is approved", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, mockStories, nil, filter)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(story, nil)
+		mockRepo.On("GetByUser", ctx, uint(1), "user-1").Return(nil, nil)
+		mockRepo.On("Upsert", ctx, mock.AnythingOfType("*domain.Review")).Return(nil)
+
+		res, err := uc.Submit(ctx, "user-1", "s-1", 4, "  ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusApproved, res.Status)
+		assert.Equal(t, 4, res.Rating)
+	})
+
+	t.Run("flagged text waits for moderation", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{ReviewAutoApprove: true}, mockRepo, mockStories, nil, filter)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(story, nil)
+		mockRepo.On("GetByUser", ctx, uint(1), "user-1").Return(nil, nil)
+		mockRepo.On("Upsert", ctx, mock.AnythingOfType("*domain.Review")).Return(nil)
+
+		res, err := uc.Submit(ctx, "user-1", "s-1", 1, "Cerita yang BODOH")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusPending, res.Status)
+		assert.Equal(t, "bodoh", res.FlaggedTerms)
+	})
+
+	t.Run("clean text is auto approved when enabled", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{ReviewAutoApprove: true}, mockRepo, mockStories, nil, filter)
+
+		mockStories.On("GetByUUID", ctx, "s-1").Return(story, nil)
+		mockRepo.On("GetByUser", ctx, uint(1), "user-1").Return(nil, nil)
+		mockRepo.On("Upsert", ctx, mock.AnythingOfType("*domain.Review")).Return(nil)
+
+		res, err := uc.Submit(ctx, "user-1", "s-1", 5, "Kisah yang indah")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusApproved, res.Status)
+		assert.Empty(t, res.FlaggedTerms)
+	})
+
+	t.Run("same text keeps moderation status", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		mockStories := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, mockStories, nil, filter)
+
+		existing := &domain.Review{ID: 9, StoryID: 1, UserID: "user-1", Rating: 2, Body: "Bagus", Status: domain.ReviewStatusApproved}
+		mockStories.On("GetByUUID", ctx, "s-1").Return(story, nil)
+		mockRepo.On("GetByUser", ctx, uint(1), "user-1").Return(existing, nil)
+		mockRepo.On("Update", ctx, existing).Return(nil)
+
+		res, err := uc.Submit(ctx, "user-1", "s-1", 5, "Bagus")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusApproved, res.Status)
+		assert.Equal(t, 5, res.Rating)
+		mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReviewUseCase_Moderate(t *testing.T) {
+	ctx := domain.WithActor(context.TODO(), "admin-1")
+
+	t.Run("reason required to reject", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, nil, nil, nil)
+
+		_, err := uc.Moderate(ctx, "r-1", domain.ReviewStatusRejected, " ")
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+		mockRepo.AssertNotCalled(t, "GetByUUID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "r-1").Return(&domain.Review{ID: 3, Status: domain.ReviewStatusPending}, nil)
+
+		_, err := uc.Moderate(ctx, "r-1", domain.ReviewStatusHidden, "spam")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("hide approved review", func(t *testing.T) {
+		mockRepo := new(mocks.ReviewRepositoryMock)
+		uc := usecase.NewReviewUseCase(&config.Config{}, mockRepo, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "r-1").Return(&domain.Review{ID: 3, Status: domain.ReviewStatusApproved}, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Review")).Return(nil)
+
+		res, err := uc.Moderate(ctx, "r-1", domain.ReviewStatusHidden, "spoiler")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusHidden, res.Status)
+		assert.Equal(t, "spoiler", res.ModerationReason)
+		assert.Equal(t, "admin-1", res.ModeratedBy)
+		assert.NotNil(t, res.ModeratedAt)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"khalif-stories/internal/domain"
+
+)
+
+// storySnapshot adalah field Story yang dicatat di revisi. Status dan jadwal ikut
+// dicatat untuk audit tetapi tidak dipulihkan saat rollback, karena status hanya
+// boleh berubah lewat alur editorial.
+type storySnapshot struct {
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	ThumbnailURL  string     `json:"thumbnail_url"`
+	DominantColor string     `json:"dominant_color"`
+	CategoryID    uint       `json:"category_id"`
+	Status        string     `json:"status"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+}
+
+func snapshotStory(s *domain.Story) storySnapshot {
+	return storySnapshot{
+		Title:         s.Title,
+		Description:   s.Description,
+		ThumbnailURL:  s.ThumbnailURL,
+		DominantColor: s.DominantColor,
+		CategoryID:    s.CategoryID,
+		Status:        s.Status,
+		PublishAt:     s.PublishAt,
+		UnpublishAt:   s.UnpublishAt,
+	}
+}
+
+// slideSnapshot mencatat sequence seperti posisi chapter, tetapi rollback tidak
+// memulihkannya karena sequence lama bisa sudah dipakai slide lain.
+type slideSnapshot struct {
+	Content  string `json:"content"`
+	ImageURL string `json:"image_url"`
+	SoundURL string `json:"sound_url"`
+	Sequence int    `json:"sequence"`
+}
+
+func snapshotSlide(s *domain.Slide) slideSnapshot {
+	return slideSnapshot{Content: s.Content, ImageURL: s.ImageURL, SoundURL: s.SoundURL, Sequence: s.Sequence}
+}
+
+// chapterSnapshot juga mencatat posisi dan status, tetapi rollback hanya memulihkan
+// metadata; urutan dan status diubah lewat endpoint masing-masing.
+type chapterSnapshot struct {
+	Title           string `json:"title"`
+	Synopsis        string `json:"synopsis"`
+	CoverURL        string `json:"cover_url"`
+	DurationSeconds int    `json:"duration_seconds"`
+	Position        int    `json:"position"`
+	Status          string `json:"status"`
+}
+
+func snapshotChapter(c *domain.Chapter) chapterSnapshot {
+	return chapterSnapshot{
+		Title:           c.Title,
+		Synopsis:        c.Synopsis,
+		CoverURL:        c.CoverURL,
+		DurationSeconds: c.DurationSeconds,
+		Position:        c.Position,
+		Status:          c.Status,
+	}
+}
+
+// toRevisionFields melewatkan snapshot lewat JSON agar nilainya sama persis dengan
+// yang dibaca kembali dari kolom jsonb (angka menjadi float64, waktu menjadi string).
+func toRevisionFields(snapshot interface{}) domain.RevisionFields {
+	data, _ := json.Marshal(snapshot)
+	fields := domain.RevisionFields{}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func fromRevisionFields(fields domain.RevisionFields, dst interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// recordRevision mencatat perubahan satu entity. Tidak ada yang dicatat jika snapshot
+// sebelum dan sesudah sama.
+func recordRevision(ctx context.Context, repo domain.RevisionRepository, storyID uint, entityType, entityKey, action string, before, after interface{}) error {
+	_, err := saveRevision(ctx, repo, storyID, entityType, entityKey, action, before, after)
+	return err
+}
+
+// saveRevision sama dengan recordRevision tetapi mengembalikan revisi yang tersimpan,
+// nil jika tidak ada perubahan.
+func saveRevision(ctx context.Context, repo domain.RevisionRepository, storyID uint, entityType, entityKey, action string, before, after interface{}) (*domain.Revision, error) {
+	if repo == nil {
+		return nil, nil
+	}
+
+	old, current := toRevisionFields(before), toRevisionFields(after)
+	changes := domain.DiffFields(old, current)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	rev := &domain.Revision{
+		StoryID:    storyID,
+		EntityType: entityType,
+		EntityKey:  entityKey,
+		Action:     action,
+		UserID:     domain.ActorFromContext(ctx),
+		Snapshot:   current,
+		Changes:    changes,
+	}
+	if err := repo.Record(ctx, rev, old); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// inTransaction menjalankan fn lewat tx agar perubahan entity dan revisinya tersimpan
+// bersama. Tanpa tx, fn dijalankan langsung.
+func inTransaction(ctx context.Context, tx domain.Transactor, fn func(ctx context.Context) error) error {
+	if tx == nil {
+		return fn(ctx)
+	}
+	return tx.WithinTransaction(ctx, fn)
+}
+
+type RevisionUC struct {
+	repo        domain.RevisionRepository
+	storyRepo   domain.StoryRepository
+	chapterRepo domain.ChapterRepository
+	redisRepo   domain.RedisRepository
+	tx          domain.Transactor
+}
+
+func NewRevisionUseCase(repo domain.RevisionRepository, storyRepo domain.StoryRepository, chapterRepo domain.ChapterRepository, redisRepo domain.RedisRepository, tx domain.Transactor) *RevisionUC {
+	return &RevisionUC{repo: repo, storyRepo: storyRepo, chapterRepo: chapterRepo, redisRepo: redisRepo, tx: tx}
+}
+
+func (u *RevisionUC) ListByStory(ctx context.Context, storyUUID, entityType string, q domain.ListQuery) ([]domain.Revision, *domain.PageInfo, error) {
+	switch entityType {
+	case "", domain.RevisionEntityStory, domain.RevisionEntityChapter, domain.RevisionEntitySlide:
+	default:
+		return nil, nil, domain.ErrBadParamInput
+	}
+
+	story, err := u.storyRepo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if story == nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	return u.repo.ListByStory(ctx, story.ID, entityType, q)
+}
+
+func (u *RevisionUC) Get(ctx context.Context, id uint) (*domain.Revision, error) {
+	rev, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, domain.ErrNotFound
+	}
+	return rev, nil
+}
+
+// Rollback mengembalikan entity ke snapshot revisi yang dipilih, termasuk URL medianya,
+// lalu mencatat hasilnya sebagai revisi baru sehingga rollback pun bisa dibatalkan. Revisi
+// baru itu yang dikembalikan; jika entity sudah sama dengan snapshot, tidak ada revisi
+// baru dan revisi yang dipilih dikembalikan apa adanya.
+func (u *RevisionUC) Rollback(ctx context.Context, id uint) (*domain.Revision, error) {
+	rev, err := u.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *domain.Revision
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		var err error
+		switch rev.EntityType {
+		case domain.RevisionEntityStory:
+			restored, err = u.rollbackStory(ctx, rev)
+		case domain.RevisionEntityChapter:
+			restored, err = u.rollbackChapter(ctx, rev)
+		case domain.RevisionEntitySlide:
+			restored, err = u.rollbackSlide(ctx, rev)
+		default:
+			err = domain.ErrBadParamInput
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeySuggestPrefix)
+	}
+	if restored == nil {
+		return rev, nil
+	}
+	return restored, nil
+}
+
+func (u *RevisionUC) rollbackStory(ctx context.Context, rev *domain.Revision) (*domain.Revision, error) {
+	var target storySnapshot
+	if err := fromRevisionFields(rev.Snapshot, &target); err != nil {
+		return nil, err
+	}
+
+	story, err := u.storyRepo.GetByUUID(ctx, rev.EntityKey)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
+	before := snapshotStory(story)
+
+	story.Title = target.Title
+	story.Description = target.Description
+	story.ThumbnailURL = target.ThumbnailURL
+	story.DominantColor = target.DominantColor
+	if target.CategoryID != 0 && target.CategoryID != story.CategoryID {
+		story.CategoryID = target.CategoryID
+		story.Category = domain.Category{}
+	}
+	story.UpdatedAt = time.Now()
+
+	// hanya kolom konten yang ditulis, status dan jadwal story tidak ikut di-rollback
+	if err := u.storyRepo.Update(ctx, story); err != nil {
+		return nil, err
+	}
+	if err := u.storyRepo.UpdateColor(ctx, story.ID, story.DominantColor); err != nil {
+		return nil, err
+	}
+	return saveRevision(ctx, u.repo, story.ID, domain.RevisionEntityStory, story.UUID, domain.RevisionActionRollback, before, snapshotStory(story))
+}
+
+func (u *RevisionUC) rollbackChapter(ctx context.Context, rev *domain.Revision) (*domain.Revision, error) {
+	var target chapterSnapshot
+	if err := fromRevisionFields(rev.Snapshot, &target); err != nil {
+		return nil, err
+	}
+
+	chapter, err := u.chapterRepo.GetByUUID(ctx, rev.EntityKey)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil {
+		return nil, domain.ErrNotFound
+	}
+	before := snapshotChapter(chapter)
+
+	chapter.Title = target.Title
+	chapter.Synopsis = target.Synopsis
+	chapter.CoverURL = target.CoverURL
+	chapter.DurationSeconds = target.DurationSeconds
+
+	if err := u.chapterRepo.Update(ctx, chapter); err != nil {
+		return nil, err
+	}
+	return saveRevision(ctx, u.repo, chapter.StoryID, domain.RevisionEntityChapter, chapter.UUID, domain.RevisionActionRollback, before, snapshotChapter(chapter))
+}
+
+func (u *RevisionUC) rollbackSlide(ctx context.Context, rev *domain.Revision) (*domain.Revision, error) {
+	var target slideSnapshot
+	if err := fromRevisionFields(rev.Snapshot, &target); err != nil {
+		return nil, err
+	}
+
+	slideID, err := strconv.ParseUint(rev.EntityKey, 10, 64)
+	if err != nil {
+		return nil, domain.ErrBadParamInput
+	}
+	slide, err := u.storyRepo.GetSlideByID(ctx, uint(slideID))
+	if err != nil {
+		return nil, err
+	}
+	if slide == nil {
+		return nil, domain.ErrNotFound
+	}
+	before := snapshotSlide(slide)
+
+	slide.Content = target.Content
+	slide.ImageURL = target.ImageURL
+	slide.SoundURL = target.SoundURL
+	slide.Status = domain.SlideStatusReady
+
+	if err := u.storyRepo.UpdateSlide(ctx, slide); err != nil {
+		return nil, err
+	}
+	// ErrConflict jika job media slide berganti sejak slide dibaca
+	if err := u.storyRepo.UpdateSlideMedia(ctx, slide); err != nil {
+		return nil, err
+	}
+	if slide.JobID != "" {
+		// job media yang masih antre tidak boleh menimpa hasil rollback
+		slide.JobID = ""
+		if err := u.storyRepo.SetSlideJob(ctx, slide); err != nil {
+			return nil, err
+		}
+	}
+	return saveRevision(ctx, u.repo, rev.StoryID, domain.RevisionEntitySlide, rev.EntityKey, domain.RevisionActionRollback, before, snapshotSlide(slide))
+}
\ No newline at end of file
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/config"
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+
+)
+
+func TestStoryUseCase_UpdateRecordsRevision(t *testing.T) {
+	ctx := domain.WithActor(context.TODO(), "admin-1")
+	mockRepo := new(mocks.StoryRepositoryMock)
+	mockRevisions := new(mocks.RevisionRepositoryMock)
+	uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, mockRevisions, nil, nil, nil)
+
+	mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Title: "Old", Description: "Desc", ThumbnailURL: "thumb-1.jpg"}, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Story")).Return(nil)
+	mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+	_, err := uc.Update(ctx, "s-1", "New", "", "", nil, nil)
+	assert.NoError(t, err)
+
+	rev := mockRevisions.Calls[0].Arguments.Get(1).(*domain.Revision)
+	baseline := mockRevisions.Calls[0].Arguments.Get(2).(domain.RevisionFields)
+	assert.Equal(t, domain.RevisionEntityStory, rev.EntityType)
+	assert.Equal(t, "s-1", rev.EntityKey)
+	assert.Equal(t, "admin-1", rev.UserID)
+	assert.Equal(t, domain.RevisionDiff{"title": {Old: "Old", New: "New"}}, rev.Changes)
+	assert.Equal(t, "thumb-1.jpg", rev.Snapshot["thumbnail_url"])
+	assert.Equal(t, "Old", baseline["title"])
+}
+
+func TestStoryUseCase_UpdateWithoutChangesSkipsRevision(t *testing.T) {
+	ctx := context.TODO()
+	mockRepo := new(mocks.StoryRepositoryMock)
+	mockRevisions := new(mocks.RevisionRepositoryMock)
+	uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, mockRevisions, nil, nil, nil)
+
+	mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Title: "Same"}, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Story")).Return(nil)
+
+	_, err := uc.Update(ctx, "s-1", "Same", "", "", nil, nil)
+	assert.NoError(t, err)
+	mockRevisions.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRevisionUseCase_Rollback(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("story restores content and media but not status", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewRevisionUseCase(mockRevisions, mockRepo, nil, nil, nil)
+
+		mockRevisions.On("GetByID", ctx, uint(7)).Return(&domain.Revision{
+			ID: 7, StoryID: 1, EntityType: domain.RevisionEntityStory, EntityKey: "s-1", Version: 1,
+			Snapshot: domain.RevisionFields{"title": "Old", "thumbnail_url": "thumb-1.jpg", "dominant_color": "#111111", "category_id": float64(2), "status": domain.StatusDraft},
+		}, nil)
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Title: "New", ThumbnailURL: "thumb-2.jpg", DominantColor: "#222222", CategoryID: 2, Status: domain.StatusPublished}, nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Story")).Return(nil)
+		mockRepo.On("UpdateColor", ctx, uint(1), "#111111").Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		restored, err := uc.Rollback(ctx, 7)
+		assert.NoError(t, err)
+
+		story := mockRepo.Calls[1].Arguments.Get(1).(*domain.Story)
+		assert.Equal(t, "Old", story.Title)
+		assert.Equal(t, "thumb-1.jpg", story.ThumbnailURL)
+		assert.Equal(t, domain.StatusPublished, story.Status)
+		mockRepo.AssertNotCalled(t, "UpdateLifecycle", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+
+		rev := mockRevisions.Calls[1].Arguments.Get(1).(*domain.Revision)
+		assert.Equal(t, domain.RevisionActionRollback, rev.Action)
+		assert.Equal(t, domain.FieldChange{Old: "thumb-2.jpg", New: "thumb-1.jpg"}, rev.Changes["thumbnail_url"])
+		assert.Same(t, rev, restored)
+	})
+
+	t.Run("slide restores image and sound but not sequence", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewRevisionUseCase(mockRevisions, mockRepo, nil, nil, nil)
+
+		mockRevisions.On("GetByID", ctx, uint(8)).Return(&domain.Revision{
+			ID: 8, StoryID: 1, EntityType: domain.RevisionEntitySlide, EntityKey: "42",
+			Snapshot: domain.RevisionFields{"content": "A", "image_url": "a.jpg", "sound_url": "a.m4a", "sequence": float64(1)},
+		}, nil)
+		mockRepo.On("GetSlideByID", ctx, uint(42)).Return(&domain.Slide{ID: 42, Content: "B", ImageURL: "b.jpg", Sequence: 3}, nil)
+		mockRepo.On("UpdateSlide", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+		mockRepo.On("UpdateSlideMedia", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		_, err := uc.Rollback(ctx, 8)
+		assert.NoError(t, err)
+
+		slide := mockRepo.Calls[1].Arguments.Get(1).(*domain.Slide)
+		assert.Equal(t, "A", slide.Content)
+		assert.Equal(t, "a.jpg", slide.ImageURL)
+		assert.Equal(t, "a.m4a", slide.SoundURL)
+		assert.Equal(t, 3, slide.Sequence)
+	})
+
+	t.Run("chapter restores metadata but not position", func(t *testing.T) {
+		mockChapters := new(mocks.ChapterRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewRevisionUseCase(mockRevisions, nil, mockChapters, nil, nil)
+
+		mockRevisions.On("GetByID", ctx, uint(10)).Return(&domain.Revision{
+			ID: 10, StoryID: 1, EntityType: domain.RevisionEntityChapter, EntityKey: "c-1",
+			Snapshot: domain.RevisionFields{"title": "Bab 1", "synopsis": "Awal", "cover_url": "c1.jpg", "duration_seconds": float64(120), "position": float64(3)},
+		}, nil)
+		mockChapters.On("GetByUUID", ctx, "c-1").Return(&domain.Chapter{ID: 5, UUID: "c-1", StoryID: 1, Title: "Bab Satu", CoverURL: "c2.jpg", Position: 1}, nil)
+		mockChapters.On("Update", ctx, mock.AnythingOfType("*domain.Chapter")).Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		_, err := uc.Rollback(ctx, 10)
+		assert.NoError(t, err)
+
+		chapter := mockChapters.Calls[1].Arguments.Get(1).(*domain.Chapter)
+		assert.Equal(t, "Bab 1", chapter.Title)
+		assert.Equal(t, "c1.jpg", chapter.CoverURL)
+		assert.Equal(t, 120, chapter.DurationSeconds)
+		assert.Equal(t, 1, chapter.Position)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewRevisionUseCase(mockRevisions, nil, nil, nil, nil)
+
+		mockRevisions.On("GetByID", ctx, uint(9)).Return(nil, nil)
+
+		_, err := uc.Rollback(ctx, 9)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
\ No newline at end of file
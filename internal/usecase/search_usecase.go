@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/utils"
+
+)
+
+type SearchUC struct {
+	storyRepo    domain.StoryRepository
+	categoryRepo domain.CategoryRepository
+	chapterRepo  domain.ChapterRepository
+	redisRepo    domain.RedisRepository
+}
+
+func NewSearchUseCase(storyRepo domain.StoryRepository, categoryRepo domain.CategoryRepository, chapterRepo domain.ChapterRepository, redisRepo domain.RedisRepository) *SearchUC {
+	return &SearchUC{
+		storyRepo:    storyRepo,
+		categoryRepo: categoryRepo,
+		chapterRepo:  chapterRepo,
+		redisRepo:    redisRepo,
+	}
+}
+
+// searchCursor menyimpan offset masing-masing sumber hasil (story, chapter, slide)
+// karena ketiganya digabung berdasarkan skor di setiap halaman. Max adalah skor
+// tertinggi tiap sumber di halaman pertama, lihat normalizeScores.
+type searchCursor struct {
+	Stories  int        `json:"s"`
+	Chapters int        `json:"c"`
+	Slides   int        `json:"l"`
+	Max      [3]float64 `json:"m,omitempty"`
+}
+
+const maxCategoryHits = 3
+
+// Search menggabungkan hasil story, chapter dan slide yang diurutkan berdasarkan skor
+// yang sudah dinormalkan per sumber. Kategori yang cocok hanya ditampilkan di halaman pertama, di atas hasil lainnya.
+func (u *SearchUC) Search(ctx context.Context, query string, filter domain.SearchFilter, cursor string, limit int) (*domain.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrBadParamInput
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	var pos searchCursor
+	if cursor != "" {
+		if err := utils.DecodeCursor(cursor, &pos); err != nil {
+			return nil, domain.ErrBadParamInput
+		}
+		if pos.Stories < 0 || pos.Chapters < 0 || pos.Slides < 0 {
+			return nil, domain.ErrBadParamInput
+		}
+	}
+	params := func(offset int) domain.SearchParams {
+		// ambil satu lebih banyak untuk tahu apakah masih ada halaman berikutnya
+		return domain.SearchParams{Query: query, Filter: filter, Offset: offset, Limit: limit + 1}
+	}
+
+	stories, _, err := u.storyRepo.Search(ctx, params(pos.Stories))
+	if err != nil {
+		return nil, err
+	}
+	chapters, err := u.chapterRepo.Search(ctx, params(pos.Chapters))
+	if err != nil {
+		return nil, err
+	}
+	slides, err := u.storyRepo.SearchSlides(ctx, params(pos.Slides))
+	if err != nil {
+		return nil, err
+	}
+	facets, err := u.storyRepo.SearchFacets(ctx, params(0))
+	if err != nil {
+		return nil, err
+	}
+
+	storyHits := make([]domain.SearchHit, len(stories))
+	for i := range stories {
+		story := stories[i].Story
+		storyHits[i] = domain.SearchHit{
+			Type:    domain.SearchTypeStory,
+			ID:      story.UUID,
+			Title:   story.Title,
+			Snippet: stories[i].Snippet,
+			Score:   stories[i].Rank,
+			Story:   &story,
+		}
+	}
+
+	result := &domain.SearchResult{Hits: []domain.SearchHit{}, Facets: *facets}
+	if cursor == "" {
+		categories, err := u.categoryHits(ctx, query, filter)
+		if err != nil {
+			return nil, err
+		}
+		result.Hits = append(result.Hits, categories...)
+	}
+
+	sources := [][]domain.SearchHit{storyHits, chapters, slides}
+	normalizeScores(sources, &pos.Max)
+	taken := make([]int, len(sources))
+	for n := 0; n < limit; n++ {
+		best := -1
+		for i, src := range sources {
+			if taken[i] >= len(src) {
+				continue
+			}
+			if best < 0 || src[taken[i]].Score > sources[best][taken[best]].Score {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		result.Hits = append(result.Hits, sources[best][taken[best]])
+		taken[best]++
+	}
+
+	for i, src := range sources {
+		if taken[i] < len(src) {
+			result.HasMore = true
+		}
+	}
+	if result.HasMore {
+		result.NextCursor = utils.EncodeCursor(searchCursor{
+			Stories:  pos.Stories + taken[0],
+			Chapters: pos.Chapters + taken[1],
+			Slides:   pos.Slides + taken[2],
+			Max:      pos.Max,
+		})
+	}
+	return result, nil
+}
+
+// normalizeScores membagi skor setiap sumber dengan skor tertingginya karena skala skor
+// story, chapter dan slide berbeda. Skor tertinggi diambil di halaman pertama lalu
+// dibawa cursor, sehingga skor antar halaman tetap sebanding.
+func normalizeScores(sources [][]domain.SearchHit, maxScores *[3]float64) {
+	for i, src := range sources {
+		if maxScores[i] <= 0 {
+			for _, hit := range src {
+				maxScores[i] = max(maxScores[i], hit.Score)
+			}
+		}
+		if maxScores[i] <= 0 {
+			continue
+		}
+		for k := range src {
+			src[k].Score /= maxScores[i]
+		}
+	}
+}
+
+func (u *SearchUC) categoryHits(ctx context.Context, query string, filter domain.SearchFilter) ([]domain.SearchHit, error) {
+	// kategori tidak punya audio, jadi filter has_audio tidak berlaku untuknya
+	if filter.HasAudio != nil {
+		return nil, nil
+	}
+
+	categories, err := u.categoryRepo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []domain.SearchHit
+	for i := range categories {
+		cat := categories[i]
+		if filter.CategoryUUID != "" && cat.UUID != filter.CategoryUUID {
+			continue
+		}
+		if filter.CreatedFrom != nil && cat.CreatedAt.Before(*filter.CreatedFrom) {
+			continue
+		}
+		if filter.CreatedTo != nil && cat.CreatedAt.After(*filter.CreatedTo) {
+			continue
+		}
+		hits = append(hits, domain.SearchHit{
+			Type:     domain.SearchTypeCategory,
+			ID:       cat.UUID,
+			Title:    cat.Name,
+			Category: &cat,
+		})
+		if len(hits) == maxCategoryHits {
+			break
+		}
+	}
+	return hits, nil
+}
+
+// Suggest menggabungkan saran judul story dan nama kategori, diurutkan dari yang paling mirip.
+func (u *SearchUC) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.ErrBadParamInput
+	}
+	if utf8.RuneCountInString(query) < 2 {
+		return []domain.SearchSuggestion{}, nil
+	}
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+
+	cacheKey := fmt.Sprintf("%s%s:l%d", domain.CacheKeySuggestPrefix, strings.ToLower(query), limit)
+	if u.redisRepo != nil {
+		if cached, _ := u.redisRepo.Get(ctx, cacheKey); cached != "" {
+			var suggestions []domain.SearchSuggestion
+			if json.Unmarshal([]byte(cached), &suggestions) == nil {
+				return suggestions, nil
+			}
+		}
+	}
+
+	categories, err := u.categoryRepo.Suggest(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	stories, err := u.storyRepo.Suggest(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := append(categories, stories...)
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	if u.redisRepo != nil {
+		if data, err := json.Marshal(suggestions); err == nil {
+			u.redisRepo.Set(ctx, cacheKey, data, 5*time.Minute)
+		}
+	}
+	return suggestions, nil
+}
\ No newline at end of file
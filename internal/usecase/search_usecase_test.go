@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"khalif-stories/internal/domain"
+	"khalif-stories/internal/mocks"
+	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
+
+)
+
+func TestSearchUseCase_Search(t *testing.T) {
+	ctx := context.TODO()
+	params := func(offset int) domain.SearchParams {
+		return domain.SearchParams{Query: "musa", Offset: offset, Limit: 3}
+	}
+
+	storyRepo := new(mocks.StoryRepositoryMock)
+	catRepo := new(mocks.CategoryRepositoryMock)
+	chapterRepo := new(mocks.ChapterRepositoryMock)
+	uc := usecase.NewSearchUseCase(storyRepo, catRepo, chapterRepo, nil)
+
+	facets := &domain.SearchFacets{Categories: []domain.FacetCount{{Value: "cat-1", Label: "Nabi", Count: 2}}}
+	storyRepo.On("SearchFacets", ctx, params(0)).Return(facets, nil)
+
+	t.Run("first page merges sources by score", func(t *testing.T) {
+		catRepo.On("Search", ctx, "musa").Return([]domain.Category{{UUID: "cat-1", Name: "Kisah Musa"}}, nil).Once()
+		storyRepo.On("Search", ctx, params(0)).Return([]domain.StorySearchHit{
+			{Story: domain.Story{UUID: "s-1", Title: "Nabi Musa"}, Rank: 1.2},
+			{Story: domain.Story{UUID: "s-2", Title: "Firaun"}, Rank: 0.2},
+		}, nil, nil).Once()
+		chapterRepo.On("Search", ctx, params(0)).Return([]domain.SearchHit{
+			{Type: domain.SearchTypeChapter, ID: "ch-1", Score: 0.5},
+		}, nil).Once()
+		storyRepo.On("SearchSlides", ctx, params(0)).Return([]domain.SearchHit{
+			{Type: domain.SearchTypeSlide, ID: "10", Score: 0.8},
+			{Type: domain.SearchTypeSlide, ID: "11", Score: 0.1},
+		}, nil).Once()
+
+		res, err := uc.Search(ctx, " musa ", domain.SearchFilter{}, "", 2)
+
+		assert.NoError(t, err)
+		if assert.Len(t, res.Hits, 3) {
+			assert.Equal(t, domain.SearchTypeCategory, res.Hits[0].Type)
+			assert.Equal(t, "s-1", res.Hits[1].ID)
+			assert.Equal(t, "ch-1", res.Hits[2].ID)
+		}
+		assert.True(t, res.HasMore)
+		assert.NotEmpty(t, res.NextCursor)
+		assert.Equal(t, facets.Categories, res.Facets.Categories)
+
+		// halaman berikutnya melanjutkan offset tiap sumber tanpa kategori, skor tetap
+		// dinormalkan terhadap skor tertinggi halaman pertama
+		storyRepo.On("Search", ctx, params(1)).Return([]domain.StorySearchHit{
+			{Story: domain.Story{UUID: "s-2", Title: "Firaun"}, Rank: 0.2},
+		}, nil, nil).Once()
+		chapterRepo.On("Search", ctx, params(1)).Return([]domain.SearchHit{}, nil).Once()
+		storyRepo.On("SearchSlides", ctx, params(0)).Return([]domain.SearchHit{
+			{Type: domain.SearchTypeSlide, ID: "10", Score: 0.8},
+			{Type: domain.SearchTypeSlide, ID: "11", Score: 0.1},
+		}, nil).Once()
+
+		next, err := uc.Search(ctx, "musa", domain.SearchFilter{}, res.NextCursor, 2)
+
+		assert.NoError(t, err)
+		if assert.Len(t, next.Hits, 2) {
+			assert.Equal(t, "10", next.Hits[0].ID)
+			assert.Equal(t, "s-2", next.Hits[1].ID)
+			assert.InDelta(t, 0.2/1.2, next.Hits[1].Score, 1e-9)
+		}
+		assert.True(t, next.HasMore)
+		catRepo.AssertExpectations(t)
+	})
+
+	t.Run("scores are compared per type", func(t *testing.T) {
+		mixed := func(offset int) domain.SearchParams {
+			return domain.SearchParams{Query: "yunus", Offset: offset, Limit: 5}
+		}
+		storyRepo.On("SearchFacets", ctx, mixed(0)).Return(&domain.SearchFacets{}, nil).Once()
+		catRepo.On("Search", ctx, "yunus").Return([]domain.Category{}, nil).Once()
+		// ts_rank story jauh lebih kecil dari skor slide, tanpa normalisasi slide selalu menang
+		storyRepo.On("Search", ctx, mixed(0)).Return([]domain.StorySearchHit{
+			{Story: domain.Story{UUID: "s-1"}, Rank: 0.06},
+			{Story: domain.Story{UUID: "s-2"}, Rank: 0.05},
+		}, nil, nil).Once()
+		chapterRepo.On("Search", ctx, mixed(0)).Return([]domain.SearchHit{}, nil).Once()
+		storyRepo.On("SearchSlides", ctx, mixed(0)).Return([]domain.SearchHit{
+			{Type: domain.SearchTypeSlide, ID: "10", Score: 0.9},
+			{Type: domain.SearchTypeSlide, ID: "11", Score: 0.3},
+		}, nil).Once()
+
+		res, err := uc.Search(ctx, "yunus", domain.SearchFilter{}, "", 4)
+
+		assert.NoError(t, err)
+		ids := make([]string, len(res.Hits))
+		for i, hit := range res.Hits {
+			ids[i] = hit.ID
+		}
+		assert.Equal(t, []string{"s-1", "10", "s-2", "11"}, ids)
+		assert.False(t, res.HasMore)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := uc.Search(ctx, "musa", domain.SearchFilter{}, "not-a-cursor!", 2)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("negative cursor offset", func(t *testing.T) {
+		cursor := utils.EncodeCursor(map[string]int{"s": 2, "c": -1, "l": 0})
+
+		_, err := uc.Search(ctx, "musa", domain.SearchFilter{}, cursor, 2)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
+
+func TestSearchUseCase_Suggest(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("merges stories and categories by score", func(t *testing.T) {
+		storyRepo := new(mocks.StoryRepositoryMock)
+		catRepo := new(mocks.CategoryRepositoryMock)
+		cache := new(mocks.RedisRepositoryMock)
+		uc := usecase.NewSearchUseCase(storyRepo, catRepo, nil, cache)
+
+		cache.On("Get", ctx, "search:suggest:rosululloh:l2").Return("", nil)
+		cache.On("Set", ctx, "search:suggest:rosululloh:l2", mock.Anything, mock.Anything).Return(nil)
+		catRepo.On("Suggest", ctx, "Rosululloh", 2).Return([]domain.SearchSuggestion{
+			{Type: domain.SearchTypeCategory, ID: "cat-1", Text: "Sirah Rasul", Score: 0.4},
+		}, nil)
+		storyRepo.On("Suggest", ctx, "Rosululloh", 2).Return([]domain.SearchSuggestion{
+			{Type: domain.SearchTypeStory, ID: "s-1", Text: "Kisah Rasulullah", Score: 1},
+			{Type: domain.SearchTypeStory, ID: "s-2", Text: "Hijrah Rasulullah", Score: 0.9},
+		}, nil)
+
+		res, err := uc.Suggest(ctx, " Rosululloh ", 2)
+
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "s-1", res[0].ID)
+		assert.Equal(t, "s-2", res[1].ID)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("too short returns nothing", func(t *testing.T) {
+		uc := usecase.NewSearchUseCase(new(mocks.StoryRepositoryMock), new(mocks.CategoryRepositoryMock), nil, nil)
+
+		res, err := uc.Suggest(ctx, "r", 8)
+
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		uc := usecase.NewSearchUseCase(new(mocks.StoryRepositoryMock), new(mocks.CategoryRepositoryMock), nil, nil)
+
+		_, err := uc.Suggest(ctx, "  ", 8)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
\ No newline at end of file
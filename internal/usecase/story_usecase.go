@@ -6,27 +6,33 @@ import (
 	"errors"
 	"fmt"
 	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"khalif-stories/internal/config"
 	"khalif-stories/internal/domain"
-	"khalif-stories/internal/repository"
 	"khalif-stories/pkg/utils"
 
 )
 
 type StoryUC struct {
-	cfg          *config.Config
-	repo         domain.StoryRepository
-	categoryRepo domain.CategoryRepository
-	redisRepo    *repository.RedisRepo
-	uploader     *utils.AzureUploader
+	cfg            *config.Config
+	repo           domain.StoryRepository
+	categoryRepo   domain.CategoryRepository
+	redisRepo      domain.RedisRepository
+	uploader       domain.StorageRepository
+	revisionRepo   domain.RevisionRepository
+	collectionRepo domain.CollectionRepository
+	jobRepo        domain.JobRepository
+	tx             domain.Transactor
 }
 
-func NewStoryUseCase(cfg *config.Config, repo domain.StoryRepository, categoryRepo domain.CategoryRepository, redisRepo *repository.RedisRepo, uploader *utils.AzureUploader) domain.StoryUseCase {
-	return &StoryUC{cfg: cfg, repo: repo, categoryRepo: categoryRepo, redisRepo: redisRepo, uploader: uploader}
+func NewStoryUseCase(cfg *config.Config, repo domain.StoryRepository, categoryRepo domain.CategoryRepository, redisRepo domain.RedisRepository, uploader domain.StorageRepository, revisionRepo domain.RevisionRepository, collectionRepo domain.CollectionRepository, jobRepo domain.JobRepository, tx domain.Transactor) domain.StoryUseCase {
+	return &StoryUC{cfg: cfg, repo: repo, categoryRepo: categoryRepo, redisRepo: redisRepo, uploader: uploader, revisionRepo: revisionRepo, collectionRepo: collectionRepo, jobRepo: jobRepo, tx: tx}
 }
 
 func (u *StoryUC) Create(ctx context.Context, title, desc string, categoryUUID string, userID string, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
@@ -46,28 +52,39 @@ func (u *StoryUC) Create(ctx context.Context, title, desc string, categoryUUID s
 		CategoryID:  cat.ID,
 		Category:    *cat,
 		UserID:      userID,
-		Status:      "Pending_Upload",
+		Status:      domain.StatusPendingUpload,
 	}
 
 	if err := u.repo.Create(ctx, story); err != nil {
 		return nil, err
 	}
 
-	thumbURL, domColor, err := utils.UploadAndAnalyzeImage(ctx, u.uploader, file, header, u.cfg.AzureContainerStoriesName, u.cfg.StoriesThumbPath, story.UUID)
+	// warna dominan diambil worker, story tetap bisa dipakai sebelum warnanya ada
+	thumbURL, err := utils.UploadFile(ctx, u.uploader, file, header, u.cfg.AzureContainerStoriesName, u.cfg.StoriesThumbPath, story.UUID)
 	if err != nil {
 		u.repo.Delete(ctx, story.UUID)
 		return nil, err
 	}
 
 	story.ThumbnailURL = thumbURL
-	story.DominantColor = domColor
 	story.Status = domain.StatusDraft
 
-	if err := u.repo.Update(ctx, story); err != nil {
+	err = u.repo.Update(ctx, story)
+	if err == nil {
+		err = u.repo.UpdateLifecycle(ctx, story, domain.StatusPendingUpload)
+	}
+	if err != nil {
 		u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerStoriesName, thumbURL)
 		u.repo.Delete(ctx, story.UUID)
 		return nil, err
 	}
+	if thumbURL != "" {
+		if err := queueStoryPalette(ctx, u.jobRepo, u.cfg, story); err != nil {
+			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerStoriesName, thumbURL)
+			u.repo.Delete(ctx, story.UUID)
+			return nil, err
+		}
+	}
 
 	if u.redisRepo != nil {
 		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
@@ -76,13 +93,13 @@ func (u *StoryUC) Create(ctx context.Context, title, desc string, categoryUUID s
 	return story, nil
 }
 
-func (u *StoryUC) Update(ctx context.Context, storyUUID string, title, desc, categoryUUID, status string, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
+func (u *StoryUC) Update(ctx context.Context, storyUUID string, title, desc, categoryUUID string, file multipart.File, header *multipart.FileHeader) (*domain.Story, error) {
 	story, err := u.repo.GetByUUID(ctx, storyUUID)
 	if err != nil || story == nil {
 		return nil, errors.New("story not found")
 	}
 
-	oldThumbURL := story.ThumbnailURL
+	before := snapshotStory(story)
 
 	if title != "" {
 		story.Title = title
@@ -90,9 +107,6 @@ func (u *StoryUC) Update(ctx context.Context, storyUUID string, title, desc, cat
 	if desc != "" {
 		story.Description = desc
 	}
-	if status != "" {
-		story.Status = status
-	}
 
 	if categoryUUID != "" {
 		if cat, _ := u.categoryRepo.GetByUUID(ctx, categoryUUID); cat != nil {
@@ -101,27 +115,142 @@ func (u *StoryUC) Update(ctx context.Context, storyUUID string, title, desc, cat
 		}
 	}
 
-	newThumbURL, newColor, err := utils.UploadAndAnalyzeImage(ctx, u.uploader, file, header, u.cfg.AzureContainerStoriesName, u.cfg.StoriesThumbPath, uuid.New().String())
+	// warna dominan lama dipakai sampai worker selesai mengambil warna thumbnail baru
+	newThumbURL, err := utils.UploadFile(ctx, u.uploader, file, header, u.cfg.AzureContainerStoriesName, u.cfg.StoriesThumbPath, uuid.New().String())
 	if err != nil {
 		return nil, err
 	}
 
 	if newThumbURL != "" {
 		story.ThumbnailURL = newThumbURL
-		story.DominantColor = newColor
 	}
 
 	story.UpdatedAt = time.Now()
 
-	if err := u.repo.Update(ctx, story); err != nil {
+	// thumbnail lama tidak dihapus karena masih dirujuk revisi untuk rollback,
+	// pembersihannya lewat perintah blobs gc
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.Update(ctx, story); err != nil {
+			return err
+		}
+		if newThumbURL != "" {
+			if err := queueStoryPalette(ctx, u.jobRepo, u.cfg, story); err != nil {
+				return err
+			}
+		}
+		return recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntityStory, story.UUID, domain.RevisionActionUpdate, before, snapshotStory(story))
+	})
+	if err != nil {
 		if newThumbURL != "" {
 			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerStoriesName, newThumbURL)
 		}
 		return nil, err
 	}
 
-	if newThumbURL != "" && oldThumbURL != "" {
-		u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerStoriesName, oldThumbURL)
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+
+	return story, nil
+}
+
+// Transition memindahkan status story sesuai domain.StoryTransitions dan mencatat
+// waktu terbit atau arsip. Jadwal yang sudah tidak berlaku untuk status baru dihapus
+// agar scheduler tidak mengubah lagi story yang dipindahkan manual.
+func (u *StoryUC) Transition(ctx context.Context, storyUUID, status, role string) (*domain.Story, error) {
+	story, err := u.repo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := domain.CheckStoryTransition(story.Status, status, role); err != nil {
+		return nil, err
+	}
+
+	before := snapshotStory(story)
+	from := story.Status
+	now := time.Now()
+	switch status {
+	case domain.StatusPublished:
+		story.PublishedAt = &now
+		story.ArchivedAt = nil
+		story.PublishAt = nil
+	case domain.StatusArchived:
+		story.ArchivedAt = &now
+		story.UnpublishAt = nil
+	case domain.StatusDraft:
+		story.ArchivedAt = nil
+		story.PublishAt = nil
+		story.UnpublishAt = nil
+	}
+	story.Status = status
+	story.UpdatedAt = now
+
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.UpdateLifecycle(ctx, story, from); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntityStory, story.UUID, domain.RevisionActionUpdate, before, snapshotStory(story))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+		// saran pencarian hanya berisi story Published
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeySuggestPrefix)
+	}
+
+	return story, nil
+}
+
+// Schedule mengganti jadwal terbit dan tarik story. Nilai nil menghapus jadwal.
+// publish_at hanya berlaku untuk story Draft/InReview dan baru dijalankan scheduler
+// saat story sudah InReview, unpublish_at butuh story yang sudah atau akan terbit.
+func (u *StoryUC) Schedule(ctx context.Context, storyUUID string, publishAt, unpublishAt *time.Time) (*domain.Story, error) {
+	story, err := u.repo.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
+
+	now := time.Now()
+	if publishAt != nil {
+		if !publishAt.After(now) {
+			return nil, domain.ErrBadParamInput
+		}
+		if story.Status != domain.StatusDraft && story.Status != domain.StatusInReview {
+			return nil, domain.ErrInvalidTransition
+		}
+	}
+	if unpublishAt != nil {
+		if !unpublishAt.After(now) || (publishAt != nil && !unpublishAt.After(*publishAt)) {
+			return nil, domain.ErrBadParamInput
+		}
+		if story.Status != domain.StatusPublished && publishAt == nil {
+			return nil, domain.ErrInvalidTransition
+		}
+	}
+
+	before := snapshotStory(story)
+	story.PublishAt = publishAt
+	story.UnpublishAt = unpublishAt
+	story.UpdatedAt = now
+
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.UpdateLifecycle(ctx, story, story.Status); err != nil {
+			return err
+		}
+		return recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntityStory, story.UUID, domain.RevisionActionUpdate, before, snapshotStory(story))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if u.redisRepo != nil {
@@ -131,6 +260,28 @@ func (u *StoryUC) Update(ctx context.Context, storyUUID string, title, desc, cat
 	return story, nil
 }
 
+func (u *StoryUC) ListScheduled(ctx context.Context, limit int) ([]domain.ScheduledChange, error) {
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+	return u.repo.ListScheduled(ctx, limit)
+}
+
+// RunScheduler dipanggil berkala oleh job di cmd/api, cache hanya dibuang jika ada
+// story yang berubah status.
+func (u *StoryUC) RunScheduler(ctx context.Context, now time.Time) (int64, error) {
+	applied, err := u.repo.ApplyDueSchedules(ctx, now)
+	if err != nil || applied == 0 {
+		return applied, err
+	}
+
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeySuggestPrefix)
+	}
+	return applied, nil
+}
+
 func (u *StoryUC) Delete(ctx context.Context, uuid string) error {
 	story, err := u.repo.GetByUUID(ctx, uuid)
 	if err != nil {
@@ -140,19 +291,12 @@ func (u *StoryUC) Delete(ctx context.Context, uuid string) error {
 		return nil
 	}
 
+	// media hasil impor paket dinamai checksum dan bisa dipakai bersama story lain,
+	// jadi thumbnail, cover chapter dan media slide dibersihkan lewat perintah blobs gc
 	if err := u.repo.Delete(ctx, uuid); err != nil {
 		return err
 	}
 
-	if story.ThumbnailURL != "" {
-		u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainerStoriesName, story.ThumbnailURL)
-	}
-	for _, slide := range story.Slides {
-		if slide.ImageURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainer, slide.ImageURL)
-		}
-	}
-
 	if u.redisRepo != nil {
 		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
 	}
@@ -165,13 +309,18 @@ func (u *StoryUC) AddSlide(ctx context.Context, storyUUID string, content string
 	if err != nil {
 		return nil, err
 	}
+	if story == nil {
+		return nil, domain.ErrNotFound
+	}
 
+	// cek awal agar file tidak diunggah percuma, batas sebenarnya ditegakkan database
 	count, _ := u.repo.CountSlides(ctx, story.ID)
 	if count >= int64(u.cfg.SlideLimit) {
-		return nil, errors.New("slide limit reached")
+		return nil, domain.ErrSlideLimitReached
 	}
 
-	imageURL, _, err := utils.UploadAndAnalyzeImage(ctx, u.uploader, file, header, u.cfg.AzureContainer, u.cfg.StoriesSlidePath, uuid.New().String())
+	// gambar diproses worker, slide tetap processing sampai selesai
+	image, err := stageMedia(ctx, u.uploader, file, header, u.cfg.AzureContainer, u.cfg.StoriesSlidePath, "")
 	if err != nil {
 		return nil, err
 	}
@@ -180,35 +329,195 @@ func (u *StoryUC) AddSlide(ctx context.Context, storyUUID string, content string
 		StoryID:  &story.ID,
 		Content:  content,
 		Sequence: sequence,
-		ImageURL: imageURL,
+		Status:   slideStatus(image),
+	}
+
+	if err := u.repo.CreateSlide(ctx, slide, u.cfg.SlideLimit); err != nil {
+		discardStaged(ctx, u.uploader, image)
+		return nil, err
+	}
+	if err := queueSlideMedia(ctx, u.jobRepo, u.repo, u.cfg, slide, story.ID, image, nil); err != nil {
+		u.repo.DeleteSlide(ctx, slide)
+		discardStaged(ctx, u.uploader, image)
+		return nil, err
+	}
+
+	return slide, nil
+}
+
+// UpdateSlide mengubah teks dan/atau gambar slide story. File lama tidak dihapus karena
+// masih dirujuk revisi, pembersihannya lewat perintah blobs gc.
+func (u *StoryUC) UpdateSlide(ctx context.Context, storyUUID string, slideID uint, content *string, file multipart.File, header *multipart.FileHeader) (*domain.Slide, error) {
+	story, err := u.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+	slide := findSlide(story.Slides, slideID)
+	if slide == nil {
+		return nil, domain.ErrNotFound
 	}
 
-	if err := u.repo.CreateSlide(ctx, slide); err != nil {
-		if imageURL != "" {
-			u.uploader.DeleteFromContainer(ctx, u.cfg.AzureContainer, imageURL)
+	before := snapshotSlide(slide)
+	if content != nil {
+		slide.Content = *content
+	}
+
+	image, err := stageMedia(ctx, u.uploader, file, header, u.cfg.AzureContainer, u.cfg.StoriesSlidePath, "")
+	if err != nil {
+		return nil, err
+	}
+	if image != nil {
+		slide.Status = domain.SlideStatusProcessing
+	}
+
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.UpdateSlide(ctx, slide); err != nil {
+			return err
+		}
+		if err := queueSlideMedia(ctx, u.jobRepo, u.repo, u.cfg, slide, story.ID, image, nil); err != nil {
+			return err
 		}
+		return recordRevision(ctx, u.revisionRepo, story.ID, domain.RevisionEntitySlide, slideRevisionKey(slide.ID), domain.RevisionActionUpdate, before, snapshotSlide(slide))
+	})
+	if err != nil {
+		discardStaged(ctx, u.uploader, image)
 		return nil, err
 	}
 
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
 	return slide, nil
 }
 
-func (u *StoryUC) GetAll(ctx context.Context, page, limit int, sort string) ([]domain.Story, error) {
-	cacheKey := fmt.Sprintf("stories:p%d:l%d:s%s", page, limit, sort)
-	if cached, _ := u.redisRepo.Get(ctx, cacheKey); cached != "" {
-		var stories []domain.Story
-		if json.Unmarshal([]byte(cached), &stories) == nil {
-			return stories, nil
+func (u *StoryUC) DeleteSlide(ctx context.Context, storyUUID string, slideID uint) error {
+	story, err := u.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return err
+	}
+	slide := findSlide(story.Slides, slideID)
+	if slide == nil {
+		return domain.ErrNotFound
+	}
+
+	// media slide dibersihkan lewat blobs gc, lihat Delete
+	if err := u.repo.DeleteSlide(ctx, slide); err != nil {
+		return err
+	}
+
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+	return nil
+}
+
+// ReorderSlides menyusun ulang semua slide story, slideIDs harus berisi tepat semua slide story.
+func (u *StoryUC) ReorderSlides(ctx context.Context, storyUUID string, slideIDs []uint) ([]domain.Slide, error) {
+	story, err := u.GetByUUID(ctx, storyUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var slides []domain.Slide
+	err = inTransaction(ctx, u.tx, func(ctx context.Context) error {
+		if err := u.repo.ReorderSlides(ctx, story.ID, slideIDs); err != nil {
+			return err
 		}
+		slides, err = applySlideOrder(ctx, u.revisionRepo, story.ID, story.Slides, slideIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	stories, err := u.repo.GetAll(ctx, page, limit, sort)
-	if err == nil {
-		if data, err := json.Marshal(stories); err == nil {
+	if u.redisRepo != nil {
+		_ = u.redisRepo.DeletePrefix(ctx, domain.CacheKeyStoryPrefix)
+	}
+	return slides, nil
+}
+
+func findSlide(slides []domain.Slide, id uint) *domain.Slide {
+	for i := range slides {
+		if slides[i].ID == id {
+			return &slides[i]
+		}
+	}
+	return nil
+}
+
+func slideRevisionKey(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// applySlideOrder mengembalikan slide dalam urutan baru yang sudah disimpan repository
+// dan mencatat revisi untuk slide yang sequence-nya berubah.
+func applySlideOrder(ctx context.Context, revisionRepo domain.RevisionRepository, storyID uint, slides []domain.Slide, slideIDs []uint) ([]domain.Slide, error) {
+	ordered := make([]domain.Slide, 0, len(slideIDs))
+	for i, id := range slideIDs {
+		slide := findSlide(slides, id)
+		if slide == nil {
+			return nil, domain.ErrBadParamInput
+		}
+
+		before := snapshotSlide(slide)
+		slide.Sequence = i + 1
+		if err := recordRevision(ctx, revisionRepo, storyID, domain.RevisionEntitySlide, slideRevisionKey(slide.ID), domain.RevisionActionUpdate, before, snapshotSlide(slide)); err != nil {
+			return nil, err
+		}
+		ordered = append(ordered, *slide)
+	}
+	return ordered, nil
+}
+
+// listPage adalah bentuk cache satu halaman listing beserta info paginasinya.
+type listPage[T any] struct {
+	Items []T              `json:"items"`
+	Page  *domain.PageInfo `json:"page"`
+}
+
+func (u *StoryUC) GetAll(ctx context.Context, q domain.ListQuery) ([]domain.Story, *domain.PageInfo, error) {
+	cacheKey := listCacheKey(domain.CacheKeyStoryPrefix, q)
+	if u.redisRepo != nil {
+		if cached, _ := u.redisRepo.Get(ctx, cacheKey); cached != "" {
+			var page listPage[domain.Story]
+			if json.Unmarshal([]byte(cached), &page) == nil && page.Page != nil {
+				return page.Items, page.Page, nil
+			}
+		}
+	}
+
+	stories, info, err := u.repo.GetAll(ctx, q)
+	if err == nil && u.redisRepo != nil {
+		if data, err := json.Marshal(listPage[domain.Story]{Items: stories, Page: info}); err == nil {
 			u.redisRepo.Set(ctx, cacheKey, data, 5*time.Minute)
 		}
 	}
-	return stories, err
+	return stories, info, err
+}
+
+// listCacheKey membuat key cache yang stabil dari ListQuery, filter diurutkan berdasarkan nama.
+func listCacheKey(prefix string, q domain.ListQuery) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sl%d:t%t:c%s:s", prefix, q.Limit, q.WithTotal, q.Cursor)
+	for i, s := range q.Sort {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if s.Desc {
+			b.WriteByte('-')
+		}
+		b.WriteString(s.Field)
+	}
+
+	names := make([]string, 0, len(q.Filters))
+	for name := range q.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, ":f%s=%s", name, q.Filters[name])
+	}
+	return b.String()
 }
 
 func (u *StoryUC) GetByUUID(ctx context.Context, uuid string) (*domain.Story, error) {
@@ -222,11 +531,37 @@ func (u *StoryUC) GetByUUID(ctx context.Context, uuid string) (*domain.Story, er
 	return story, nil
 }
 
-func (u *StoryUC) Search(ctx context.Context, query string) ([]domain.Story, error) {
-	return u.repo.Search(ctx, query)
+// GetPublished dipakai endpoint publik, story yang belum terbit dianggap tidak ada.
+// Posisi story di setiap series ikut dilampirkan untuk navigasi sebelum/berikutnya.
+func (u *StoryUC) GetPublished(ctx context.Context, uuid string) (*domain.Story, error) {
+	story, err := u.GetByUUID(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if story.Status != domain.StatusPublished {
+		return nil, domain.ErrNotFound
+	}
+
+	if u.collectionRepo != nil {
+		series, err := u.collectionRepo.SeriesForStory(ctx, story.ID)
+		if err != nil {
+			return nil, err
+		}
+		story.Series = series
+	}
+	return story, nil
 }
 
-// --- [INI FUNGSI YANG KITA TAMBAHKAN] ---
-func (u *StoryUC) GetRecommendations(ctx context.Context, userID string) ([]domain.Recommendation, error) {
-	return u.repo.GetRecommendations(ctx, userID)
+func (u *StoryUC) Search(ctx context.Context, params domain.SearchParams) ([]domain.StorySearchHit, *domain.PageInfo, error) {
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return nil, nil, domain.ErrBadParamInput
+	}
+	if params.Offset < 0 {
+		params.Offset = 0
+	}
+	if params.Limit < 1 || params.Limit > 50 {
+		params.Limit = 10
+	}
+	return u.repo.Search(ctx, params)
 }
\ No newline at end of file
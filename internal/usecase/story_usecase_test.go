@@ -2,30 +2,44 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"khalif-stories/internal/config"
 	"khalif-stories/internal/domain"
 	"khalif-stories/internal/mocks"
 	"khalif-stories/internal/usecase"
+	"khalif-stories/pkg/utils"
 
 )
 
 func TestStoryUseCase_Create(t *testing.T) {
 	mockRepo := new(mocks.StoryRepositoryMock)
+	mockCatRepo := new(mocks.CategoryRepositoryMock)
 	cfg := &config.Config{SlideLimit: 20}
 
-	uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil)
+	uc := usecase.NewStoryUseCase(cfg, mockRepo, mockCatRepo, nil, nil, nil, nil, nil, nil)
 
 	ctx := context.TODO()
 
 	t.Run("success", func(t *testing.T) {
+		category := &domain.Category{ID: 1, UUID: "cat-uuid"}
+
+		mockRepo.On("CheckDuplicate", ctx, "Title", "Desc").Return(false, nil)
+		mockCatRepo.On("GetByUUID", ctx, "cat-uuid").Return(category, nil)
 		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Story")).Return(nil)
+		mockRepo.On("Update", ctx, mock.AnythingOfType("*domain.Story")).Return(nil)
+		mockRepo.On("UpdateLifecycle", ctx, mock.AnythingOfType("*domain.Story"), domain.StatusPendingUpload).Return(nil)
 
-		res, err := uc.Create(ctx, "Title", "Desc", 1, nil, nil)
+		res, err := uc.Create(ctx, "Title", "Desc", "cat-uuid", "user-1", nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -35,11 +49,285 @@ func TestStoryUseCase_Create(t *testing.T) {
 	})
 }
 
+func TestStoryUseCase_Transition(t *testing.T) {
+	ctx := context.TODO()
+
+	cases := []struct {
+		name    string
+		from    string
+		to      string
+		role    string
+		wantErr error
+	}{
+		{"editor submits for review", domain.StatusDraft, domain.StatusInReview, domain.RoleEditor, nil},
+		{"editor cannot publish", domain.StatusInReview, domain.StatusPublished, domain.RoleEditor, domain.ErrForbidden},
+		{"admin publishes", domain.StatusInReview, domain.StatusPublished, domain.RoleAdmin, nil},
+		{"draft cannot skip review", domain.StatusDraft, domain.StatusPublished, domain.RoleAdmin, domain.ErrInvalidTransition},
+		{"user cannot submit", domain.StatusDraft, domain.StatusInReview, domain.RoleUser, domain.ErrForbidden},
+		{"admin archives", domain.StatusPublished, domain.StatusArchived, domain.RoleAdmin, nil},
+		{"unknown status", domain.StatusDraft, "Deleted", domain.RoleAdmin, domain.ErrInvalidTransition},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.StoryRepositoryMock)
+			uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+			mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: tc.from}, nil)
+			mockRepo.On("UpdateLifecycle", ctx, mock.AnythingOfType("*domain.Story"), tc.from).Return(nil).Maybe()
+
+			res, err := uc.Transition(ctx, "s-1", tc.to, tc.role)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockRepo.AssertNotCalled(t, "UpdateLifecycle", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.to, res.Status)
+			switch tc.to {
+			case domain.StatusPublished:
+				assert.NotNil(t, res.PublishedAt)
+			case domain.StatusArchived:
+				assert.NotNil(t, res.ArchivedAt)
+			}
+		})
+	}
+
+	t.Run("manual transition clears stale schedule", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+		unpublishAt := time.Now().Add(time.Hour)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusPublished, UnpublishAt: &unpublishAt}, nil)
+		mockRepo.On("UpdateLifecycle", ctx, mock.AnythingOfType("*domain.Story"), domain.StatusPublished).Return(nil)
+
+		res, err := uc.Transition(ctx, "s-1", domain.StatusArchived, domain.RoleAdmin)
+
+		assert.NoError(t, err)
+		assert.Nil(t, res.UnpublishAt)
+	})
+
+	t.Run("status changed by scheduler meanwhile", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: domain.StatusInReview}, nil)
+		mockRepo.On("UpdateLifecycle", ctx, mock.AnythingOfType("*domain.Story"), domain.StatusInReview).Return(domain.ErrInvalidTransition)
+
+		_, err := uc.Transition(ctx, "s-1", domain.StatusPublished, domain.RoleAdmin)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+}
+
+func TestStoryUseCase_GetPublished(t *testing.T) {
+	mockRepo := new(mocks.StoryRepositoryMock)
+	uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.TODO()
+
+	mockRepo.On("GetByUUID", ctx, "draft").Return(&domain.Story{UUID: "draft", Status: domain.StatusDraft}, nil)
+	mockRepo.On("GetByUUID", ctx, "live").Return(&domain.Story{UUID: "live", Status: domain.StatusPublished}, nil)
+
+	_, err := uc.GetPublished(ctx, "draft")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	res, err := uc.GetPublished(ctx, "live")
+	assert.NoError(t, err)
+	assert.Equal(t, "live", res.UUID)
+}
+
+func TestStoryUseCase_Schedule(t *testing.T) {
+	ctx := context.TODO()
+	at := func(d time.Duration) *time.Time {
+		v := time.Now().Add(d)
+		return &v
+	}
+
+	cases := []struct {
+		name      string
+		status    string
+		publish   *time.Time
+		unpublish *time.Time
+		wantErr   error
+	}{
+		{"publish window for reviewed story", domain.StatusInReview, at(24 * time.Hour), at(48 * time.Hour), nil},
+		{"unpublish live story", domain.StatusPublished, nil, at(time.Hour), nil},
+		{"publish in the past", domain.StatusInReview, at(-time.Hour), nil, domain.ErrBadParamInput},
+		{"unpublish before publish", domain.StatusDraft, at(48 * time.Hour), at(24 * time.Hour), domain.ErrBadParamInput},
+		{"publish already published", domain.StatusPublished, at(time.Hour), nil, domain.ErrInvalidTransition},
+		{"unpublish draft without publish", domain.StatusDraft, nil, at(time.Hour), domain.ErrInvalidTransition},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.StoryRepositoryMock)
+			uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+			mockRepo.On("GetByUUID", ctx, "s-1").Return(&domain.Story{ID: 1, UUID: "s-1", Status: tc.status}, nil)
+			mockRepo.On("UpdateLifecycle", ctx, mock.AnythingOfType("*domain.Story"), tc.status).Return(nil).Maybe()
+
+			res, err := uc.Schedule(ctx, "s-1", tc.publish, tc.unpublish)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockRepo.AssertNotCalled(t, "UpdateLifecycle", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.publish, res.PublishAt)
+			assert.Equal(t, tc.unpublish, res.UnpublishAt)
+		})
+	}
+}
+
+func TestStoryUseCase_RunScheduler(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now()
+
+	t.Run("invalidates cache when stories change", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockRedis := new(mocks.RedisRepositoryMock)
+		uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, mockRedis, nil, nil, nil, nil, nil)
+
+		mockRepo.On("ApplyDueSchedules", ctx, now).Return(int64(2), nil)
+		mockRedis.On("DeletePrefix", ctx, domain.CacheKeyStoryPrefix).Return(nil).Once()
+		mockRedis.On("DeletePrefix", ctx, domain.CacheKeySuggestPrefix).Return(nil).Once()
+
+		applied, err := uc.RunScheduler(ctx, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), applied)
+		mockRedis.AssertExpectations(t)
+	})
+
+	t.Run("keeps cache when nothing is due", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockRedis := new(mocks.RedisRepositoryMock)
+		uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, mockRedis, nil, nil, nil, nil, nil)
+
+		mockRepo.On("ApplyDueSchedules", ctx, now).Return(int64(0), nil)
+
+		_, err := uc.RunScheduler(ctx, now)
+
+		assert.NoError(t, err)
+		mockRedis.AssertNotCalled(t, "DeletePrefix", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoryUseCase_Delete(t *testing.T) {
+	mockRepo := new(mocks.StoryRepositoryMock)
+	mockStorage := new(mocks.StorageRepositoryMock)
+	cfg := &config.Config{AzureContainer: "media", AzureContainerStoriesName: "stories"}
+
+	uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, mockStorage, nil, nil, nil, nil)
+	ctx := context.TODO()
+
+	t.Run("leaves shared assets to blobs gc", func(t *testing.T) {
+		story := &domain.Story{
+			ID:           1,
+			UUID:         "abc-123",
+			ThumbnailURL: "/uploads/stories/thumb.png",
+			Slides:       []domain.Slide{{ImageURL: "/uploads/media/slide.png"}},
+		}
+
+		mockRepo.On("GetByUUID", ctx, "abc-123").Return(story, nil)
+		mockRepo.On("Delete", ctx, "abc-123").Return(nil)
+
+		err := uc.Delete(ctx, "abc-123")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "DeleteFromContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestStoryUseCase_SlideCRUD(t *testing.T) {
+	ctx := context.TODO()
+	cfg := &config.Config{AzureContainer: "media"}
+	newStory := func() *domain.Story {
+		return &domain.Story{ID: 1, UUID: "s-1", Slides: []domain.Slide{
+			{ID: 10, Content: "A", ImageURL: "/uploads/media/a.png", Sequence: 1},
+			{ID: 11, Content: "B", Sequence: 2},
+		}}
+	}
+
+	t.Run("update keeps image when only content is sent", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(newStory(), nil)
+		mockRepo.On("UpdateSlide", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+
+		content := "A2"
+		res, err := uc.UpdateSlide(ctx, "s-1", 10, &content, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "A2", res.Content)
+		assert.Equal(t, "/uploads/media/a.png", res.ImageURL)
+	})
+
+	t.Run("unknown slide", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(newStory(), nil)
+
+		_, err := uc.UpdateSlide(ctx, "s-1", 99, nil, nil, nil)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "UpdateSlide", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete keeps the image for blobs gc", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockStorage := new(mocks.StorageRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, mockStorage, nil, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(newStory(), nil)
+		mockRepo.On("DeleteSlide", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+
+		err := uc.DeleteSlide(ctx, "s-1", 10)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockStorage.AssertNotCalled(t, "DeleteFromContainer", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reorder records sequence changes", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		mockRevisions := new(mocks.RevisionRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, nil, mockRevisions, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(newStory(), nil)
+		mockRepo.On("ReorderSlides", ctx, uint(1), []uint{11, 10}).Return(nil)
+		mockRevisions.On("Record", ctx, mock.AnythingOfType("*domain.Revision"), mock.AnythingOfType("domain.RevisionFields")).Return(nil)
+
+		res, err := uc.ReorderSlides(ctx, "s-1", []uint{11, 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(11), res[0].ID)
+		assert.Equal(t, 1, res[0].Sequence)
+		assert.Equal(t, 2, res[1].Sequence)
+		mockRevisions.AssertNumberOfCalls(t, "Record", 2)
+	})
+
+	t.Run("reorder rejects incomplete list", func(t *testing.T) {
+		mockRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+
+		mockRepo.On("GetByUUID", ctx, "s-1").Return(newStory(), nil)
+		mockRepo.On("ReorderSlides", ctx, uint(1), []uint{11}).Return(domain.ErrBadParamInput)
+
+		_, err := uc.ReorderSlides(ctx, "s-1", []uint{11})
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
+
 func TestStoryUseCase_AddSlide(t *testing.T) {
 	mockRepo := new(mocks.StoryRepositoryMock)
 	cfg := &config.Config{SlideLimit: 5}
 
-	uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil)
+	uc := usecase.NewStoryUseCase(cfg, mockRepo, nil, nil, nil, nil, nil, nil, nil)
 	ctx := context.TODO()
 
 	t.Run("success", func(t *testing.T) {
@@ -48,7 +336,7 @@ func TestStoryUseCase_AddSlide(t *testing.T) {
 
 		mockRepo.On("GetByUUID", ctx, storyUUID).Return(story, nil)
 		mockRepo.On("CountSlides", ctx, uint(1)).Return(int64(2), nil)
-		mockRepo.On("CreateSlide", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+		mockRepo.On("CreateSlide", ctx, mock.AnythingOfType("*domain.Slide"), 5).Return(nil)
 
 		res, err := uc.AddSlide(ctx, storyUUID, "Content", 1, nil, nil)
 
@@ -65,8 +353,91 @@ func TestStoryUseCase_AddSlide(t *testing.T) {
 
 		res, err := uc.AddSlide(ctx, storyUUID, "Content", 1, nil, nil)
 
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, domain.ErrSlideLimitReached)
 		assert.Nil(t, res)
-		assert.Equal(t, "slide limit reached", err.Error())
+	})
+
+	t.Run("limit reached concurrently", func(t *testing.T) {
+		raceRepo := new(mocks.StoryRepositoryMock)
+		uc := usecase.NewStoryUseCase(cfg, raceRepo, nil, nil, nil, nil, nil, nil, nil)
+		story := &domain.Story{ID: 3, UUID: "abc-race"}
+
+		// pre-check lolos, tetapi upload lain sudah mengisi slot terakhir lebih dulu
+		raceRepo.On("GetByUUID", ctx, "abc-race").Return(story, nil)
+		raceRepo.On("CountSlides", ctx, uint(3)).Return(int64(4), nil)
+		raceRepo.On("CreateSlide", ctx, mock.AnythingOfType("*domain.Slide"), 5).Return(domain.ErrSlideLimitReached)
+
+		_, err := uc.AddSlide(ctx, "abc-race", "Content", 5, nil, nil)
+
+		assert.ErrorIs(t, err, domain.ErrSlideLimitReached)
+	})
+
+	t.Run("image is processed in the background", func(t *testing.T) {
+		repo := new(mocks.StoryRepositoryMock)
+		jobs := new(mocks.JobRepositoryMock)
+		storage, err := utils.NewLocalUploader(t.TempDir(), "/uploads", "media")
+		require.NoError(t, err)
+		cfg := &config.Config{SlideLimit: 5, AzureContainer: "media", StoriesSlidePath: "stories/slides/", JobMaxAttempts: 3}
+		uc := usecase.NewStoryUseCase(cfg, repo, nil, nil, storage, nil, nil, jobs, nil)
+		story := &domain.Story{ID: 4, UUID: "abc-img"}
+
+		repo.On("GetByUUID", ctx, "abc-img").Return(story, nil)
+		repo.On("CountSlides", ctx, uint(4)).Return(int64(0), nil)
+		repo.On("CreateSlide", ctx, mock.AnythingOfType("*domain.Slide"), 5).Return(nil)
+		jobs.On("Enqueue", ctx, mock.AnythingOfType("*domain.Job")).Return(nil)
+		repo.On("SetSlideJob", ctx, mock.AnythingOfType("*domain.Slide")).Return(nil)
+
+		file := testFile("image-bytes")
+		res, err := uc.AddSlide(ctx, "abc-img", "Content", 1, file, &multipart.FileHeader{Filename: "slide.png"})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.SlideStatusProcessing, res.Status)
+		assert.Empty(t, res.ImageURL)
+
+		job := jobs.Calls[0].Arguments.Get(1).(*domain.Job)
+		assert.Equal(t, job.UUID, res.JobID)
+		assert.Equal(t, domain.JobTypeSlideMedia, job.Type)
+		assert.Equal(t, 3, job.MaxAttempts)
+
+		var payload domain.SlideMediaJob
+		require.NoError(t, json.Unmarshal(job.Payload, &payload))
+		require.NotNil(t, payload.Image)
+		assert.Nil(t, payload.Sound)
+		assert.Equal(t, uint(4), payload.StoryID)
+		assert.Equal(t, res.JobID, payload.JobID)
+		assert.True(t, strings.HasPrefix(payload.Image.Target, "stories/slides/"))
+		assert.True(t, strings.HasSuffix(payload.Image.Target, ".png"))
+
+		staged, err := storage.OpenFromContainer(ctx, "media", payload.Image.URL)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(staged)
+		staged.Close()
+		assert.Equal(t, "image-bytes", string(data))
+	})
+}
+
+func TestStoryUseCase_Search(t *testing.T) {
+	mockRepo := new(mocks.StoryRepositoryMock)
+	uc := usecase.NewStoryUseCase(&config.Config{}, mockRepo, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.TODO()
+
+	t.Run("normalizes query and paging", func(t *testing.T) {
+		hits := []domain.StorySearchHit{{Story: domain.Story{UUID: "s-1"}, Rank: 0.6, Headline: "Kisah <mark>Nabi</mark> Musa"}}
+		total := int64(1)
+		expected := domain.SearchParams{Query: "nabi musa", Cursor: "c1", Offset: 0, Limit: 10}
+		mockRepo.On("Search", ctx, expected).Return(hits, &domain.PageInfo{Total: &total}, nil).Once()
+
+		res, page, err := uc.Search(ctx, domain.SearchParams{Query: "  nabi musa ", Cursor: "c1", Offset: -10, Limit: 500})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), *page.Total)
+		assert.Len(t, res, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		_, _, err := uc.Search(ctx, domain.SearchParams{Query: "   "})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
 	})
 }
\ No newline at end of file
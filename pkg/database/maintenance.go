@@ -0,0 +1,55 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+)
+
+// RecountSlides menghitung ulang kolom slide_count dari isi tabel slides.
+func RecountSlides(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE stories s SET slide_count = (
+			SELECT count(*) FROM slides WHERE slides.story_id = s.id
+		)`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE chapters c SET slide_count = (
+			SELECT count(*) FROM slides WHERE slides.chapter_id = c.id
+		)`).Error
+	})
+}
+
+// ReferencedAssetURLs mengumpulkan semua URL file yang masih dipakai oleh database,
+// termasuk yang hanya dirujuk revisi lama agar rollback tetap bisa memulihkan media,
+// dan file staging milik job yang belum selesai atau ada di dead_jobs agar job tersebut
+// masih bisa dijalankan atau diulang.
+func ReferencedAssetURLs(db *gorm.DB) (map[string]struct{}, error) {
+	queries := []string{
+		"SELECT image_url FROM categories WHERE image_url <> ''",
+		"SELECT image_url FROM collections WHERE image_url <> ''",
+		"SELECT thumbnail_url FROM stories WHERE thumbnail_url <> ''",
+		"SELECT image_url FROM slides WHERE image_url <> ''",
+		"SELECT sound_url FROM slides WHERE sound_url <> ''",
+		"SELECT cover_url FROM chapters WHERE cover_url <> ''",
+		"SELECT DISTINCT snapshot->>'thumbnail_url' FROM revisions WHERE snapshot->>'thumbnail_url' <> ''",
+		"SELECT DISTINCT snapshot->>'image_url' FROM revisions WHERE snapshot->>'image_url' <> ''",
+		"SELECT DISTINCT snapshot->>'sound_url' FROM revisions WHERE snapshot->>'sound_url' <> ''",
+		"SELECT DISTINCT snapshot->>'cover_url' FROM revisions WHERE snapshot->>'cover_url' <> ''",
+		"SELECT payload->'image'->>'url' FROM jobs WHERE status <> 'completed' AND payload->'image'->>'url' <> ''",
+		"SELECT payload->'sound'->>'url' FROM jobs WHERE status <> 'completed' AND payload->'sound'->>'url' <> ''",
+		"SELECT payload->'image'->>'url' FROM dead_jobs WHERE payload->'image'->>'url' <> ''",
+		"SELECT payload->'sound'->>'url' FROM dead_jobs WHERE payload->'sound'->>'url' <> ''",
+	}
+
+	urls := map[string]struct{}{}
+	for _, q := range queries {
+		var rows []string
+		if err := db.Raw(q).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range rows {
+			urls[u] = struct{}{}
+		}
+	}
+	return urls, nil
+}
\ No newline at end of file
@@ -1,8 +1,18 @@
 package database
 
 import (
+	"context"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -14,29 +24,318 @@ import (
 //go:embed schema/*.sql
 var schemaFS embed.FS
 
-func RunMigrations(db *gorm.DB) {
-	content, err := schemaFS.ReadFile("schema/001_init_schema.sql")
+// migrationLockKey dipakai untuk pg_advisory_lock agar dua replika tidak
+// menjalankan migrasi secara bersamaan.
+const migrationLockKey int64 = 7_241_001
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var ErrMigrationDrift = errors.New("migration drift detected")
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Drift     bool
+}
+
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Checksum  string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(schemaFS, "schema")
 	if err != nil {
-		logger.Fatal("Failed to read migration file from embed", zap.Error(err))
+		return nil, err
 	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up menjalankan semua migrasi yang belum tercatat di schema_migrations.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(tx *gorm.DB) error {
+		records, err := m.records(tx)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(tx, mig); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down membatalkan sejumlah migrasi terakhir, dimulai dari versi tertinggi.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
 
-	blocks := strings.Split(string(content), "--SEPARATOR--")
+	err := m.withLock(ctx, func(tx *gorm.DB) error {
+		records, err := m.records(tx)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(records); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := records[mig.Version]; !ok {
+				continue
+			}
+
+			if err := m.revert(tx, mig); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
 
-	for _, block := range blocks {
+	return reverted, err
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(tx *gorm.DB) error {
+		records, err := m.records(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			status := MigrationStatus{Version: mig.Version, Name: mig.Name}
+			if rec, ok := records[mig.Version]; ok {
+				appliedAt := rec.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Drift = rec.Checksum != mig.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+
+	return statuses, err
+}
+
+// Verify memastikan checksum migrasi yang sudah dijalankan masih sama dengan
+// file yang di-embed. Dipanggil saat boot agar server menolak start bila ada drift.
+func (m *Migrator) Verify(ctx context.Context) error {
+	return m.withLock(ctx, func(tx *gorm.DB) error {
+		records, err := m.records(tx)
+		if err != nil {
+			return err
+		}
+		return m.verify(records)
+	})
+}
+
+func (m *Migrator) verify(records map[int]schemaMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+
+	for version, rec := range records {
+		mig, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: version %03d (%s) is applied but missing from embedded schema", ErrMigrationDrift, version, rec.Name)
+		}
+		if rec.Checksum != mig.Checksum {
+			return fmt.Errorf("%w: version %03d (%s) checksum %s does not match embedded file %s", ErrMigrationDrift, version, rec.Name, rec.Checksum, mig.Checksum)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(db *gorm.DB, mig Migration) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := execBlocks(tx, mig.Up); err != nil {
+			return fmt.Errorf("migration %03d_%s up: %w", mig.Version, mig.Name, err)
+		}
+		return tx.Create(&schemaMigration{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Checksum:  mig.Checksum,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+func (m *Migrator) revert(db *gorm.DB, mig Migration) error {
+	if strings.TrimSpace(mig.Down) == "" {
+		return fmt.Errorf("migration %03d_%s has no down file", mig.Version, mig.Name)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := execBlocks(tx, mig.Down); err != nil {
+			return fmt.Errorf("migration %03d_%s down: %w", mig.Version, mig.Name, err)
+		}
+		return tx.Delete(&schemaMigration{}, "version = ?", mig.Version).Error
+	})
+}
+
+func (m *Migrator) records(tx *gorm.DB) (map[int]schemaMigration, error) {
+	if err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := tx.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		records[row.Version] = row
+	}
+	return records, nil
+}
+
+// withLock menjalankan fn di satu koneksi yang sama sambil memegang advisory lock,
+// karena session lock Postgres terikat ke koneksi, bukan ke pool.
+func (m *Migrator) withLock(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		return fn(conn)
+	})
+}
+
+func execBlocks(tx *gorm.DB, content string) error {
+	for _, block := range strings.Split(content, "--SEPARATOR--") {
 		trimmedBlock := strings.TrimSpace(block)
 		if trimmedBlock == "" {
 			continue
 		}
 
-		if err := db.Exec(trimmedBlock).Error; err != nil {
-			logger.Fatal("Failed to execute migration block", 
-				zap.String("query_snippet", trimmedBlock[:min(len(trimmedBlock), 50)]), 
-				zap.Error(err),
-			)
+		if err := tx.Exec(trimmedBlock).Error; err != nil {
+			return fmt.Errorf("%w (query: %s)", err, trimmedBlock[:min(len(trimmedBlock), 50)])
 		}
 	}
+	return nil
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected NNN_name.up.sql or NNN_name.down.sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migration version %03d has conflicting names %q and %q", version, mig.Name, match[2])
+		}
+
+		// Line ending dinormalisasi agar checksum tidak berubah karena checkout Windows
+		normalized := strings.ReplaceAll(string(content), "\r\n", "\n")
+		if match[3] == "up" {
+			mig.Up = normalized
+			sum := sha256.Sum256([]byte(normalized))
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = normalized
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migration %03d_%s has no up file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func RunMigrations(db *gorm.DB) {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		logger.Fatal("Failed to load migrations from embed", zap.Error(err))
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		logger.Fatal("Failed to run migrations", zap.Error(err))
+	}
+
+	for _, mig := range applied {
+		logger.Info("Migration applied", zap.Int("version", mig.Version), zap.String("name", mig.Name))
+	}
 
-	logger.Info("Database migration executed successfully")
+	logger.Info("Database migration executed successfully", zap.Int("applied", len(applied)))
 }
 
 func min(a, b int) int {
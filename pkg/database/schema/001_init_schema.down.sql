@@ -0,0 +1,57 @@
+DROP PROCEDURE IF EXISTS add_slide_safe(INT, TEXT, TEXT, INT);
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_update_slide_count ON slides;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS update_slide_count();
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS update_stories_modtime ON stories;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS update_categories_modtime ON categories;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS user_choice_hadists;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS user_choice_dakwahs;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS user_choice_stories;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS recommendations;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS listening_histories;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS slides;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS chapters;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS stories;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS categories;
\ No newline at end of file
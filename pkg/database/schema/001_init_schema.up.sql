@@ -0,0 +1,261 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID,
+    name TEXT,
+    image_url TEXT,
+    dominant_color TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_uuid ON categories (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_categories_name ON categories (name);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS stories (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID,
+    title TEXT,
+    description TEXT,
+    thumbnail_url TEXT,
+    dominant_color TEXT,
+    category_id BIGINT CONSTRAINT fk_categories_stories REFERENCES categories (id),
+    user_id TEXT,
+    slide_count BIGINT DEFAULT 0,
+    status TEXT DEFAULT 'Draft',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stories_uuid ON stories (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_title ON stories (title);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_category_id ON stories (category_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_user_id ON stories (user_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_status ON stories (status);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_created_at ON stories (created_at);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS chapters (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID,
+    story_id BIGINT CONSTRAINT fk_stories_chapters REFERENCES stories (id),
+    slide_count BIGINT DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chapters_uuid ON chapters (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_chapters_story_id ON chapters (story_id);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS slides (
+    id BIGSERIAL PRIMARY KEY,
+    story_id BIGINT CONSTRAINT fk_stories_slides REFERENCES stories (id),
+    chapter_id BIGINT CONSTRAINT fk_chapters_slides REFERENCES chapters (id),
+    image_url TEXT,
+    sound_url TEXT,
+    content TEXT,
+    sequence BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_slides_story_id ON slides (story_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_slides_chapter_id ON slides (chapter_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_slides_sequence ON slides (sequence);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS listening_histories (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT,
+    story_id BIGINT,
+    duration BIGINT,
+    created_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_listening_histories_user_id ON listening_histories (user_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_listening_histories_story_id ON listening_histories (story_id);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS recommendations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT,
+    story_id BIGINT CONSTRAINT fk_recommendations_story REFERENCES stories (id),
+    score DECIMAL,
+    created_at TIMESTAMPTZ
+);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_user_id ON recommendations (user_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_story_id ON recommendations (story_id);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS user_choice_stories (
+    user_id TEXT,
+    category_id BIGINT,
+    PRIMARY KEY (user_id, category_id)
+);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS user_choice_dakwahs (
+    user_id TEXT,
+    category_id BIGINT,
+    PRIMARY KEY (user_id, category_id)
+);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS user_choice_hadists (
+    user_id TEXT,
+    category_id BIGINT,
+    PRIMARY KEY (user_id, category_id)
+);
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS update_categories_modtime ON categories;
+
+--SEPARATOR--
+
+CREATE TRIGGER update_categories_modtime BEFORE UPDATE ON categories FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS update_stories_modtime ON stories;
+
+--SEPARATOR--
+
+CREATE TRIGGER update_stories_modtime BEFORE UPDATE ON stories FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION update_slide_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE stories SET slide_count = slide_count + 1, updated_at = NOW() WHERE id = NEW.story_id;
+        RETURN NEW;
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE stories SET slide_count = slide_count - 1, updated_at = NOW() WHERE id = OLD.story_id;
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_update_slide_count ON slides;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_update_slide_count
+AFTER INSERT OR DELETE ON slides
+FOR EACH ROW EXECUTE PROCEDURE update_slide_count();
+
+--SEPARATOR--
+
+CREATE OR REPLACE PROCEDURE add_slide_safe(
+    p_story_id INT, 
+    p_image_url TEXT, 
+    p_content TEXT, 
+    p_sequence INT
+)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    current_count INT;
+BEGIN
+    SELECT count(*) INTO current_count FROM slides WHERE story_id = p_story_id;
+    
+    INSERT INTO slides (story_id, image_url, content, sequence, created_at, updated_at)
+    VALUES (p_story_id, p_image_url, p_content, p_sequence, NOW(), NOW());
+END;
+$$;
+
+--SEPARATOR--
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_catalog.pg_roles WHERE rolname = 'readonly_user') THEN
+        CREATE ROLE readonly_user WITH LOGIN PASSWORD 'readonly_password';
+    END IF;
+END
+$$;
+
+--SEPARATOR--
+
+GRANT CONNECT ON DATABASE postgres TO readonly_user;
+
+--SEPARATOR--
+
+GRANT USAGE ON SCHEMA public TO readonly_user;
+
+--SEPARATOR--
+
+GRANT SELECT ON ALL TABLES IN SCHEMA public TO readonly_user;
+
+--SEPARATOR--
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT ON TABLES TO readonly_user;
\ No newline at end of file
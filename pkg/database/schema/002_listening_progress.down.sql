@@ -0,0 +1,25 @@
+DROP INDEX IF EXISTS idx_listening_histories_user_story_created;
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_listening_histories_chapter_id;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories DROP CONSTRAINT IF EXISTS fk_listening_histories_chapter;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories DROP CONSTRAINT IF EXISTS fk_listening_histories_story;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories DROP COLUMN IF EXISTS position;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories DROP COLUMN IF EXISTS slide_sequence;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories DROP COLUMN IF EXISTS chapter_id;
\ No newline at end of file
@@ -0,0 +1,31 @@
+ALTER TABLE listening_histories ADD COLUMN IF NOT EXISTS chapter_id BIGINT;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories ADD COLUMN IF NOT EXISTS slide_sequence BIGINT NOT NULL DEFAULT 0;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories ADD COLUMN IF NOT EXISTS position BIGINT NOT NULL DEFAULT 0;
+
+--SEPARATOR--
+
+DELETE FROM listening_histories WHERE story_id IS NULL OR story_id NOT IN (SELECT id FROM stories);
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories
+    ADD CONSTRAINT fk_listening_histories_story FOREIGN KEY (story_id) REFERENCES stories (id) ON DELETE CASCADE;
+
+--SEPARATOR--
+
+ALTER TABLE listening_histories
+    ADD CONSTRAINT fk_listening_histories_chapter FOREIGN KEY (chapter_id) REFERENCES chapters (id) ON DELETE SET NULL;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_listening_histories_chapter_id ON listening_histories (chapter_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_listening_histories_user_story_created ON listening_histories (user_id, story_id, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_recommendations_user_score;
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_recommendations_user_story;
+
+--SEPARATOR--
+
+ALTER TABLE recommendations DROP CONSTRAINT IF EXISTS fk_recommendations_story;
+
+--SEPARATOR--
+
+ALTER TABLE recommendations
+    ADD CONSTRAINT fk_recommendations_story FOREIGN KEY (story_id) REFERENCES stories (id);
\ No newline at end of file
@@ -0,0 +1,23 @@
+ALTER TABLE recommendations DROP CONSTRAINT IF EXISTS fk_recommendations_story;
+
+--SEPARATOR--
+
+DELETE FROM recommendations WHERE story_id IS NULL OR story_id NOT IN (SELECT id FROM stories);
+
+--SEPARATOR--
+
+ALTER TABLE recommendations
+    ADD CONSTRAINT fk_recommendations_story FOREIGN KEY (story_id) REFERENCES stories (id) ON DELETE CASCADE;
+
+--SEPARATOR--
+
+DELETE FROM recommendations a USING recommendations b
+WHERE a.user_id = b.user_id AND a.story_id = b.story_id AND a.id < b.id;
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recommendations_user_story ON recommendations (user_id, story_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_user_score ON recommendations (user_id, score DESC);
\ No newline at end of file
@@ -0,0 +1,49 @@
+DROP INDEX IF EXISTS idx_stories_search_vector;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_categories_search ON categories;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS categories_search_trigger();
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_slides_search ON slides;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS slides_search_trigger();
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_stories_search ON stories;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS stories_search_trigger();
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS search_vector;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS refresh_story_search(BIGINT);
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS story_search_vector(BIGINT, TEXT, TEXT, BIGINT);
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS story_search_body(BIGINT);
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS search_config();
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS search_settings;
\ No newline at end of file
@@ -0,0 +1,138 @@
+CREATE TABLE IF NOT EXISTS search_settings (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    config REGCONFIG NOT NULL DEFAULT 'simple'
+);
+
+--SEPARATOR--
+
+INSERT INTO search_settings (id, config)
+SELECT 1, CASE WHEN EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'indonesian') THEN 'indonesian'::regconfig ELSE 'simple'::regconfig END
+ON CONFLICT (id) DO NOTHING;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION search_config()
+RETURNS REGCONFIG AS $$
+    SELECT config FROM search_settings WHERE id = 1;
+$$ LANGUAGE sql STABLE;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION story_search_body(p_story_id BIGINT)
+RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(sl.content, ' ' ORDER BY sl.chapter_id NULLS FIRST, sl.sequence), '')
+    FROM slides sl
+    LEFT JOIN chapters ch ON ch.id = sl.chapter_id
+    WHERE sl.story_id = p_story_id OR ch.story_id = p_story_id;
+$$ LANGUAGE sql STABLE;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION story_search_vector(p_story_id BIGINT, p_title TEXT, p_description TEXT, p_category_id BIGINT)
+RETURNS TSVECTOR AS $$
+DECLARE
+    cfg REGCONFIG := search_config();
+    category_name TEXT;
+BEGIN
+    SELECT name INTO category_name FROM categories WHERE id = p_category_id;
+
+    RETURN setweight(to_tsvector(cfg, COALESCE(p_title, '')), 'A')
+        || setweight(to_tsvector(cfg, COALESCE(p_description, '')), 'B')
+        || setweight(to_tsvector(cfg, COALESCE(category_name, '')), 'B')
+        || setweight(to_tsvector(cfg, story_search_body(p_story_id)), 'C');
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION refresh_story_search(p_story_id BIGINT)
+RETURNS VOID AS $$
+BEGIN
+    UPDATE stories
+    SET search_vector = story_search_vector(id, title, description, category_id)
+    WHERE id = p_story_id;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION stories_search_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := story_search_vector(NEW.id, NEW.title, NEW.description, NEW.category_id);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_stories_search ON stories;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_stories_search
+BEFORE INSERT OR UPDATE OF title, description, category_id ON stories
+FOR EACH ROW EXECUTE PROCEDURE stories_search_trigger();
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION slides_search_trigger()
+RETURNS TRIGGER AS $$
+DECLARE
+    affected BIGINT;
+BEGIN
+    IF (TG_OP IN ('UPDATE', 'DELETE')) THEN
+        SELECT COALESCE(OLD.story_id, (SELECT story_id FROM chapters WHERE id = OLD.chapter_id)) INTO affected;
+        PERFORM refresh_story_search(affected);
+    END IF;
+    IF (TG_OP IN ('INSERT', 'UPDATE')) THEN
+        SELECT COALESCE(NEW.story_id, (SELECT story_id FROM chapters WHERE id = NEW.chapter_id)) INTO affected;
+        PERFORM refresh_story_search(affected);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_slides_search ON slides;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_slides_search
+AFTER INSERT OR DELETE OR UPDATE OF content, story_id, chapter_id ON slides
+FOR EACH ROW EXECUTE PROCEDURE slides_search_trigger();
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION categories_search_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE stories
+    SET search_vector = story_search_vector(id, title, description, category_id)
+    WHERE category_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_categories_search ON categories;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_categories_search
+AFTER UPDATE OF name ON categories
+FOR EACH ROW EXECUTE PROCEDURE categories_search_trigger();
+
+--SEPARATOR--
+
+UPDATE stories SET search_vector = story_search_vector(id, title, description, category_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_search_vector ON stories USING GIN (search_vector);
\ No newline at end of file
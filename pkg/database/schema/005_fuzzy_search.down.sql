@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_stories_title_trgm;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS normalize_translit(TEXT);
\ No newline at end of file
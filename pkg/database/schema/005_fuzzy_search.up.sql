@@ -0,0 +1,37 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+--SEPARATOR--
+
+-- Menyeragamkan variasi transliterasi Arab-Indonesia (Rasulullah, Rasullulah,
+-- Rosululloh) sebelum dibandingkan dengan trigram. Dipakai di index dan query.
+CREATE OR REPLACE FUNCTION normalize_translit(input TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    s TEXT := lower(COALESCE(input, ''));
+BEGIN
+    s := translate(s, 'āáàâäīíìîïūúùûüēéèêëōóòôöḥṣḍṭẓġ-', 'aaaaaiiiiiuuuuueeeeeooooohsdtzg ');
+    s := regexp_replace(s, '[^a-z0-9 ]', '', 'g');
+    s := replace(s, 'oe', 'u');
+    s := replace(s, 'dj', 'j');
+    s := replace(s, 'tj', 'c');
+    s := regexp_replace(s, 's[yh]', 's', 'g');
+    s := replace(s, 'ts', 's');
+    s := replace(s, 'th', 't');
+    s := replace(s, 'dz', 'z');
+    s := replace(s, 'dh', 'd');
+    s := regexp_replace(s, '[kc]h', 'h', 'g');
+    s := replace(s, 'q', 'k');
+    s := replace(s, 'o', 'a');
+    s := regexp_replace(s, '([a-z])\1+', '\1', 'g');
+    s := regexp_replace(btrim(s), '\s+', ' ', 'g');
+    RETURN s;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_title_trgm ON stories USING GIN (normalize_translit(title) gin_trgm_ops);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm ON categories USING GIN (normalize_translit(name) gin_trgm_ops);
\ No newline at end of file
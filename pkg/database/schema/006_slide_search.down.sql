@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_slides_search_vector;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_slides_vector ON slides;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS slides_vector_trigger();
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
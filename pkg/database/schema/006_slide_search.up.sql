@@ -0,0 +1,29 @@
+ALTER TABLE slides ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION slides_vector_trigger()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := to_tsvector(search_config(), COALESCE(NEW.content, ''));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_slides_vector ON slides;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_slides_vector
+BEFORE INSERT OR UPDATE OF content ON slides
+FOR EACH ROW EXECUTE PROCEDURE slides_vector_trigger();
+
+--SEPARATOR--
+
+UPDATE slides SET search_vector = to_tsvector(search_config(), COALESCE(content, ''));
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_slides_search_vector ON slides USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_stories_published;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP CONSTRAINT IF EXISTS chk_stories_status;
+
+--SEPARATOR--
+
+ALTER TABLE stories ALTER COLUMN status DROP NOT NULL;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS archived_at;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS published_at;
\ No newline at end of file
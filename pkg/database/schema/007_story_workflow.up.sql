@@ -0,0 +1,28 @@
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+--SEPARATOR--
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+--SEPARATOR--
+
+UPDATE stories SET status = 'Draft'
+WHERE status IS NULL OR status NOT IN ('Pending_Upload', 'Draft', 'InReview', 'Published', 'Archived');
+
+--SEPARATOR--
+
+UPDATE stories SET published_at = COALESCE(updated_at, created_at, now())
+WHERE status = 'Published' AND published_at IS NULL;
+
+--SEPARATOR--
+
+ALTER TABLE stories ALTER COLUMN status SET NOT NULL;
+
+--SEPARATOR--
+
+ALTER TABLE stories ADD CONSTRAINT chk_stories_status
+    CHECK (status IN ('Pending_Upload', 'Draft', 'InReview', 'Published', 'Archived'));
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_published ON stories (created_at DESC, id DESC) WHERE status = 'Published';
\ No newline at end of file
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_stories_unpublish_at;
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_stories_publish_at;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS unpublish_at;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS publish_at;
\ No newline at end of file
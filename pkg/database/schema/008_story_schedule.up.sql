@@ -0,0 +1,13 @@
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+--SEPARATOR--
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_publish_at ON stories (publish_at) WHERE publish_at IS NOT NULL;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_unpublish_at ON stories (unpublish_at) WHERE unpublish_at IS NOT NULL;
\ No newline at end of file
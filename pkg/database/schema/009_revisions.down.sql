@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_revisions_immutable ON revisions;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS revisions_immutable();
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS revisions;
\ No newline at end of file
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS revisions (
+    id BIGSERIAL PRIMARY KEY,
+    story_id BIGINT NOT NULL CONSTRAINT fk_revisions_story REFERENCES stories (id) ON DELETE CASCADE,
+    entity_type TEXT NOT NULL,
+    entity_key TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    user_id TEXT NOT NULL DEFAULT '',
+    snapshot JSONB NOT NULL DEFAULT '{}',
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_revisions_entity_version ON revisions (entity_type, entity_key, version);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_revisions_story_id ON revisions (story_id, id DESC);
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION revisions_immutable() RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'revisions are immutable';
+END;
+$$;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_revisions_immutable ON revisions;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_revisions_immutable
+    BEFORE UPDATE ON revisions
+    FOR EACH ROW EXECUTE FUNCTION revisions_immutable();
\ No newline at end of file
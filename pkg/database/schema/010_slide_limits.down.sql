@@ -0,0 +1,29 @@
+DROP FUNCTION IF EXISTS add_slide_safe(BIGINT, BIGINT, TEXT, TEXT, TEXT, INT, INT);
+
+--SEPARATOR--
+
+CREATE OR REPLACE PROCEDURE add_slide_safe(
+    p_story_id INT, 
+    p_image_url TEXT, 
+    p_content TEXT, 
+    p_sequence INT
+)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    current_count INT;
+BEGIN
+    SELECT count(*) INTO current_count FROM slides WHERE story_id = p_story_id;
+    
+    INSERT INTO slides (story_id, image_url, content, sequence, created_at, updated_at)
+    VALUES (p_story_id, p_image_url, p_content, p_sequence, NOW(), NOW());
+END;
+$$;
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS uq_slides_chapter_sequence;
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS uq_slides_story_sequence;
\ No newline at end of file
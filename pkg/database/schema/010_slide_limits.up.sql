@@ -0,0 +1,91 @@
+-- Sequence ganda dari upload bersamaan dirapikan dulu, hanya untuk story/chapter yang
+-- memang punya duplikat, dengan urutan lama (sequence, id) dipertahankan.
+UPDATE slides s SET sequence = r.rn
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY story_id ORDER BY sequence, id) AS rn
+    FROM slides
+    WHERE story_id IN (
+        SELECT story_id FROM slides WHERE story_id IS NOT NULL GROUP BY story_id, sequence HAVING count(*) > 1
+    )
+) r
+WHERE s.id = r.id;
+
+--SEPARATOR--
+
+UPDATE slides s SET sequence = r.rn
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY chapter_id ORDER BY sequence, id) AS rn
+    FROM slides
+    WHERE chapter_id IN (
+        SELECT chapter_id FROM slides WHERE chapter_id IS NOT NULL GROUP BY chapter_id, sequence HAVING count(*) > 1
+    )
+) r
+WHERE s.id = r.id;
+
+--SEPARATOR--
+
+-- DEFERRABLE agar keunikan dicek di akhir statement, sehingga reorder yang menukar
+-- sequence dalam satu UPDATE tidak bentrok dengan baris yang belum diubah.
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS uq_slides_story_sequence;
+
+--SEPARATOR--
+
+ALTER TABLE slides ADD CONSTRAINT uq_slides_story_sequence UNIQUE (story_id, sequence) DEFERRABLE INITIALLY IMMEDIATE;
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS uq_slides_chapter_sequence;
+
+--SEPARATOR--
+
+ALTER TABLE slides ADD CONSTRAINT uq_slides_chapter_sequence UNIQUE (chapter_id, sequence) DEFERRABLE INITIALLY IMMEDIATE;
+
+--SEPARATOR--
+
+DROP PROCEDURE IF EXISTS add_slide_safe(INT, TEXT, TEXT, INT);
+
+--SEPARATOR--
+
+-- add_slide_safe mengunci baris story/chapter induk sehingga upload bersamaan antre,
+-- lalu menolak insert jika jumlah slide sudah mencapai p_limit. slide_count story
+-- diperbarui trigger trg_update_slide_count, slide_count chapter diperbarui di sini.
+CREATE OR REPLACE FUNCTION add_slide_safe(
+    p_story_id BIGINT,
+    p_chapter_id BIGINT,
+    p_image_url TEXT,
+    p_sound_url TEXT,
+    p_content TEXT,
+    p_sequence INT,
+    p_limit INT
+)
+RETURNS slides
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    current_count INT;
+    new_slide slides;
+BEGIN
+    IF p_chapter_id IS NOT NULL THEN
+        PERFORM 1 FROM chapters WHERE id = p_chapter_id FOR UPDATE;
+        SELECT count(*) INTO current_count FROM slides WHERE chapter_id = p_chapter_id;
+    ELSE
+        PERFORM 1 FROM stories WHERE id = p_story_id FOR UPDATE;
+        SELECT count(*) INTO current_count FROM slides WHERE story_id = p_story_id;
+    END IF;
+
+    IF current_count >= p_limit THEN
+        RAISE EXCEPTION 'slide limit of % reached', p_limit
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'chk_slide_limit';
+    END IF;
+
+    INSERT INTO slides (story_id, chapter_id, image_url, sound_url, content, sequence, created_at, updated_at)
+    VALUES (p_story_id, p_chapter_id, p_image_url, p_sound_url, p_content, p_sequence, NOW(), NOW())
+    RETURNING * INTO new_slide;
+
+    IF p_chapter_id IS NOT NULL THEN
+        UPDATE chapters SET slide_count = slide_count + 1 WHERE id = p_chapter_id;
+    END IF;
+
+    RETURN new_slide;
+END;
+$$;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE chapters DROP CONSTRAINT IF EXISTS chk_chapters_status;
+
+--SEPARATOR--
+
+ALTER TABLE chapters DROP CONSTRAINT IF EXISTS uq_chapters_story_position;
+
+--SEPARATOR--
+
+ALTER TABLE chapters
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS duration_seconds,
+    DROP COLUMN IF EXISTS cover_url,
+    DROP COLUMN IF EXISTS synopsis,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS title;
\ No newline at end of file
@@ -0,0 +1,43 @@
+ALTER TABLE chapters
+    ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS position INTEGER,
+    ADD COLUMN IF NOT EXISTS synopsis TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS cover_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS duration_seconds INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'Draft',
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+--SEPARATOR--
+
+-- Urutan chapter lama mengikuti urutan pembuatannya
+UPDATE chapters c SET position = r.rn
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY story_id ORDER BY created_at, id) AS rn
+    FROM chapters
+) r
+WHERE c.id = r.id AND c.position IS NULL;
+
+--SEPARATOR--
+
+-- Chapter yang sudah ada sebelumnya tampil publik, jadi dianggap sudah terbit
+UPDATE chapters SET status = 'Published', published_at = COALESCE(created_at, now()) WHERE published_at IS NULL;
+
+--SEPARATOR--
+
+ALTER TABLE chapters ALTER COLUMN position SET NOT NULL;
+
+--SEPARATOR--
+
+ALTER TABLE chapters DROP CONSTRAINT IF EXISTS uq_chapters_story_position;
+
+--SEPARATOR--
+
+ALTER TABLE chapters ADD CONSTRAINT uq_chapters_story_position UNIQUE (story_id, position) DEFERRABLE INITIALLY IMMEDIATE;
+
+--SEPARATOR--
+
+ALTER TABLE chapters DROP CONSTRAINT IF EXISTS chk_chapters_status;
+
+--SEPARATOR--
+
+ALTER TABLE chapters ADD CONSTRAINT chk_chapters_status CHECK (status IN ('Draft', 'Published'));
\ No newline at end of file
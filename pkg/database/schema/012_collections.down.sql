@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS collection_stories;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS collections;
\ No newline at end of file
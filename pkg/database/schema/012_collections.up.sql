@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    type TEXT NOT NULL DEFAULT 'series',
+    image_url TEXT NOT NULL DEFAULT '',
+    dominant_color TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_collections_type CHECK (type IN ('series', 'collection'))
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_uuid ON collections (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_collections_created_at ON collections (created_at, id);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS collection_stories (
+    collection_id BIGINT NOT NULL CONSTRAINT fk_collection_stories_collection REFERENCES collections (id) ON DELETE CASCADE,
+    story_id BIGINT NOT NULL CONSTRAINT fk_collection_stories_story REFERENCES stories (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, story_id),
+    CONSTRAINT uq_collection_stories_position UNIQUE (collection_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_collection_stories_story_id ON collection_stories (story_id);
\ No newline at end of file
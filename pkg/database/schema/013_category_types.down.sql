@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_categories_parent_id;
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_categories_type;
+
+--SEPARATOR--
+
+ALTER TABLE categories
+    DROP CONSTRAINT IF EXISTS chk_categories_parent,
+    DROP CONSTRAINT IF EXISTS chk_categories_type,
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS type;
\ No newline at end of file
@@ -0,0 +1,45 @@
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'story',
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT CONSTRAINT fk_categories_parent REFERENCES categories (id) ON DELETE SET NULL;
+
+--SEPARATOR--
+
+-- Kategori yang sejauh ini hanya dipilih di satu section lain (dan tidak dipakai story)
+-- dipindahkan ke section tersebut, sisanya tetap story
+UPDATE categories c SET type = 'dakwah'
+WHERE EXISTS (SELECT 1 FROM user_choice_dakwahs d WHERE d.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM user_choice_hadists h WHERE h.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM user_choice_stories s WHERE s.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM stories st WHERE st.category_id = c.id);
+
+--SEPARATOR--
+
+UPDATE categories c SET type = 'hadist'
+WHERE EXISTS (SELECT 1 FROM user_choice_hadists h WHERE h.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM user_choice_dakwahs d WHERE d.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM user_choice_stories s WHERE s.category_id = c.id)
+  AND NOT EXISTS (SELECT 1 FROM stories st WHERE st.category_id = c.id);
+
+--SEPARATOR--
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_type;
+
+--SEPARATOR--
+
+ALTER TABLE categories ADD CONSTRAINT chk_categories_type CHECK (type IN ('story', 'dakwah', 'hadist'));
+
+--SEPARATOR--
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_parent;
+
+--SEPARATOR--
+
+ALTER TABLE categories ADD CONSTRAINT chk_categories_parent CHECK (parent_id IS NULL OR parent_id <> id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_categories_type ON categories (type, name);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS favourites;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS favourite_folders;
\ No newline at end of file
@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS favourite_folders (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favourite_folders_uuid ON favourite_folders (uuid);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_favourite_folders_user_name ON favourite_folders (user_id, lower(name));
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS favourites (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id BIGINT NOT NULL CONSTRAINT fk_favourites_story REFERENCES stories (id) ON DELETE CASCADE,
+    chapter_id BIGINT CONSTRAINT fk_favourites_chapter REFERENCES chapters (id) ON DELETE CASCADE,
+    folder_id BIGINT CONSTRAINT fk_favourites_folder REFERENCES favourite_folders (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_favourites_uuid ON favourites (uuid);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_favourites_user_story ON favourites (user_id, story_id) WHERE chapter_id IS NULL;
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_favourites_user_chapter ON favourites (user_id, chapter_id) WHERE chapter_id IS NOT NULL;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_favourites_user_created_at ON favourites (user_id, created_at, id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_favourites_story_id ON favourites (story_id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_favourites_folder_id ON favourites (folder_id);
\ No newline at end of file
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS reviews;
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS refresh_story_rating();
+
+--SEPARATOR--
+
+DROP FUNCTION IF EXISTS recalculate_story_rating(BIGINT);
+
+--SEPARATOR--
+
+DROP INDEX IF EXISTS idx_stories_rating_average;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS rating_count;
+
+--SEPARATOR--
+
+ALTER TABLE stories DROP COLUMN IF EXISTS rating_average;
\ No newline at end of file
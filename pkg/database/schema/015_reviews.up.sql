@@ -0,0 +1,88 @@
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS rating_average DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+--SEPARATOR--
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_stories_rating_average ON stories (rating_average, id);
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    story_id BIGINT NOT NULL CONSTRAINT fk_reviews_story REFERENCES stories (id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    rating SMALLINT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    flagged_terms TEXT NOT NULL DEFAULT '',
+    moderation_reason TEXT NOT NULL DEFAULT '',
+    moderated_by TEXT NOT NULL DEFAULT '',
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT uq_reviews_story_user UNIQUE (story_id, user_id),
+    CONSTRAINT chk_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+    CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'approved', 'rejected', 'hidden'))
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_uuid ON reviews (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_reviews_story_status ON reviews (story_id, status, created_at, id);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_reviews_status_created_at ON reviews (status, created_at, id);
+
+--SEPARATOR--
+
+-- Rating story dihitung ulang dari ulasan approved setiap kali ulasan berubah, sehingga
+-- moderasi langsung tercermin di rating_average dan rating_count.
+CREATE OR REPLACE FUNCTION recalculate_story_rating(p_story_id BIGINT)
+RETURNS VOID AS $$
+BEGIN
+    UPDATE stories s SET
+        rating_average = COALESCE(r.average, 0),
+        rating_count = r.total
+    FROM (
+        SELECT ROUND(AVG(rating)::numeric, 2)::double precision AS average, COUNT(*) AS total
+        FROM reviews WHERE story_id = p_story_id AND status = 'approved'
+    ) r
+    WHERE s.id = p_story_id;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+CREATE OR REPLACE FUNCTION refresh_story_rating()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'DELETE') THEN
+        PERFORM recalculate_story_rating(OLD.story_id);
+        RETURN OLD;
+    END IF;
+
+    PERFORM recalculate_story_rating(NEW.story_id);
+    IF (TG_OP = 'UPDATE' AND OLD.story_id <> NEW.story_id) THEN
+        PERFORM recalculate_story_rating(OLD.story_id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+--SEPARATOR--
+
+DROP TRIGGER IF EXISTS trg_refresh_story_rating ON reviews;
+
+--SEPARATOR--
+
+CREATE TRIGGER trg_refresh_story_rating
+AFTER INSERT OR UPDATE OF rating, status, story_id OR DELETE ON reviews
+FOR EACH ROW EXECUTE PROCEDURE refresh_story_rating();
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    user_id TEXT NOT NULL DEFAULT '',
+    format TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL DEFAULT 'pending',
+    payload BYTEA,
+    archive BYTEA,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    succeeded_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    report JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    CONSTRAINT chk_import_jobs_format CHECK (format IN ('csv', 'json')),
+    CONSTRAINT chk_import_jobs_status CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_import_jobs_uuid ON import_jobs (uuid);
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status ON import_jobs (status, id);
\ No newline at end of file
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS dead_jobs;
+
+--SEPARATOR--
+
+DROP TABLE IF EXISTS jobs;
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS chk_slides_status;
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,57 @@
+ALTER TABLE slides ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'ready';
+
+--SEPARATOR--
+
+ALTER TABLE slides DROP CONSTRAINT IF EXISTS chk_slides_status;
+
+--SEPARATOR--
+
+ALTER TABLE slides ADD CONSTRAINT chk_slides_status CHECK (status IN ('processing', 'ready', 'failed'));
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    uuid UUID NOT NULL,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error TEXT NOT NULL DEFAULT '',
+    locked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ,
+    CONSTRAINT chk_jobs_status CHECK (status IN ('pending', 'running', 'completed', 'dead'))
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_uuid ON jobs (uuid);
+
+--SEPARATOR--
+
+-- Hanya job yang masih menunggu yang perlu diindeks untuk claim worker.
+CREATE INDEX IF NOT EXISTS idx_jobs_pending_run_at ON jobs (run_at, id) WHERE status = 'pending';
+
+--SEPARATOR--
+
+CREATE INDEX IF NOT EXISTS idx_jobs_running_locked_at ON jobs (locked_at) WHERE status = 'running';
+
+--SEPARATOR--
+
+CREATE TABLE IF NOT EXISTS dead_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL CONSTRAINT fk_dead_jobs_job REFERENCES jobs (id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+--SEPARATOR--
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_dead_jobs_job ON dead_jobs (job_id);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE slides DROP COLUMN IF EXISTS job_id;
\ No newline at end of file
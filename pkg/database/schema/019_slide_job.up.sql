@@ -0,0 +1 @@
+ALTER TABLE slides ADD COLUMN IF NOT EXISTS job_id TEXT NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS recommendation_requests;
\ No newline at end of file
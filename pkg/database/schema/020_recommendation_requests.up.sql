@@ -0,0 +1,6 @@
+-- User yang perhitungan rekomendasinya sudah diantrekan dari GetForUser, agar user yang
+-- hasil hitungnya memang kosong tidak diantrekan lagi di setiap permintaan.
+CREATE TABLE IF NOT EXISTS recommendation_requests (
+    user_id TEXT PRIMARY KEY,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
\ No newline at end of file
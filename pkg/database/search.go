@@ -0,0 +1,42 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+)
+
+// SyncSearchConfig menyimpan dictionary text search (mis. "indonesian" atau "simple")
+// yang dipakai trigger pencarian. Return true jika dictionary berubah sehingga
+// search_vector perlu dibangun ulang.
+func SyncSearchConfig(db *gorm.DB, language string) (bool, error) {
+	var exists bool
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", language).Scan(&exists).Error; err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, fmt.Errorf("text search configuration %q is not installed", language)
+	}
+
+	var current string
+	if err := db.Raw("SELECT config::text FROM search_settings WHERE id = 1").Scan(&current).Error; err != nil {
+		return false, err
+	}
+	if current == language {
+		return false, nil
+	}
+
+	err := db.Exec("UPDATE search_settings SET config = ?::regconfig WHERE id = 1", language).Error
+	return err == nil, err
+}
+
+// RebuildSearchIndex menghitung ulang search_vector semua story dan slide.
+func RebuildSearchIndex(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE slides SET search_vector = to_tsvector(search_config(), COALESCE(content, ''))").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE stories SET search_vector = story_search_vector(id, title, description, category_id)").Error
+	})
+}
\ No newline at end of file
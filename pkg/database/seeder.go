@@ -1,52 +1,62 @@
 package database
 
 import (
+	"embed"
 	"encoding/json"
-	"fmt"
-	"io"
-	"os"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
-	"khalif-stories/internal/domain"
+	"khalif-stories/pkg/logger"
 
 )
 
-func SeedCategories(db *gorm.DB) {
+//go:embed seed/*.json
+var seedFS embed.FS
+
+// seedCategory adalah bentuk baris di seed/categories.json. ID ikut disimpan agar
+// kategori awal punya ID yang sama di semua environment.
+type seedCategory struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func SeedCategories(db *gorm.DB) error {
 	var count int64
-	db.Raw("SELECT count(*) FROM categories").Scan(&count)
+	if err := db.Raw("SELECT count(*) FROM categories").Scan(&count).Error; err != nil {
+		return err
+	}
 
 	if count > 0 {
-		return
+		return nil
 	}
 
-	jsonFile, err := os.Open("seeds/categories.json")
+	byteValue, err := seedFS.ReadFile("seed/categories.json")
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
-	defer jsonFile.Close()
-
-	byteValue, _ := io.ReadAll(jsonFile)
 
-	var categories []domain.Category
+	var categories []seedCategory
 	if err := json.Unmarshal(byteValue, &categories); err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
-	tx := db.Begin()
+	query := "INSERT INTO categories (id, uuid, name, created_at, updated_at) VALUES (?, ?, ?, NOW(), NOW()) ON CONFLICT (id) DO NOTHING"
 
-	query := "INSERT INTO categories (id, name, created_at, updated_at) VALUES (?, ?, NOW(), NOW()) ON CONFLICT (id) DO NOTHING"
-
-	for _, cat := range categories {
-		if err := tx.Exec(query, cat.ID, cat.Name).Error; err != nil {
-			tx.Rollback()
-			fmt.Println(err)
-			return
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, cat := range categories {
+			if err := tx.Exec(query, cat.ID, uuid.New().String(), cat.Name).Error; err != nil {
+				return err
+			}
 		}
+
+		// ID di-insert manual, sequence harus dimajukan agar Create berikutnya tidak bentrok
+		return tx.Exec("SELECT setval(pg_get_serial_sequence('categories', 'id'), (SELECT COALESCE(MAX(id), 1) FROM categories))").Error
+	})
+	if err != nil {
+		return err
 	}
 
-	tx.Commit()
-	fmt.Println("Seeding Categories finished")
+	logger.Info("Seeding categories finished")
+	return nil
 }
\ No newline at end of file
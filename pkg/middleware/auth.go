@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
+	"khalif-stories/internal/domain"
+
 )
 
 func AuthMiddleware(secretKey string) gin.HandlerFunc {
@@ -18,26 +20,56 @@ func AuthMiddleware(secretKey string) gin.HandlerFunc {
 			return
 		}
 
-		tokenString := strings.Split(authHeader, " ")[1]
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method")
-			}
-			return []byte(secretKey), nil
-		})
-
+		token, err := parseToken(authHeader, secretKey)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token"})
 			return
 		}
 
 		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("user_id", claims["user_id"])
-			c.Set("role", claims["role"])
+			setClaims(c, claims)
 			c.Next()
 		} else {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid Token Claims"})
 		}
 	}
+}
+
+// OptionalAuth dipakai endpoint publik yang responsnya bisa dipersonalisasi. Request
+// tanpa token atau dengan token yang tidak valid tetap dilayani sebagai tamu.
+func OptionalAuth(secretKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if strings.Contains(authHeader, "Bearer") {
+			if token, err := parseToken(authHeader, secretKey); err == nil && token.Valid {
+				if claims, ok := token.Claims.(jwt.MapClaims); ok {
+					setClaims(c, claims)
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+func parseToken(authHeader, secretKey string) (*jwt.Token, error) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("missing token")
+	}
+
+	return jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method")
+		}
+		return []byte(secretKey), nil
+	})
+}
+
+func setClaims(c *gin.Context, claims jwt.MapClaims) {
+	c.Set("user_id", claims["user_id"])
+	c.Set("role", claims["role"])
+	// user id juga dibawa di context request agar usecase bisa mencatat pelaku perubahan
+	if userID, ok := claims["user_id"].(string); ok {
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), userID))
+	}
 }
\ No newline at end of file
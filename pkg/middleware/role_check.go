@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 
@@ -22,6 +23,27 @@ func OnlyAdmin() gin.HandlerFunc {
 			return
 		}
 
+		c.Next()
+	}
+}
+
+// RequireRole meloloskan request jika role pada token termasuk salah satu roles.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		name, _ := role.(string)
+		if !slices.Contains(roles, name) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Access denied.",
+			})
+			return
+		}
+
 		c.Next()
 	}
 }
\ No newline at end of file
@@ -2,10 +2,13 @@ package utils
 
 import (
 	"context"
-	"mime/multipart"
+	"io"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+
+	"khalif-stories/internal/domain"
 
 )
 
@@ -22,24 +25,23 @@ func NewAzureUploader(connStr, containerName string) (*AzureUploader, error) {
 	return &AzureUploader{Client: client, ContainerName: containerName}, nil
 }
 
-func (a *AzureUploader) Upload(file multipart.File, header *multipart.FileHeader) (string, error) {
-	return a.UploadToContainer(context.Background(), file, a.ContainerName, header.Filename)
+func (a *AzureUploader) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return a.UploadToContainer(ctx, file, a.ContainerName, filename)
 }
 
-func (a *AzureUploader) Delete(fileURL string) error {
-	blobName := ExtractBlobName(fileURL, a.ContainerName)
-	if blobName == "" {
+func (a *AzureUploader) Delete(ctx context.Context, fileURL string) error {
+	return a.DeleteFromContainer(ctx, a.ContainerName, fileURL)
+}
+
+func (a *AzureUploader) EnsureContainer(ctx context.Context, containerName string) error {
+	_, err := a.Client.CreateContainer(ctx, containerName, nil)
+	if err != nil && bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
 		return nil
 	}
-	_, err := a.Client.DeleteBlob(context.Background(), a.ContainerName, blobName, nil)
 	return err
 }
 
-func (a *AzureUploader) UploadFile(ctx context.Context, file multipart.File, filename string) (string, error) {
-	return a.UploadToContainer(ctx, file, a.ContainerName, filename)
-}
-
-func (a *AzureUploader) UploadToContainer(ctx context.Context, file multipart.File, containerName, filename string) (string, error) {
+func (a *AzureUploader) UploadToContainer(ctx context.Context, file io.Reader, containerName, filename string) (string, error) {
 	_, err := a.Client.UploadStream(ctx, containerName, filename, file, nil)
 	if err != nil {
 		return "", err
@@ -62,6 +64,45 @@ func (a *AzureUploader) DeleteFromContainer(ctx context.Context, containerName,
 	return err
 }
 
+func (a *AzureUploader) ListContainer(ctx context.Context, containerName string) ([]domain.StoredObject, error) {
+	baseURL := a.Client.URL()
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	var objects []domain.StoredObject
+	pager := a.Client.NewListBlobsFlatPager(containerName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			obj := domain.StoredObject{URL: baseURL + containerName + "/" + *item.Name}
+			if item.Properties != nil && item.Properties.LastModified != nil {
+				obj.ModifiedAt = *item.Properties.LastModified
+			}
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+func (a *AzureUploader) OpenFromContainer(ctx context.Context, containerName, fileURL string) (io.ReadCloser, error) {
+	blobName := ExtractBlobName(fileURL, containerName)
+	if blobName == "" {
+		return nil, domain.ErrNotFound
+	}
+	resp, err := a.Client.DownloadStream(ctx, containerName, blobName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 func ExtractBlobName(fullURL, containerName string) string {
 	parts := strings.Split(fullURL, containerName+"/")
 	if len(parts) > 1 {
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+)
+
+// leetReplacer menormalkan variasi penulisan yang umum dipakai untuk mengakali filter.
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+// KeywordFilter mencocokkan teks dengan daftar kata atau frasa terlarang per kata utuh,
+// tanpa membedakan huruf besar kecil, sehingga "kasar" tidak cocok dengan "kekasaran".
+type KeywordFilter struct {
+	terms  []string
+	tokens [][]string
+}
+
+func NewKeywordFilter(terms []string) *KeywordFilter {
+	f := &KeywordFilter{}
+	for _, term := range terms {
+		tokens := tokenize(term)
+		if len(tokens) == 0 {
+			continue
+		}
+		f.terms = append(f.terms, strings.TrimSpace(term))
+		f.tokens = append(f.tokens, tokens)
+	}
+	return f
+}
+
+func tokenize(text string) []string {
+	text = leetReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Match mengembalikan kata terlarang yang ditemukan, masing-masing satu kali sesuai
+// urutan daftar.
+func (f *KeywordFilter) Match(text string) []string {
+	if f == nil || len(f.terms) == 0 {
+		return nil
+	}
+
+	words := tokenize(text)
+	var found []string
+	for i, term := range f.tokens {
+		if containsSequence(words, term) {
+			found = append(found, f.terms[i])
+		}
+	}
+	return found
+}
+
+func containsSequence(words, seq []string) bool {
+	for i := 0; i+len(seq) <= len(words); i++ {
+		match := true
+		for j := range seq {
+			if words[i+j] != seq[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
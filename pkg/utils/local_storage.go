@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"khalif-stories/internal/domain"
+
+)
+
+// LocalUploader menyimpan file di filesystem lokal. File disajikan lewat
+// static route gin, sehingga BaseURL harus menunjuk ke route tersebut.
+type LocalUploader struct {
+	BasePath      string
+	BaseURL       string
+	ContainerName string
+}
+
+func NewLocalUploader(basePath, baseURL, containerName string) (*LocalUploader, error) {
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalUploader{
+		BasePath:      basePath,
+		BaseURL:       strings.TrimSuffix(baseURL, "/"),
+		ContainerName: containerName,
+	}, nil
+}
+
+func (l *LocalUploader) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return l.UploadToContainer(ctx, file, l.ContainerName, filename)
+}
+
+func (l *LocalUploader) Delete(ctx context.Context, fileURL string) error {
+	return l.DeleteFromContainer(ctx, l.ContainerName, fileURL)
+}
+
+func (l *LocalUploader) EnsureContainer(ctx context.Context, containerName string) error {
+	return os.MkdirAll(filepath.Join(l.BasePath, containerName), 0o755)
+}
+
+func (l *LocalUploader) UploadToContainer(ctx context.Context, file io.Reader, containerName, filename string) (string, error) {
+	target, err := l.resolve(containerName, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, file); err != nil {
+		os.Remove(target)
+		return "", err
+	}
+
+	return l.BaseURL + "/" + containerName + "/" + filename, nil
+}
+
+func (l *LocalUploader) DeleteFromContainer(ctx context.Context, containerName, fileURL string) error {
+	blobName := ExtractBlobName(fileURL, containerName)
+	if blobName == "" {
+		return nil
+	}
+
+	target, err := l.resolve(containerName, blobName)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *LocalUploader) ListContainer(ctx context.Context, containerName string) ([]domain.StoredObject, error) {
+	root := filepath.Join(l.BasePath, containerName)
+
+	var objects []domain.StoredObject
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, domain.StoredObject{
+			URL:        l.BaseURL + "/" + containerName + "/" + filepath.ToSlash(rel),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	return objects, err
+}
+
+func (l *LocalUploader) OpenFromContainer(ctx context.Context, containerName, fileURL string) (io.ReadCloser, error) {
+	blobName := ExtractBlobName(fileURL, containerName)
+	if blobName == "" {
+		return nil, domain.ErrNotFound
+	}
+
+	target, err := l.resolve(containerName, blobName)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(target)
+}
+
+// resolve memastikan path hasil join tetap berada di dalam direktori container
+func (l *LocalUploader) resolve(containerName, filename string) (string, error) {
+	root := filepath.Join(l.BasePath, containerName)
+	target := filepath.Join(root, filepath.FromSlash(filename))
+	if target != root && !strings.HasPrefix(target, root+string(os.PathSeparator)) {
+		return "", errors.New("invalid file path")
+	}
+	return target, nil
+}
\ No newline at end of file
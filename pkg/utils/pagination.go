@@ -1,41 +1,175 @@
 package utils
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
-	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
+	"khalif-stories/internal/domain"
+
+)
+
+// EncodeCursor membungkus posisi paginasi menjadi string opaque untuk client.
+func EncodeCursor(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(cursor string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// FilterKind menentukan cara nilai filter divalidasi dan dinormalisasi.
+type FilterKind int
+
+const (
+	FilterString FilterKind = iota
+	FilterUUID
+	FilterBool
+	FilterEnum
 )
 
-type Pagination struct {
-	Limit int    `json:"limit"`
-	Page  int    `json:"page"`
-	Sort  string `json:"sort"`
+type FilterRule struct {
+	Kind   FilterKind
+	Values []string
+}
+
+// ListSpec adalah whitelist field sort dan filter untuk satu endpoint listing.
+type ListSpec struct {
+	SortFields   []string
+	Filters      map[string]FilterRule
+	DefaultSort  []domain.SortField
+	DefaultLimit int
+	MaxLimit     int
 }
 
-func GeneratePaginationFromRequest(c *gin.Context) Pagination {
-	limit := 10
-	page := 1
-	sort := "created_at desc"
+// QueryError menandakan parameter listing yang tidak dikenal atau tidak valid.
+type QueryError struct {
+	Param  string
+	Reason string
+}
 
-	query := c.Request.URL.Query()
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query parameter %q: %s", e.Param, e.Reason)
+}
 
-	for key, value := range query {
-		queryValue := value[len(value)-1]
+// ParseListQuery membaca grammar listing:
+//
+//	limit=20&cursor=<next_cursor dari halaman sebelumnya>&with_total=true
+//	sort=-created_at,title          (awalan "-" berarti descending)
+//	filter[category]=<uuid>&filter[has_audio]=true
+//
+// Field sort dan filter di luar spec ditolak dengan *QueryError.
+func ParseListQuery(values url.Values, spec ListSpec) (domain.ListQuery, error) {
+	q := domain.ListQuery{
+		Limit:   spec.DefaultLimit,
+		Sort:    spec.DefaultSort,
+		Filters: map[string]string{},
+	}
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
 
-		switch key {
-		case "limit":
-			limit, _ = strconv.Atoi(queryValue)
-		case "page":
-			page, _ = strconv.Atoi(queryValue)
-		case "sort":
-			sort = queryValue
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return q, &QueryError{Param: "limit", Reason: "must be a positive integer"}
 		}
+		q.Limit = limit
+	}
+	if spec.MaxLimit > 0 && q.Limit > spec.MaxLimit {
+		q.Limit = spec.MaxLimit
+	}
+	q.Cursor = values.Get("cursor")
+	if v := values.Get("with_total"); v != "" {
+		withTotal, err := strconv.ParseBool(v)
+		if err != nil {
+			return q, &QueryError{Param: "with_total", Reason: "must be true or false"}
+		}
+		q.WithTotal = withTotal
 	}
 
-	return Pagination{
-		Limit: limit,
-		Page:  page,
-		Sort:  sort,
+	if v, ok := values["sort"]; ok {
+		sort, err := parseSort(v[len(v)-1], spec.SortFields)
+		if err != nil {
+			return q, err
+		}
+		q.Sort = sort
+	}
+
+	for key, v := range values {
+		if !strings.HasPrefix(key, "filter") {
+			continue
+		}
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") {
+			return q, &QueryError{Param: key, Reason: "expected filter[field]"}
+		}
+		name := key[len("filter[") : len(key)-1]
+		rule, ok := spec.Filters[name]
+		if !ok {
+			return q, &QueryError{Param: key, Reason: "unknown filter field"}
+		}
+		value, err := normalizeFilter(rule, strings.TrimSpace(v[len(v)-1]))
+		if err != nil {
+			return q, &QueryError{Param: key, Reason: err.Error()}
+		}
+		q.Filters[name] = value
+	}
+
+	return q, nil
+}
+
+func parseSort(raw string, allowed []string) ([]domain.SortField, error) {
+	var fields []domain.SortField
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		field := domain.SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if field.Field == "" || !slices.Contains(allowed, field.Field) {
+			return nil, &QueryError{Param: "sort", Reason: fmt.Sprintf("unknown sort field %q", part)}
+		}
+		if seen[field.Field] {
+			return nil, &QueryError{Param: "sort", Reason: fmt.Sprintf("duplicate sort field %q", field.Field)}
+		}
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func normalizeFilter(rule FilterRule, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("value is required")
+	}
+
+	switch rule.Kind {
+	case FilterUUID:
+		if _, err := uuid.Parse(value); err != nil {
+			return "", fmt.Errorf("must be a UUID")
+		}
+	case FilterBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("must be true or false")
+		}
+		return strconv.FormatBool(b), nil
+	case FilterEnum:
+		for _, allowed := range rule.Values {
+			if strings.EqualFold(allowed, value) {
+				return allowed, nil
+			}
+		}
+		return "", fmt.Errorf("must be one of %s", strings.Join(rule.Values, ", "))
 	}
+	return value, nil
 }
\ No newline at end of file
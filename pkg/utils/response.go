@@ -32,4 +32,11 @@ func ErrorResponse(c *gin.Context, code int, message string) {
 	c.JSON(code, APIResponse{
 		Error: message,
 	})
+}
+
+func ErrorResponseWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(code, APIResponse{
+		Error: message,
+		Data:  data,
+	})
 }
\ No newline at end of file
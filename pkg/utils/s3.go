@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"khalif-stories/internal/domain"
+
+)
+
+// S3Uploader bekerja dengan AWS S3 maupun server S3-compatible seperti MinIO.
+// Setiap container dipetakan ke satu bucket.
+type S3Uploader struct {
+	Client        *minio.Client
+	BaseURL       string
+	ContainerName string
+	Region        string
+}
+
+func NewS3Uploader(endpoint, accessKey, secretKey, region string, useSSL bool, publicURL, containerName string) (*S3Uploader, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+		Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := publicURL
+	if baseURL == "" {
+		baseURL = client.EndpointURL().String()
+	}
+
+	return &S3Uploader{
+		Client:        client,
+		BaseURL:       strings.TrimSuffix(baseURL, "/"),
+		ContainerName: containerName,
+		Region:        region,
+	}, nil
+}
+
+func (s *S3Uploader) Upload(ctx context.Context, file io.Reader, filename string) (string, error) {
+	return s.UploadToContainer(ctx, file, s.ContainerName, filename)
+}
+
+func (s *S3Uploader) Delete(ctx context.Context, fileURL string) error {
+	return s.DeleteFromContainer(ctx, s.ContainerName, fileURL)
+}
+
+func (s *S3Uploader) EnsureContainer(ctx context.Context, containerName string) error {
+	exists, err := s.Client.BucketExists(ctx, containerName)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return s.Client.MakeBucket(ctx, containerName, minio.MakeBucketOptions{Region: s.Region})
+}
+
+func (s *S3Uploader) UploadToContainer(ctx context.Context, file io.Reader, containerName, filename string) (string, error) {
+	opts := minio.PutObjectOptions{
+		ContentType: mime.TypeByExtension(filepath.Ext(filename)),
+	}
+
+	if _, err := s.Client.PutObject(ctx, containerName, filename, file, -1, opts); err != nil {
+		return "", err
+	}
+
+	return s.BaseURL + "/" + containerName + "/" + filename, nil
+}
+
+func (s *S3Uploader) DeleteFromContainer(ctx context.Context, containerName, fileURL string) error {
+	blobName := ExtractBlobName(fileURL, containerName)
+	if blobName == "" {
+		return nil
+	}
+	return s.Client.RemoveObject(ctx, containerName, blobName, minio.RemoveObjectOptions{})
+}
+
+func (s *S3Uploader) ListContainer(ctx context.Context, containerName string) ([]domain.StoredObject, error) {
+	var objects []domain.StoredObject
+	for obj := range s.Client.ListObjects(ctx, containerName, minio.ListObjectsOptions{Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		objects = append(objects, domain.StoredObject{
+			URL:        s.BaseURL + "/" + containerName + "/" + obj.Key,
+			ModifiedAt: obj.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+// OpenFromContainer memanggil Stat lebih dulu karena GetObject minio baru mengembalikan
+// error saat objek pertama kali dibaca.
+func (s *S3Uploader) OpenFromContainer(ctx context.Context, containerName, fileURL string) (io.ReadCloser, error) {
+	blobName := ExtractBlobName(fileURL, containerName)
+	if blobName == "" {
+		return nil, domain.ErrNotFound
+	}
+	obj, err := s.Client.GetObject(ctx, containerName, blobName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, err
+	}
+	return obj, nil
+}
\ No newline at end of file
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+)
+
+// Ed25519Signer menandatangani payload dengan kunci Ed25519. Seed adalah 32 byte yang
+// di-encode base64; app cukup menyimpan public key untuk memverifikasi.
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Signer membuat signer dari seed base64. Seed kosong menghasilkan kunci acak,
+// yang berarti signature berubah setiap kali server restart.
+func NewEd25519Signer(seed string) (*Ed25519Signer, error) {
+	if seed == "" {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &Ed25519Signer{key: key}, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.SeedSize {
+		return nil, errors.New("signing key seed must be 32 bytes")
+	}
+	return &Ed25519Signer{key: ed25519.NewKeyFromSeed(raw)}, nil
+}
+
+func (s *Ed25519Signer) Algorithm() string {
+	return "ed25519"
+}
+
+func (s *Ed25519Signer) Sign(payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload))
+}
+
+func (s *Ed25519Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
\ No newline at end of file